@@ -1,6 +1,7 @@
 package cfclient
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -147,14 +148,14 @@ func (account *Account) GetID() string {
 	return account.ID
 }
 
-func (client *Client) GetAccountByID(id string) (*Account, error) {
+func (client *Client) GetAccountByID(ctx context.Context, id string) (*Account, error) {
 	fullPath := fmt.Sprintf("/admin/accounts/%s", id)
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -170,8 +171,26 @@ func (client *Client) GetAccountByID(id string) (*Account, error) {
 	return &account, nil
 }
 
+// ImpersonateAccount returns a client acting in the given account on behalf of its first admin.
+// It requires a system admin token, allowed to login as any user.
+func (client *Client) ImpersonateAccount(ctx context.Context, accountID string) (*Client, error) {
+	account, err := client.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if len(account.Admins) == 0 {
+		return nil, fmt.Errorf("cannot impersonate account %s - account does not have any admin", account.Name)
+	}
+
+	accountAdminToken, err := client.GetXAccessToken(ctx, account.Admins[0], accountID)
+	if err != nil {
+		return nil, err
+	}
+	return client.withToken(accountAdminToken, "x-access-token"), nil
+}
+
 // GetAccountByName - returns account
-func (client *Client) GetAccountByName(name string) (*Account, error) {
+func (client *Client) GetAccountByName(ctx context.Context, name string) (*Account, error) {
 
 	if name == "" {
 		return nil, fmt.Errorf("GetAccountByName - must specify name param")
@@ -182,7 +201,7 @@ func (client *Client) GetAccountByName(name string) (*Account, error) {
 		QS:     map[string]string{"filter[name]": name},
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -207,14 +226,14 @@ func (client *Client) GetAccountByName(name string) (*Account, error) {
 	return account, nil
 }
 
-func (client *Client) GetAllAccounts() (*[]Account, error) {
+func (client *Client) GetAllAccounts(ctx context.Context) (*[]Account, error) {
 
 	opts := RequestOptions{
 		Path:   "/admin/accounts",
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -230,12 +249,12 @@ func (client *Client) GetAllAccounts() (*[]Account, error) {
 	return &accounts, nil
 }
 
-func (client *Client) GetAccountsList(accountsId []string) (*[]Account, error) {
+func (client *Client) GetAccountsList(ctx context.Context, accountsId []string) (*[]Account, error) {
 
 	var accounts []Account
 
 	for _, accountId := range accountsId {
-		account, err := client.GetAccountByID(accountId)
+		account, err := client.GetAccountByID(ctx, accountId)
 		if err != nil {
 			return nil, err
 		}
@@ -246,7 +265,7 @@ func (client *Client) GetAccountsList(accountsId []string) (*[]Account, error) {
 	return &accounts, nil
 }
 
-func (client *Client) CreateAccount(account *Account) (*Account, error) {
+func (client *Client) CreateAccount(ctx context.Context, account *Account) (*Account, error) {
 
 	body, err := EncodeToJSON(account)
 
@@ -259,7 +278,7 @@ func (client *Client) CreateAccount(account *Account) (*Account, error) {
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -272,21 +291,21 @@ func (client *Client) CreateAccount(account *Account) (*Account, error) {
 		return nil, err
 	}
 
-	err = client.setAccountFeatures(account.Features, &respAccount)
+	err = client.setAccountFeatures(ctx, account.Features, &respAccount)
 	if err != nil {
 		return nil, err
 	}
 	return &respAccount, nil
 }
 
-func (client *Client) UpdateAccount(account *Account) (*Account, error) {
+func (client *Client) UpdateAccount(ctx context.Context, account *Account) (*Account, error) {
 
 	id := account.GetID()
 	if id == "" {
 		return nil, errors.New("[ERROR] Account ID is empty")
 	}
 
-	accountToUpdate, err := client.GetAccountByID(id)
+	accountToUpdate, err := client.GetAccountByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -310,7 +329,7 @@ func (client *Client) UpdateAccount(account *Account) (*Account, error) {
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
 		return nil, err
 	}
@@ -321,7 +340,7 @@ func (client *Client) UpdateAccount(account *Account) (*Account, error) {
 		return nil, err
 	}
 
-	err = client.setAccountFeatures(account.Features, &respAccount)
+	err = client.setAccountFeatures(ctx, account.Features, &respAccount)
 	if err != nil {
 		return nil, err
 	}
@@ -329,7 +348,7 @@ func (client *Client) UpdateAccount(account *Account) (*Account, error) {
 	return &respAccount, nil
 }
 
-func (client *Client) DeleteAccount(id string) error {
+func (client *Client) DeleteAccount(ctx context.Context, id string) error {
 
 	fullPath := fmt.Sprintf("/admin/accounts/%s", id)
 	opts := RequestOptions{
@@ -337,7 +356,7 @@ func (client *Client) DeleteAccount(id string) error {
 		Method: "DELETE",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
@@ -367,7 +386,7 @@ func GetAccountAdminsDiff(desiredAdmins []string, existingAdmins []string) (admi
 }
 
 // Update Features
-func (client *Client) setAccountFeatures(features map[string]bool, account *Account) error {
+func (client *Client) setAccountFeatures(ctx context.Context, features map[string]bool, account *Account) error {
 	id := account.GetID()
 	requestOptions := &RequestOptions{}
 	for k, v := range features {
@@ -379,7 +398,7 @@ func (client *Client) setAccountFeatures(features map[string]bool, account *Acco
 			requestOptions.Path = fmt.Sprintf("/features/switchOff/%s", id)
 			requestOptions.Method = "PUT"
 		}
-		_, err := client.RequestAPI(requestOptions)
+		_, err := client.RequestAPI(ctx, requestOptions)
 		if err != nil {
 			return err
 		}
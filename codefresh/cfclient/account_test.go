@@ -0,0 +1,54 @@
+package cfclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestImpersonateAccount(t *testing.T) {
+	var pipelinesToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/accounts/acc1":
+			_, _ = w.Write([]byte(`{"_id":"acc1","name":"acme","admins":["admin1"]}`))
+		case "/admin/user/loginAsUser":
+			if r.URL.Query().Get("userId") != "admin1" || r.Header.Get("Authorization") != "system-admin-token" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`{"accessToken":"admin1-token"}`))
+		case "/user/changeaccount/acc1":
+			if r.Header.Get("x-access-token") != "admin1-token" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`{"accessToken":"acc1-token"}`))
+		case "/pipelines":
+			pipelinesToken = r.Header.Get("x-access-token")
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, server.URL, "system-admin-token", "")
+	ctx := context.Background()
+
+	accountClient, err := client.ImpersonateAccount(ctx, "acc1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := accountClient.RequestAPI(ctx, &RequestOptions{Path: "/pipelines", Method: "GET"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pipelinesToken != "acc1-token" {
+		t.Fatalf("expected the account scoped token to be used, got %q", pipelinesToken)
+	}
+
+	if _, err := client.ImpersonateAccount(ctx, "missing"); !IsNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
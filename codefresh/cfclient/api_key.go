@@ -1,6 +1,7 @@
 package cfclient
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -38,14 +39,14 @@ type TokenResponse struct {
 	} `json:"user"`
 }
 
-func (client *Client) GetAPIKey(keyID string) (*ApiKey, error) {
+func (client *Client) GetAPIKey(ctx context.Context, keyID string) (*ApiKey, error) {
 
 	opts := RequestOptions{
 		Path:   fmt.Sprintf("/auth/key/%s", keyID),
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -61,14 +62,14 @@ func (client *Client) GetAPIKey(keyID string) (*ApiKey, error) {
 	return &apiKey, nil
 }
 
-func (client *Client) DeleteAPIKey(keyID string) error {
+func (client *Client) DeleteAPIKey(ctx context.Context, keyID string) error {
 
 	opts := RequestOptions{
 		Path:   fmt.Sprintf("/auth/key/%s", keyID),
 		Method: "DELETE",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
 		fmt.Println(string(resp))
 		return err
@@ -77,7 +78,7 @@ func (client *Client) DeleteAPIKey(keyID string) error {
 	return nil
 }
 
-func (client *Client) UpdateAPIKey(key *ApiKey) error {
+func (client *Client) UpdateAPIKey(ctx context.Context, key *ApiKey) error {
 
 	keyID := key.ID
 	if keyID == "" {
@@ -95,7 +96,7 @@ func (client *Client) UpdateAPIKey(key *ApiKey) error {
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		fmt.Println(string(resp))
@@ -106,10 +107,10 @@ func (client *Client) UpdateAPIKey(key *ApiKey) error {
 }
 
 // CreateApiKey - creates api key for account by switch to the user and call /api/auth/keys
-func (client *Client) CreateApiKey(userID string, accountId string, apiKey *ApiKey) (string, error) {
+func (client *Client) CreateApiKey(ctx context.Context, userID string, accountId string, apiKey *ApiKey) (string, error) {
 
 	// Check collaborataros
-	account, err := client.GetAccountByID(accountId)
+	account, err := client.GetAccountByID(ctx, accountId)
 	if err != nil {
 		return "", err
 	}
@@ -119,19 +120,19 @@ func (client *Client) CreateApiKey(userID string, accountId string, apiKey *ApiK
 
 	var xAccessToken string
 	if userID == "" {
-		userID, err = client.createRandomUser(accountId)
+		userID, err = client.createRandomUser(ctx, accountId)
 		if err != nil {
 			return "", err
 		}
 	}
 	// login as user
-	xAccessToken, err = client.GetXAccessToken(userID, accountId)
+	xAccessToken, err = client.GetXAccessToken(ctx, userID, accountId)
 	if err != nil {
 		return "", err
 	}
 
 	// generate token
-	apiToken, err := client.GenerateToken(xAccessToken, apiKey)
+	apiToken, err := client.GenerateToken(ctx, xAccessToken, apiKey)
 	if err != nil {
 		return "", err
 	}
@@ -140,7 +141,7 @@ func (client *Client) CreateApiKey(userID string, accountId string, apiKey *ApiK
 }
 
 // GetXAccessToken
-func (client *Client) GetXAccessToken(userID string, accountId string) (string, error) {
+func (client *Client) GetXAccessToken(ctx context.Context, userID string, accountId string) (string, error) {
 
 	fullPath := fmt.Sprintf("/admin/user/loginAsUser?userId=%s", userID)
 	opts := RequestOptions{
@@ -148,7 +149,7 @@ func (client *Client) GetXAccessToken(userID string, accountId string) (string,
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return "", err
@@ -176,7 +177,7 @@ func (client *Client) GetXAccessToken(userID string, accountId string) (string,
 		XAccessToken: userCfAccessToken,
 	}
 
-	resp, err = client.RequestApiXAccessToken(&opts)
+	resp, err = client.RequestApiXAccessToken(ctx, &opts)
 
 	if err != nil {
 		return "", err
@@ -199,7 +200,7 @@ func (client *Client) GetXAccessToken(userID string, accountId string) (string,
 	return accCfAccessToken, nil
 }
 
-func (client *Client) GenerateToken(xToken string, apiKey *ApiKey) (string, error) {
+func (client *Client) GenerateToken(ctx context.Context, xToken string, apiKey *ApiKey) (string, error) {
 
 	body, err := EncodeToJSON(apiKey)
 	if err != nil {
@@ -213,7 +214,7 @@ func (client *Client) GenerateToken(xToken string, apiKey *ApiKey) (string, erro
 		Body:         body,
 	}
 
-	resp, err := client.RequestApiXAccessToken(&opts)
+	resp, err := client.RequestApiXAccessToken(ctx, &opts)
 
 	if err != nil {
 		return "", err
@@ -222,14 +223,14 @@ func (client *Client) GenerateToken(xToken string, apiKey *ApiKey) (string, erro
 	return string(resp), nil
 }
 
-func (client *Client) GetApiKeysList() ([]ApiKey, error) {
+func (client *Client) GetApiKeysList(ctx context.Context) ([]ApiKey, error) {
 	fullPath := "/auth/keys"
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -245,14 +246,14 @@ func (client *Client) GetApiKeysList() ([]ApiKey, error) {
 	return apiKeys, nil
 }
 
-func (client *Client) GetAPIKeyServiceUser(keyID string, serviceUserId string) (*ApiKey, error) {
+func (client *Client) GetAPIKeyServiceUser(ctx context.Context, keyID string, serviceUserId string) (*ApiKey, error) {
 
 	opts := RequestOptions{
 		Path:   fmt.Sprintf("/auth/key/service-user/%s/%s", serviceUserId, keyID),
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -268,14 +269,14 @@ func (client *Client) GetAPIKeyServiceUser(keyID string, serviceUserId string) (
 	return &apiKey, nil
 }
 
-func (client *Client) DeleteAPIKeyServiceUser(keyID string, serviceUserId string) error {
+func (client *Client) DeleteAPIKeyServiceUser(ctx context.Context, keyID string, serviceUserId string) error {
 
 	opts := RequestOptions{
 		Path:   fmt.Sprintf("/auth/key/service-user/%s/%s", serviceUserId, keyID),
 		Method: "DELETE",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
 		fmt.Println(string(resp))
 		return err
@@ -284,7 +285,7 @@ func (client *Client) DeleteAPIKeyServiceUser(keyID string, serviceUserId string
 	return nil
 }
 
-func (client *Client) UpdateAPIKeyServiceUser(key *ApiKey, serviceUserId string) error {
+func (client *Client) UpdateAPIKeyServiceUser(ctx context.Context, key *ApiKey, serviceUserId string) error {
 
 	keyID := key.ID
 	if keyID == "" {
@@ -302,7 +303,7 @@ func (client *Client) UpdateAPIKeyServiceUser(key *ApiKey, serviceUserId string)
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		fmt.Println(string(resp))
@@ -312,7 +313,7 @@ func (client *Client) UpdateAPIKeyServiceUser(key *ApiKey, serviceUserId string)
 	return nil
 }
 
-func (client *Client) CreateApiKeyServiceUser(serviceUserId string, apiKey *ApiKey) (string, error) {
+func (client *Client) CreateApiKeyServiceUser(ctx context.Context, serviceUserId string, apiKey *ApiKey) (string, error) {
 
 	body, err := EncodeToJSON(apiKey)
 	if err != nil {
@@ -325,7 +326,7 @@ func (client *Client) CreateApiKeyServiceUser(serviceUserId string, apiKey *ApiK
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return "", err
@@ -334,27 +335,27 @@ func (client *Client) CreateApiKeyServiceUser(serviceUserId string, apiKey *ApiK
 	return string(resp), nil
 }
 
-func (client *Client) createRandomUser(accountId string) (string, error) {
+func (client *Client) createRandomUser(ctx context.Context, accountId string) (string, error) {
 	// add user
 	userPrefix := acctest.RandString(10)
 	userName := "tfuser" + userPrefix
 	userEmail := userName + "@codefresh.io"
 
-	user, err := client.AddNewUserToAccount(accountId, userName, userEmail)
+	user, err := client.AddNewUserToAccount(ctx, accountId, userName, userEmail)
 	if err != nil {
 		return "", err
 	}
 	userID := user.ID
 
 	// activate
-	err = client.ActivateUser(userID)
+	err = client.ActivateUser(ctx, userID)
 
 	if err != nil {
 		return "", err
 	}
 
 	// set user as account admin
-	err = client.SetUserAsAccountAdmin(accountId, userID)
+	err = client.SetUserAsAccountAdmin(ctx, accountId, userID)
 	if err != nil {
 		return "", nil
 	}
@@ -0,0 +1,218 @@
+package cfclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Statuses of a build, a build is finished once in one of BuildFinishedStatuses
+const (
+	BuildStatusPending         = "pending"
+	BuildStatusElected         = "elected"
+	BuildStatusRunning         = "running"
+	BuildStatusPendingApproval = "pending-approval"
+	BuildStatusApproved        = "approved"
+	BuildStatusTerminating     = "terminating"
+	BuildStatusSuccess         = "success"
+	BuildStatusError           = "error"
+	BuildStatusTerminated      = "terminated"
+	BuildStatusDenied          = "denied"
+)
+
+var BuildStatuses = []string{
+	BuildStatusPending, BuildStatusElected, BuildStatusRunning, BuildStatusPendingApproval, BuildStatusApproved,
+	BuildStatusTerminating, BuildStatusSuccess, BuildStatusError, BuildStatusTerminated, BuildStatusDenied,
+}
+
+var BuildFinishedStatuses = []string{BuildStatusSuccess, BuildStatusError, BuildStatusTerminated, BuildStatusDenied}
+
+// maxBuildsPageSize is the maximum number of builds requested at once
+const maxBuildsPageSize = 100
+
+// BuildRequest is the body of a request to run a pipeline
+type BuildRequest struct {
+	Branch             string              `json:"branch,omitempty"`
+	Variables          map[string]string   `json:"variables,omitempty"`
+	Options            *TriggerOptions     `json:"options,omitempty"`
+	RuntimeEnvironment *RuntimeEnvironment `json:"runtimeEnvironment,omitempty"`
+}
+
+// PipelineBuild is a build of a pipeline
+type PipelineBuild struct {
+	ID           string      `json:"id"`
+	Status       string      `json:"status"`
+	PipelineID   string      `json:"serviceId,omitempty"`
+	PipelineName string      `json:"serviceName,omitempty"`
+	Branch       string      `json:"branchName,omitempty"`
+	Revision     string      `json:"revision,omitempty"`
+	Trigger      string      `json:"trigger,omitempty"`
+	Created      time.Time   `json:"created"`
+	Started      time.Time   `json:"started"`
+	Finished     time.Time   `json:"finished"`
+	Images       BuildImages `json:"images,omitempty"`
+}
+
+// BuildImages are the names of the images built, the API returns either names or objects with an imageName
+type BuildImages []string
+
+func (images *BuildImages) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*images = make(BuildImages, 0, len(raw))
+	for _, item := range raw {
+		var name string
+		if err := json.Unmarshal(item, &name); err != nil {
+			var image struct {
+				ImageName string `json:"imageName"`
+			}
+			if err := json.Unmarshal(item, &image); err != nil {
+				return err
+			}
+			name = image.ImageName
+		}
+		*images = append(*images, name)
+	}
+	return nil
+}
+
+// BuildsFilter selects the builds returned by GetBuilds, zero fields do not filter
+type BuildsFilter struct {
+	PipelineID string
+	Branch     string
+	Status     string
+	Trigger    string
+	Since      time.Time
+	Until      time.Time
+	// Limit is the maximum number of builds returned, the most recent first
+	Limit int
+}
+
+type buildsPage struct {
+	Workflows struct {
+		Docs  []PipelineBuild `json:"docs"`
+		Pages int             `json:"pages"`
+	} `json:"workflows"`
+}
+
+// IsFinished returns whether the build is in a final status
+func (build *PipelineBuild) IsFinished() bool {
+	return slices.Contains(BuildFinishedStatuses, build.Status)
+}
+
+// RunPipeline starts a build of the pipeline and returns its ID
+func (client *Client) RunPipeline(ctx context.Context, pipelineID string, request *BuildRequest) (string, error) {
+	body, err := EncodeToJSON(request)
+	if err != nil {
+		return "", err
+	}
+
+	fullPath := fmt.Sprintf("/pipelines/run/%s", url.PathEscape(pipelineID))
+	opts := RequestOptions{
+		Path:   fullPath,
+		Method: "POST",
+		Body:   body,
+	}
+
+	resp, err := client.RequestAPI(ctx, &opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to run pipeline %s: \n%w", pipelineID, err)
+	}
+
+	var buildID string
+	err = DecodeResponseInto(resp, &buildID)
+	if err != nil {
+		return "", err
+	}
+
+	return buildID, nil
+}
+
+// GetBuild returns the build with the given ID
+func (client *Client) GetBuild(ctx context.Context, id string) (*PipelineBuild, error) {
+	fullPath := fmt.Sprintf("/builds/%s", url.PathEscape(id))
+	opts := RequestOptions{
+		Path:   fullPath,
+		Method: "GET",
+	}
+
+	resp, err := client.RequestAPI(ctx, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var build PipelineBuild
+	err = DecodeResponseInto(resp, &build)
+	if err != nil {
+		return nil, err
+	}
+
+	return &build, nil
+}
+
+// GetBuilds returns the builds matching filter, the most recent first
+func (client *Client) GetBuilds(ctx context.Context, filter *BuildsFilter) ([]PipelineBuild, error) {
+	qs := map[string]string{
+		"limit": strconv.Itoa(min(filter.Limit, maxBuildsPageSize)),
+	}
+	for key, value := range map[string]string{
+		"pipeline":   filter.PipelineID,
+		"branchName": filter.Branch,
+		"status":     filter.Status,
+		"trigger":    filter.Trigger,
+	} {
+		if value != "" {
+			// ToQS sends the values as given, while branch names and dates may contain reserved characters such as + or &
+			qs[key] = url.QueryEscape(value)
+		}
+	}
+	if !filter.Since.IsZero() {
+		qs["startDate"] = url.QueryEscape(filter.Since.UTC().Format(time.RFC3339))
+	}
+	if !filter.Until.IsZero() {
+		qs["endDate"] = url.QueryEscape(filter.Until.UTC().Format(time.RFC3339))
+	}
+
+	builds := []PipelineBuild{}
+	for page := 1; len(builds) < filter.Limit; page++ {
+		qs["page"] = strconv.Itoa(page)
+		opts := RequestOptions{
+			Path:   "/workflow",
+			Method: "GET",
+			QS:     qs,
+		}
+
+		resp, err := client.RequestAPI(ctx, &opts)
+		if err != nil {
+			return nil, err
+		}
+
+		var result buildsPage
+		err = DecodeResponseInto(resp, &result)
+		if err != nil {
+			return nil, err
+		}
+
+		builds = append(builds, result.Workflows.Docs...)
+		if page >= result.Workflows.Pages || len(result.Workflows.Docs) == 0 {
+			break
+		}
+	}
+
+	if len(builds) > filter.Limit {
+		builds = builds[:filter.Limit]
+	}
+	return builds, nil
+}
+
+// BuildURL returns the URL of the page of the build in the Codefresh UI
+func (client *Client) BuildURL(id string) string {
+	return fmt.Sprintf("%s/build/%s", strings.TrimSuffix(client.Host, "/api"), id)
+}
@@ -0,0 +1,60 @@
+package cfclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGetBuildsPaginatesUpToTheLimit(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		switch r.URL.Query().Get("page") {
+		case "1":
+			_, _ = w.Write([]byte(`{"workflows":{"pages":3,"docs":[
+				{"id":"b3","status":"success","revision":"c0ffee","created":"2024-05-01T10:00:00.000Z","images":["r.io/app:main"]},
+				{"id":"b2","status":"error","images":[{"imageName":"r.io/app:c0ffee"}]}]}}`))
+		case "2":
+			_, _ = w.Write([]byte(`{"workflows":{"pages":3,"docs":[{"id":"b1","status":"success"},{"id":"b0","status":"success"}]}}`))
+		default:
+			t.Errorf("unexpected request of page %s", r.URL.Query().Get("page"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, server.URL, "token", "")
+	builds, err := client.GetBuilds(context.Background(), &BuildsFilter{
+		PipelineID: "p1",
+		Branch:     "feature/a+b&c",
+		Status:     BuildStatusSuccess,
+		Since:      time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		Limit:      3,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ids := []string{}
+	for _, build := range builds {
+		ids = append(ids, build.ID)
+	}
+	if !slices.Equal(ids, []string{"b3", "b2", "b1"}) {
+		t.Fatalf("expected the 3 most recent builds, got %v", ids)
+	}
+	if builds[0].Revision != "c0ffee" || !builds[0].Created.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) || !builds[0].Finished.IsZero() {
+		t.Fatalf("unexpected build %+v", builds[0])
+	}
+	if !slices.Equal(builds[0].Images, BuildImages{"r.io/app:main"}) || !slices.Equal(builds[1].Images, BuildImages{"r.io/app:c0ffee"}) {
+		t.Fatalf("expected the image names to be decoded, got %v and %v", builds[0].Images, builds[1].Images)
+	}
+	expected := url.Values{"limit": {"3"}, "page": {"1"}, "pipeline": {"p1"}, "branchName": {"feature/a+b&c"}, "status": {"success"}, "startDate": {"2024-05-01T00:00:00Z"}}
+	if len(queries) != 2 || !reflect.DeepEqual(queries[0], expected) {
+		t.Fatalf("expected 2 requests starting with %v, got %v", expected, queries)
+	}
+}
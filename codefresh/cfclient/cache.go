@@ -0,0 +1,156 @@
+package cfclient
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is the default duration for which lookups of the current account and of the IDP, team and service user lists are cached
+const DefaultCacheTTL = 30 * time.Second
+
+const (
+	cacheKeyCurrentAccount = "currentAccount"
+	cacheKeyIDPs           = "idps"
+	cacheKeyAccountIDPs    = "accountIdps"
+	cacheKeyTeams          = "teams"
+	cacheKeyServiceUsers   = "serviceUsers"
+)
+
+// cacheInvalidations maps the path prefixes of mutating API calls to the cache entries they make stale
+var cacheInvalidations = []struct {
+	pathPrefix string
+	keys       []string
+}{
+	{"/team", []string{cacheKeyTeams}},
+	{"/service-users", []string{cacheKeyServiceUsers, cacheKeyTeams}},
+	{"/idp", []string{cacheKeyIDPs, cacheKeyAccountIDPs}},
+	{"/admin/idp", []string{cacheKeyIDPs, cacheKeyAccountIDPs}},
+	{"/accounts", []string{cacheKeyCurrentAccount}},
+	{"/admin/accounts", []string{cacheKeyCurrentAccount}},
+	{"/admin/user", []string{cacheKeyCurrentAccount, cacheKeyTeams}},
+	{"/user", []string{cacheKeyCurrentAccount}},
+	{"/features", []string{cacheKeyCurrentAccount}},
+}
+
+// cacheKey identifies a cached lookup. Clients derived from one another share their cache, the scope
+// (the token of the client) keeps apart the lookups whose result depends on the authenticated account.
+type cacheKey struct {
+	scope string
+	name  string
+}
+
+type cacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+type cacheCall struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+// cache is a concurrency-safe, TTL-bound store for API lookups shared by all the resources using a client and the clients derived from it.
+// Concurrent loads of the same key are collapsed into a single API call.
+type cache struct {
+	mu       sync.Mutex
+	entries  map[cacheKey]cacheEntry
+	inflight map[cacheKey]*cacheCall
+	// generation is bumped on every invalidation so that a load started before it is not stored
+	generation uint64
+}
+
+func newCache() *cache {
+	return &cache{
+		entries:  map[cacheKey]cacheEntry{},
+		inflight: map[cacheKey]*cacheCall{},
+	}
+}
+
+// getOrLoad returns the value of key, calling load to fill it when it is missing or expired.
+// Callers waiting for a load started by another caller stop waiting when their own ctx is done,
+// and load the value themselves if the other caller's load was stopped by the end of its context.
+func (c *cache) getOrLoad(ctx context.Context, key cacheKey, ttl time.Duration, load func() (any, error)) (any, error) {
+	for {
+		c.mu.Lock()
+		if ttl <= 0 {
+			c.mu.Unlock()
+			return load()
+		}
+		if entry, ok := c.entries[key]; ok && time.Now().Before(entry.expires) {
+			c.mu.Unlock()
+			return entry.value, nil
+		}
+		if call, ok := c.inflight[key]; ok {
+			c.mu.Unlock()
+			select {
+			case <-call.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			if isContextError(call.err) && ctx.Err() == nil {
+				continue
+			}
+			return call.value, call.err
+		}
+		call := &cacheCall{done: make(chan struct{})}
+		c.inflight[key] = call
+		generation := c.generation
+		c.mu.Unlock()
+
+		call.value, call.err = load()
+
+		c.mu.Lock()
+		delete(c.inflight, key)
+		if call.err == nil && generation == c.generation {
+			c.entries[key] = cacheEntry{value: call.value, expires: time.Now().Add(ttl)}
+		}
+		c.mu.Unlock()
+		close(call.done)
+
+		return call.value, call.err
+	}
+}
+
+// isContextError returns true if err is due to the end of a context
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// invalidate drops the entries with the given names in every scope
+func (c *cache) invalidate(names ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if slices.Contains(names, key.name) {
+			delete(c.entries, key)
+		}
+	}
+	c.generation++
+}
+
+// invalidatePath drops the entries made stale by a mutating call to the given API path
+func (c *cache) invalidatePath(path string) {
+	for _, invalidation := range cacheInvalidations {
+		if path == invalidation.pathPrefix || strings.HasPrefix(path, invalidation.pathPrefix+"/") || strings.HasPrefix(path, invalidation.pathPrefix+"?") {
+			c.invalidate(invalidation.keys...)
+		}
+	}
+}
+
+// cached returns the value stored in the client cache under name for the token of the client, calling load to fill it when it is missing or expired.
+// A zero or negative CacheTTL disables caching.
+func cached[T any](ctx context.Context, client *Client, name string, load func() (T, error)) (T, error) {
+	value, err := client.cache.getOrLoad(ctx, cacheKey{scope: client.Token, name: name}, client.CacheTTL, func() (any, error) {
+		return load()
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return value.(T), nil
+}
@@ -0,0 +1,226 @@
+package cfclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTeamListTestServer(calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/team" {
+			atomic.AddInt32(calls, 1)
+			// leave room for concurrent callers to pile up on the in-flight load
+			time.Sleep(10 * time.Millisecond)
+			_, _ = w.Write([]byte(`[{"_id":"1","name":"developers"}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+}
+
+func TestGetTeamListIsCachedAcrossConcurrentCalls(t *testing.T) {
+	var calls int32
+	server := newTeamListTestServer(&calls)
+	defer server.Close()
+
+	client := NewClient(server.URL, server.URL, "token", "")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetTeamByName(context.Background(), "developers"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestGetTeamListIsInvalidatedByMutatingCalls(t *testing.T) {
+	var calls int32
+	server := newTeamListTestServer(&calls)
+	defer server.Close()
+
+	client := NewClient(server.URL, server.URL, "token", "")
+	ctx := context.Background()
+
+	teams, err := client.GetTeamList(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	teams[0].Name = "modified"
+
+	teams, err = client.GetTeamList(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+	if teams[0].Name != "developers" {
+		t.Fatalf("expected the cached list not to be modified by callers, got %s", teams[0].Name)
+	}
+
+	if _, err := client.RequestAPI(ctx, &RequestOptions{Path: "/team/1", Method: "PUT", Body: []byte(`{}`)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetTeamList(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestGetTeamListIsInvalidatedByMutatingCallsOfDerivedClients(t *testing.T) {
+	var calls int32
+	server := newTeamListTestServer(&calls)
+	defer server.Close()
+
+	client := NewClient(server.URL, server.URL, "token", "")
+	accountAdminClient := client.withToken("account-admin-token", "x-access-token")
+	ctx := context.Background()
+
+	for _, c := range []*Client{client, accountAdminClient, client, accountAdminClient} {
+		if _, err := c.GetTeamList(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected 1 call per token, got %d", calls)
+	}
+
+	if _, err := client.RequestApiXAccessToken(ctx, &RequestOptions{Path: "/team/1", Method: "PUT", Body: []byte(`{}`), XAccessToken: "account-admin-token"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetTeamList(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected a call with an access token to invalidate the cache, got %d calls", calls)
+	}
+
+	if _, err := accountAdminClient.RequestAPI(ctx, &RequestOptions{Path: "/team/1", Method: "PUT", Body: []byte(`{}`)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetTeamList(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 4 {
+		t.Fatalf("expected a call of a derived client to invalidate the cache, got %d calls", calls)
+	}
+}
+
+func TestCacheExpiresEntries(t *testing.T) {
+	c := newCache()
+	loads := 0
+	load := func() (any, error) {
+		loads++
+		return loads, nil
+	}
+
+	_, _ = c.getOrLoad(context.Background(), cacheKey{name: "key"}, time.Hour, load)
+	_, _ = c.getOrLoad(context.Background(), cacheKey{name: "key"}, time.Hour, load)
+	if loads != 1 {
+		t.Fatalf("expected 1 load, got %d", loads)
+	}
+
+	_, _ = c.getOrLoad(context.Background(), cacheKey{name: "other"}, time.Nanosecond, load)
+	time.Sleep(time.Millisecond)
+	_, _ = c.getOrLoad(context.Background(), cacheKey{name: "other"}, time.Nanosecond, load)
+	if loads != 3 {
+		t.Fatalf("expected an expired entry to be loaded again, got %d loads", loads)
+	}
+
+	_, _ = c.getOrLoad(context.Background(), cacheKey{name: "disabled"}, 0, load)
+	_, _ = c.getOrLoad(context.Background(), cacheKey{name: "disabled"}, 0, load)
+	if loads != 5 {
+		t.Fatalf("expected a zero TTL to disable caching, got %d loads", loads)
+	}
+}
+
+func TestCacheInvalidatePath(t *testing.T) {
+	c := newCache()
+	load := func() (any, error) { return true, nil }
+	for _, key := range []string{cacheKeyCurrentAccount, cacheKeyTeams, cacheKeyServiceUsers} {
+		_, _ = c.getOrLoad(context.Background(), cacheKey{name: key}, time.Hour, load)
+	}
+
+	c.invalidatePath("/service-users/1")
+	c.invalidatePath("/teams-unrelated")
+
+	if _, ok := c.entries[cacheKey{name: cacheKeyCurrentAccount}]; !ok {
+		t.Fatal("expected the current account to stay cached")
+	}
+	for _, key := range []string{cacheKeyTeams, cacheKeyServiceUsers} {
+		if _, ok := c.entries[cacheKey{name: key}]; ok {
+			t.Fatalf("expected %s to be invalidated", key)
+		}
+	}
+}
+
+func TestCacheWaitersStopWhenTheirContextIsDone(t *testing.T) {
+	c := newCache()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	go func() {
+		_, _ = c.getOrLoad(context.Background(), cacheKey{name: "key"}, time.Hour, func() (any, error) {
+			close(started)
+			<-release
+			return true, nil
+		})
+	}()
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := c.getOrLoad(ctx, cacheKey{name: "key"}, time.Hour, func() (any, error) {
+		t.Fatal("expected the waiter not to load the value itself")
+		return nil, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestCacheWaitersLoadAgainWhenTheLoadingContextIsDone(t *testing.T) {
+	c := newCache()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		_, _ = c.getOrLoad(context.Background(), cacheKey{name: "key"}, time.Hour, func() (any, error) {
+			close(started)
+			<-release
+			return nil, context.Canceled
+		})
+	}()
+	<-started
+
+	result := make(chan error)
+	go func() {
+		value, err := c.getOrLoad(context.Background(), cacheKey{name: "key"}, time.Hour, func() (any, error) { return "loaded", nil })
+		if err == nil && value != "loaded" {
+			err = fmt.Errorf("unexpected value %v", value)
+		}
+		result <- err
+	}()
+	close(release)
+
+	if err := <-result; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -2,21 +2,39 @@ package cfclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
+	"time"
 )
 
+// DefaultRequestTimeout bounds a single HTTP exchange with the Codefresh API, the overall operation is bounded by the caller's context
+const DefaultRequestTimeout = 2 * time.Minute
+
 // Client token, host, htpp.Client
 type Client struct {
-	Token        string
-	TokenHeader  string
-	Host         string
-	HostV2       string
-	featureFlags map[string]bool
-	Client       *http.Client
+	Token       string
+	TokenHeader string
+	Host        string
+	HostV2      string
+	Client      *http.Client
+	// CacheTTL is the duration for which the current account and the IDP, team and service user lists are cached, 0 disables caching
+	CacheTTL time.Duration
+	cache    *cache
+	// RetryMaxAttempts is the maximum number of attempts made for a single API call, 1 disables retries
+	RetryMaxAttempts int
+	// RetryMinBackoff is the initial wait between two attempts
+	RetryMinBackoff time.Duration
+	// RetryMaxBackoff is the maximum wait between two attempts, including waits requested by Retry-After
+	RetryMaxBackoff time.Duration
+	// MaxRequestsPerSecond is the rate at which requests, including retries, are sent to each of Host and HostV2, 0 disables rate limiting
+	MaxRequestsPerSecond float64
+	hostLimiter          *rateLimiter
+	hostV2Limiter        *rateLimiter
+	// ReadOnly rejects every request that could change data: REST calls other than GET and GraphQL mutations
+	ReadOnly bool
 }
 
 // RequestOptions  path, method, etc
@@ -36,103 +54,134 @@ func NewClient(hostname string, hostnameV2 string, token string, tokenHeader str
 		tokenHeader = "Authorization"
 	}
 	return &Client{
-		Host:         hostname,
-		HostV2:       hostnameV2,
-		Token:        token,
-		TokenHeader:  tokenHeader,
-		Client:       &http.Client{},
-		featureFlags: map[string]bool{},
+		Host:                 hostname,
+		HostV2:               hostnameV2,
+		Token:                token,
+		TokenHeader:          tokenHeader,
+		Client:               &http.Client{Timeout: DefaultRequestTimeout},
+		CacheTTL:             DefaultCacheTTL,
+		cache:                newCache(),
+		RetryMaxAttempts:     DefaultRetryMaxAttempts,
+		RetryMinBackoff:      DefaultRetryMinBackoff,
+		RetryMaxBackoff:      DefaultRetryMaxBackoff,
+		MaxRequestsPerSecond: DefaultMaxRequestsPerSecond,
+		hostLimiter:          newRateLimiter(),
+		hostV2Limiter:        newRateLimiter(),
 	}
 
 }
 
-// RequestAPI http request to Codefresh API
-func (client *Client) RequestAPI(opt *RequestOptions) ([]byte, error) {
-	finalURL := fmt.Sprintf("%s%s", client.Host, opt.Path)
-	if opt.QS != nil {
-		finalURL += ToQS(opt.QS)
-	}
-	request, err := http.NewRequest(opt.Method, finalURL, bytes.NewBuffer(opt.Body))
-	if err != nil {
-		return nil, err
-	}
-
-	tokenHeader := client.TokenHeader
-	if tokenHeader == "" {
-		tokenHeader = "Authorization"
+// WrapTransport replaces the transport of the client with the one returned by wrap, which is given the current transport to delegate to.
+// It is the hook used to observe, record or stub the requests sent to the Codefresh APIs, including those of impersonated clients.
+func (client *Client) WrapTransport(wrap func(next http.RoundTripper) http.RoundTripper) {
+	next := client.Client.Transport
+	if next == nil {
+		next = http.DefaultTransport
 	}
-	request.Header.Set(tokenHeader, client.Token)
-	request.Header.Set("Content-Type", "application/json; charset=utf-8")
+	client.Client.Transport = wrap(next)
+}
 
-	resp, err := client.Client.Do(request)
+// withToken returns a copy of the client, sharing its HTTP, retry, rate limiting and cache settings, that authenticates with another token
+func (client *Client) withToken(token string, tokenHeader string) *Client {
+	newClient := NewClient(client.Host, client.HostV2, token, tokenHeader)
+	newClient.Client = client.Client
+	newClient.RetryMaxAttempts = client.RetryMaxAttempts
+	newClient.RetryMinBackoff = client.RetryMinBackoff
+	newClient.RetryMaxBackoff = client.RetryMaxBackoff
+	newClient.CacheTTL = client.CacheTTL
+	newClient.cache = client.cache
+	newClient.MaxRequestsPerSecond = client.MaxRequestsPerSecond
+	newClient.hostLimiter = client.hostLimiter
+	newClient.hostV2Limiter = client.hostV2Limiter
+	newClient.ReadOnly = client.ReadOnly
+	return newClient
+}
 
+// RequestAPI http request to Codefresh API
+func (client *Client) RequestAPI(ctx context.Context, opt *RequestOptions) ([]byte, error) {
+	resp, body, err := client.requestREST(ctx, opt, func(request *http.Request) {
+		tokenHeader := client.TokenHeader
+		if tokenHeader == "" {
+			tokenHeader = "Authorization"
+		}
+		request.Header.Set(tokenHeader, client.Token)
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to read body %v %v", resp.StatusCode, resp.Status)
-	}
 
 	// todo: maybe other 2**?
 	if resp.StatusCode != 200 && resp.StatusCode != 201 {
-		return nil, fmt.Errorf("%v, %s", resp.Status, string(body))
+		return nil, newAPIError(resp, opt.Method, opt.Path, body)
 	}
 	return body, nil
 }
 
-func (client *Client) RequestApiXAccessToken(opt *RequestOptions) ([]byte, error) {
-	finalURL := fmt.Sprintf("%s%s", client.Host, opt.Path)
-	if opt.QS != nil {
-		finalURL += ToQS(opt.QS)
-	}
-	request, err := http.NewRequest(opt.Method, finalURL, bytes.NewBuffer(opt.Body))
+func (client *Client) RequestApiXAccessToken(ctx context.Context, opt *RequestOptions) ([]byte, error) {
+	resp, body, err := client.requestREST(ctx, opt, func(request *http.Request) {
+		request.Header.Set("x-access-token", opt.XAccessToken)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	request.Header.Set("x-access-token", opt.XAccessToken)
-	request.Header.Set("Content-Type", "application/json; charset=utf-8")
-
-	resp, err := client.Client.Do(request)
+	if resp.StatusCode != 200 {
+		return nil, newAPIError(resp, opt.Method, opt.Path, body)
+	}
+	return body, nil
+}
 
-	if err != nil {
-		return nil, err
+// requestREST sends a request to the REST API, authenticated by authenticate, and drops the cache entries a mutating request makes stale
+func (client *Client) requestREST(ctx context.Context, opt *RequestOptions, authenticate func(request *http.Request)) (*http.Response, []byte, error) {
+	if client.ReadOnly && opt.Method != http.MethodGet {
+		return nil, nil, readOnlyError(opt.Method, opt.Path)
+	}
+	finalURL := fmt.Sprintf("%s%s", client.Host, opt.Path)
+	if opt.QS != nil {
+		finalURL += ToQS(opt.QS)
 	}
-	defer resp.Body.Close()
+	resp, body, err := client.do(ctx, client.hostLimiter, func() (*http.Request, error) {
+		request, err := http.NewRequestWithContext(ctx, opt.Method, finalURL, bytes.NewBuffer(opt.Body))
+		if err != nil {
+			return nil, err
+		}
 
-	body, err := io.ReadAll(resp.Body)
+		authenticate(request)
+		request.Header.Set("Content-Type", "application/json; charset=utf-8")
+		return request, nil
+	}, false)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to read body %v %v", resp.StatusCode, resp.Status)
+		return nil, nil, err
 	}
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("%v, %s", resp.Status, string(body))
+	if opt.Method != http.MethodGet {
+		client.cache.invalidatePath(opt.Path)
 	}
-	return body, nil
+	return resp, body, nil
 }
 
-func (client *Client) isFeatureFlagEnabled(flagName string) (bool, error) {
+func (client *Client) isFeatureFlagEnabled(ctx context.Context, flagName string) (bool, error) {
 
-	if len(client.featureFlags) == 0 {
-		currAcc, err := client.GetCurrentAccount()
-
-		if err != nil {
-			return false, err
-		}
-
-		client.featureFlags = currAcc.FeatureFlags
+	currAcc, err := client.GetCurrentAccount(ctx)
+	if err != nil {
+		return false, err
 	}
 
-	if val, ok := client.featureFlags[flagName]; ok {
+	if val, ok := currAcc.FeatureFlags[flagName]; ok {
 		return val, nil
 	}
 
 	return false, nil
 }
 
+// decryptVariablesQS returns the query string asking for the values of encrypted variables, or nil if the account forbids decryption
+func (client *Client) decryptVariablesQS(ctx context.Context) map[string]string {
+	if forbidDecrypt, err := client.isFeatureFlagEnabled(ctx, "forbidDecrypt"); err == nil && forbidDecrypt {
+		return nil
+	}
+	return map[string]string{"decryptVariables": "true"}
+}
+
 // ToQS add extra parameters to path
 func ToQS(qs map[string]string) string {
 	var arr = []string{}
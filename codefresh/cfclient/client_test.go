@@ -0,0 +1,22 @@
+package cfclient
+
+import (
+	"testing"
+)
+
+func TestToQSSendsValuesAsGiven(t *testing.T) {
+	// the values of the query strings built by the clients, some of them already escaped, are sent unchanged
+	for _, testCase := range []struct {
+		qs       map[string]string
+		expected string
+	}{
+		{map[string]string{"filter[name]": "my-account"}, "?filter[name]=my-account"},
+		{map[string]string{"decryptVariables": "true"}, "?decryptVariables=true"},
+		{map[string]string{"limit": "10000"}, "?limit=10000"},
+		{map[string]string{"startDate": "2024-05-01T00%3A00%3A00Z"}, "?startDate=2024-05-01T00%3A00%3A00Z"},
+	} {
+		if actual := ToQS(testCase.qs); actual != testCase.expected {
+			t.Errorf("expected %s, got %s", testCase.expected, actual)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package cfclient
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/url"
@@ -40,10 +41,10 @@ func (context *Context) GetID() string {
 	return context.Metadata.Name
 }
 
-func (client *Client) GetContext(name string) (*Context, error) {
+func (client *Client) GetContext(ctx context.Context, name string) (*Context, error) {
 	fullPath := fmt.Sprintf("/contexts/%s", url.PathEscape(name))
 
-	forbidDecrypt, err := client.isFeatureFlagEnabled("forbidDecrypt")
+	forbidDecrypt, err := client.isFeatureFlagEnabled(ctx, "forbidDecrypt")
 
 	if err != nil {
 		forbidDecrypt = false
@@ -58,7 +59,7 @@ func (client *Client) GetContext(name string) (*Context, error) {
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -82,7 +83,28 @@ func (client *Client) GetContext(name string) (*Context, error) {
 	return &respContext, nil
 }
 
-func (client *Client) CreateContext(context *Context) (*Context, error) {
+// GetContexts returns the contexts of the account, without decrypting them
+func (client *Client) GetContexts(ctx context.Context) ([]Context, error) {
+	opts := RequestOptions{
+		Path:   "/contexts",
+		Method: "GET",
+	}
+
+	resp, err := client.RequestAPI(ctx, &opts)
+
+	if err != nil {
+		return nil, err
+	}
+	var contexts []Context
+	err = DecodeResponseInto(resp, &contexts)
+	if err != nil {
+		return nil, err
+	}
+
+	return contexts, nil
+}
+
+func (client *Client) CreateContext(ctx context.Context, context *Context) (*Context, error) {
 
 	body, err := EncodeToJSON(context)
 
@@ -95,7 +117,7 @@ func (client *Client) CreateContext(context *Context) (*Context, error) {
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		log.Printf("[DEBUG] Call to API for context creation failed with Error = %v for Body %v", err, body)
@@ -112,7 +134,7 @@ func (client *Client) CreateContext(context *Context) (*Context, error) {
 
 }
 
-func (client *Client) UpdateContext(context *Context) (*Context, error) {
+func (client *Client) UpdateContext(ctx context.Context, context *Context) (*Context, error) {
 
 	body, err := EncodeToJSON(context)
 
@@ -127,7 +149,7 @@ func (client *Client) UpdateContext(context *Context) (*Context, error) {
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -143,7 +165,7 @@ func (client *Client) UpdateContext(context *Context) (*Context, error) {
 
 }
 
-func (client *Client) DeleteContext(name string) error {
+func (client *Client) DeleteContext(ctx context.Context, name string) error {
 
 	fullPath := fmt.Sprintf("/contexts/%s", url.PathEscape(name))
 	opts := RequestOptions{
@@ -151,7 +173,7 @@ func (client *Client) DeleteContext(name string) error {
 		Method: "DELETE",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
@@ -1,8 +1,10 @@
 package cfclient
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 
 	"github.com/stretchr/objx"
 	"slices"
@@ -26,10 +28,28 @@ type CurrentAccount struct {
 }
 
 // GetCurrentAccount -
-func (client *Client) GetCurrentAccount() (*CurrentAccount, error) {
+func (client *Client) GetCurrentAccount(ctx context.Context) (*CurrentAccount, error) {
+	currentAccount, err := cached(ctx, client, cacheKeyCurrentAccount, func() (*CurrentAccount, error) {
+		return client.getCurrentAccount(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// callers get their own copy so that the cached account can't be modified
+	return &CurrentAccount{
+		ID:           currentAccount.ID,
+		Name:         currentAccount.Name,
+		Users:        slices.Clone(currentAccount.Users),
+		Admins:       slices.Clone(currentAccount.Admins),
+		FeatureFlags: maps.Clone(currentAccount.FeatureFlags),
+	}, nil
+}
+
+func (client *Client) getCurrentAccount(ctx context.Context) (*CurrentAccount, error) {
 
 	// get and parse current account
-	userResp, err := client.RequestAPI(&RequestOptions{
+	userResp, err := client.RequestAPI(ctx, &RequestOptions{
 		Path:   "/user",
 		Method: "GET",
 	})
@@ -77,7 +97,7 @@ func (client *Client) GetCurrentAccount() (*CurrentAccount, error) {
 	}
 
 	// get and parse account users
-	accountUsersResp, err := client.RequestAPI(&RequestOptions{
+	accountUsersResp, err := client.RequestAPI(ctx, &RequestOptions{
 		Path:   fmt.Sprintf("/accounts/%s/users", currentAccount.ID),
 		Method: "GET",
 	})
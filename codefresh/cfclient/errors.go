@@ -0,0 +1,107 @@
+package cfclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrNotFound is matched by errors returned when a looked up object does not exist,
+// either because the API answered 404 or because it is missing from a list returned by the API
+var ErrNotFound = errors.New("not found")
+
+// ErrReadOnly is returned when a read-only client is asked to send a request that could change data
+var ErrReadOnly = errors.New("read-only mode")
+
+func readOnlyError(method string, path string) error {
+	return fmt.Errorf("%w: refusing to send %s %s", ErrReadOnly, method, path)
+}
+
+// APIError is returned when the Codefresh API answers with an unexpected status code
+type APIError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Status is the HTTP status line of the response, e.g. "404 Not Found"
+	Status string
+	// Method is the HTTP method of the request
+	Method string
+	// Path is the path of the request, relative to the API URL
+	Path string
+	// Response is the parsed error body, nil if the body is not a Codefresh error object
+	Response *ErrorResponse
+	// Body is the raw response body
+	Body string
+}
+
+func newAPIError(resp *http.Response, method string, path string, body []byte) *APIError {
+	apiError := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Method:     method,
+		Path:       path,
+		Body:       string(body),
+	}
+
+	var errorResponse ErrorResponse
+	if err := json.Unmarshal(body, &errorResponse); err == nil && (errorResponse.Message != "" || errorResponse.Error != "") {
+		apiError.Response = &errorResponse
+	}
+
+	return apiError
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%v, %s", e.Status, e.Body)
+}
+
+// Is makes errors.Is(err, ErrNotFound) true for 404 responses
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+// notFoundError is returned by lookups that search a list returned by the API
+type notFoundError struct {
+	message string
+}
+
+func notFoundf(format string, args ...any) error {
+	return &notFoundError{message: fmt.Sprintf(format, args...)}
+}
+
+func (e *notFoundError) Error() string {
+	return e.message
+}
+
+func (e *notFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// statusCode returns the HTTP status code carried by err, 0 if err is not an APIError
+func statusCode(err error) int {
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound returns true if err means that the requested object does not exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsForbidden returns true if err is a 403 response of the API
+func IsForbidden(err error) bool {
+	return statusCode(err) == http.StatusForbidden
+}
+
+// IsUnauthorized returns true if err is a 401 response of the API
+func IsUnauthorized(err error) bool {
+	return statusCode(err) == http.StatusUnauthorized
+}
+
+// IsConflict returns true if err is a 409 response of the API
+func IsConflict(err error) bool {
+	return statusCode(err) == http.StatusConflict
+}
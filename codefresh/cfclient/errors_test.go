@@ -0,0 +1,91 @@
+package cfclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestAPIReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"status":404,"message":"Pipeline not found","error":"Not Found"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, server.URL, "token", "")
+	_, err := client.RequestAPI(context.Background(), &RequestOptions{Path: "/pipelines/missing", Method: "GET"})
+
+	var apiError *APIError
+	if !errors.As(err, &apiError) {
+		t.Fatalf("expected an APIError, got %T: %v", err, err)
+	}
+	if apiError.StatusCode != http.StatusNotFound || apiError.Path != "/pipelines/missing" || apiError.Method != "GET" {
+		t.Fatalf("unexpected APIError %+v", apiError)
+	}
+	if apiError.Response == nil || apiError.Response.Message != "Pipeline not found" {
+		t.Fatalf("expected the error body to be parsed, got %+v", apiError.Response)
+	}
+	if !IsNotFound(err) || IsForbidden(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+	if !IsNotFound(fmt.Errorf("wrapped: %w", err)) {
+		t.Fatal("expected a wrapped APIError to be detected as not found")
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	cases := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"nil":             {nil, false},
+		"plain error":     {errors.New("404 Not Found"), false},
+		"forbidden":       {&APIError{StatusCode: http.StatusForbidden}, false},
+		"not found":       {&APIError{StatusCode: http.StatusNotFound}, true},
+		"missing in list": {notFoundf("[ERROR] IDP with ID %s isn't found.", "id"), true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if actual := IsNotFound(c.err); actual != c.expected {
+				t.Fatalf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestReadOnlyClientRejectsMutations(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, server.URL, "token", "")
+	client.ReadOnly = true
+	ctx := context.Background()
+
+	if _, err := client.RequestAPI(ctx, &RequestOptions{Path: "/pipelines", Method: "GET"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.SendGqlRequest(ctx, GraphQLRequest{Query: "query AccountInfo { me { id } }"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.RequestAPI(ctx, &RequestOptions{Path: "/pipelines/p", Method: "DELETE"}); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected %v, got %v", ErrReadOnly, err)
+	}
+	if _, err := client.RequestApiXAccessToken(ctx, &RequestOptions{Path: "/auth/key", Method: "POST"}); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected %v, got %v", ErrReadOnly, err)
+	}
+	if _, err := client.SendGqlRequest(ctx, GraphQLRequest{Query: "mutation RemoveAbacRule { removeAbacRule }"}); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected %v, got %v", ErrReadOnly, err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected only the reads to be sent, got %d calls", calls)
+	}
+}
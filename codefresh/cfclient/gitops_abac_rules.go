@@ -1,6 +1,7 @@
 package cfclient
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -35,7 +36,7 @@ type GitopsAbacRuleResponse struct {
 	} `json:"data"`
 }
 
-func (client *Client) GetAbacRulesList(entityType string) ([]GitopsAbacRule, error) {
+func (client *Client) GetAbacRulesList(ctx context.Context, entityType string) ([]GitopsAbacRule, error) {
 	request := GraphQLRequest{
 		Query: `
 			query AbacRules($accountId: String!, $entityType: AbacEntityValues!) {
@@ -60,7 +61,7 @@ func (client *Client) GetAbacRulesList(entityType string) ([]GitopsAbacRule, err
 		},
 	}
 
-	response, err := client.SendGqlRequest(request)
+	response, err := client.SendGqlRequest(ctx, request)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return nil, err
@@ -76,7 +77,7 @@ func (client *Client) GetAbacRulesList(entityType string) ([]GitopsAbacRule, err
 }
 
 // GetAbacRuleByID -
-func (client *Client) GetAbacRuleByID(id string) (*GitopsAbacRule, error) {
+func (client *Client) GetAbacRuleByID(ctx context.Context, id string) (*GitopsAbacRule, error) {
 	request := GraphQLRequest{
 		Query: `
 			query AbacRule($accountId: String!, $id: ID!) {
@@ -101,7 +102,7 @@ func (client *Client) GetAbacRuleByID(id string) (*GitopsAbacRule, error) {
 		},
 	}
 
-	response, err := client.SendGqlRequest(request)
+	response, err := client.SendGqlRequest(ctx, request)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return nil, err
@@ -116,7 +117,7 @@ func (client *Client) GetAbacRuleByID(id string) (*GitopsAbacRule, error) {
 	return &gitopsAbacRuleResponse.Data.AbacRule, nil
 }
 
-func (client *Client) CreateAbacRule(gitopsAbacRule *GitopsAbacRule) (*GitopsAbacRule, error) {
+func (client *Client) CreateAbacRule(ctx context.Context, gitopsAbacRule *GitopsAbacRule) (*GitopsAbacRule, error) {
 
 	newAbacRule := &GitopsAbacRule{
 		EntityType: gitopsAbacRule.EntityType,
@@ -150,7 +151,7 @@ func (client *Client) CreateAbacRule(gitopsAbacRule *GitopsAbacRule) (*GitopsAba
 		},
 	}
 
-	response, err := client.SendGqlRequest(request)
+	response, err := client.SendGqlRequest(ctx, request)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return nil, err
@@ -165,7 +166,7 @@ func (client *Client) CreateAbacRule(gitopsAbacRule *GitopsAbacRule) (*GitopsAba
 	return &gitopsAbacRuleResponse.Data.CreateAbacRule, nil
 }
 
-func (client *Client) DeleteAbacRule(id string) (*GitopsAbacRule, error) {
+func (client *Client) DeleteAbacRule(ctx context.Context, id string) (*GitopsAbacRule, error) {
 	request := GraphQLRequest{
 		Query: `
 			mutation RemoveAbacRule($accountId: String!, $id: ID!) {
@@ -190,7 +191,7 @@ func (client *Client) DeleteAbacRule(id string) (*GitopsAbacRule, error) {
 		},
 	}
 
-	response, err := client.SendGqlRequest(request)
+	response, err := client.SendGqlRequest(ctx, request)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return nil, err
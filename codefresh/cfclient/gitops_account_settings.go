@@ -1,6 +1,7 @@
 package cfclient
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -21,7 +22,7 @@ type GitopsActiveAccountInfo struct {
 	Admins           []string `json:"admins,omitempty"`
 }
 
-func (client *Client) GetActiveGitopsAccountInfo() (*GitopsActiveAccountInfo, error) {
+func (client *Client) GetActiveGitopsAccountInfo(ctx context.Context) (*GitopsActiveAccountInfo, error) {
 	request := GraphQLRequest{
 		Query: `
 			query AccountInfo {
@@ -39,7 +40,7 @@ func (client *Client) GetActiveGitopsAccountInfo() (*GitopsActiveAccountInfo, er
 		`,
 	}
 
-	response, err := client.SendGqlRequest(request)
+	response, err := client.SendGqlRequest(ctx, request)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return nil, err
@@ -58,7 +59,7 @@ func (client *Client) GetActiveGitopsAccountInfo() (*GitopsActiveAccountInfo, er
 	return &gitopsActiveAccountInfo, nil
 }
 
-func (client *Client) UpdateActiveGitopsAccountSettings(gitProvider string, gitProviderApiUrl string, sharedConfigRepo string) error {
+func (client *Client) UpdateActiveGitopsAccountSettings(ctx context.Context, gitProvider string, gitProviderApiUrl string, sharedConfigRepo string) error {
 	request := GraphQLRequest{
 		Query: `
 			mutation updateCsdpSettings($gitProvider: GitProviders!, $gitApiUrl: String!, $sharedConfigRepo: String!) {
@@ -72,7 +73,7 @@ func (client *Client) UpdateActiveGitopsAccountSettings(gitProvider string, gitP
 		},
 	}
 
-	_, err := client.SendGqlRequest(request)
+	_, err := client.SendGqlRequest(ctx, request)
 
 	if err != nil {
 		fmt.Println("Error:", err)
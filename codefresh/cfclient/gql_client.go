@@ -2,10 +2,10 @@ package cfclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
-	"io"
 	"net/http"
+	"strings"
 )
 
 // GraphQLRequest GraphQL query
@@ -14,42 +14,44 @@ type GraphQLRequest struct {
 	Variables map[string]interface{} `json:"variables,omitempty"`
 }
 
-func (client *Client) SendGqlRequest(request GraphQLRequest) ([]byte, error) {
+func (client *Client) SendGqlRequest(ctx context.Context, request GraphQLRequest) ([]byte, error) {
+	if client.ReadOnly && isGqlMutation(request.Query) {
+		return nil, readOnlyError("POST", "GraphQL mutation")
+	}
 	jsonRequest, err := json.Marshal(request)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", client.HostV2, bytes.NewBuffer(jsonRequest))
-	if err != nil {
-		return nil, err
-	}
-
-	tokenHeader := client.TokenHeader
-	if tokenHeader == "" {
-		tokenHeader = "Authorization"
-	}
-	req.Header.Set(tokenHeader, client.Token)
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-
-	httpClient := &http.Client{}
-	resp, err := httpClient.Do(req)
+	// Queries only read data and can be retried, mutations are retried only when rate limited
+	retryable := !isGqlMutation(request.Query)
+	resp, body, err := client.do(ctx, client.hostV2Limiter, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", client.HostV2, bytes.NewBuffer(jsonRequest))
+		if err != nil {
+			return nil, err
+		}
+
+		tokenHeader := client.TokenHeader
+		if tokenHeader == "" {
+			tokenHeader = "Authorization"
+		}
+		req.Header.Set(tokenHeader, client.Token)
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+		return req, nil
+	}, retryable)
 	if err != nil {
 		return nil, err
 	}
 	if resp.StatusCode >= 400 {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, errors.New(resp.Status + " " + string(bodyBytes))
+		return nil, newAPIError(resp, "POST", resp.Request.URL.Path, body)
 	}
-	defer resp.Body.Close()
 
-	var buf bytes.Buffer
-	_, err = buf.ReadFrom(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+	return body, nil
+}
 
-	return buf.Bytes(), nil
+// isGqlMutation returns true if the GraphQL operation is a mutation
+func isGqlMutation(query string) bool {
+	return strings.HasPrefix(strings.TrimSpace(query), "mutation")
 }
 
 func DecodeGraphQLResponseInto(body []byte, target interface{}) error {
@@ -1,6 +1,7 @@
 package cfclient
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -18,7 +19,7 @@ type EventData struct {
 	Secret  string `json:"secret"`
 }
 
-func (client *Client) GetHermesTriggerByEventAndPipeline(event string, pipeline string) (*HermesTrigger, error) {
+func (client *Client) GetHermesTriggerByEventAndPipeline(ctx context.Context, event string, pipeline string) (*HermesTrigger, error) {
 
 	fullPath := fmt.Sprintf("/hermes/triggers/event/%s", UriEncodeEvent(event))
 	opts := RequestOptions{
@@ -26,7 +27,7 @@ func (client *Client) GetHermesTriggerByEventAndPipeline(event string, pipeline
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
 		return nil, err
 	}
@@ -45,13 +46,13 @@ func (client *Client) GetHermesTriggerByEventAndPipeline(event string, pipeline
 		}
 	}
 	if hermesTrigger.Event == "" {
-		return nil, fmt.Errorf("no Trigger found for event: %s, pipeline: %s", event, pipeline)
+		return nil, notFoundf("no Trigger found for event: %s, pipeline: %s", event, pipeline)
 	}
 
 	return &hermesTrigger, nil
 }
 
-func (client *Client) CreateHermesTriggerByEventAndPipeline(event string, pipeline string) error {
+func (client *Client) CreateHermesTriggerByEventAndPipeline(ctx context.Context, event string, pipeline string) error {
 
 	fullPath := fmt.Sprintf("/hermes/triggers/%s/%s", UriEncodeEvent(event), pipeline)
 	opts := RequestOptions{
@@ -59,21 +60,21 @@ func (client *Client) CreateHermesTriggerByEventAndPipeline(event string, pipeli
 		Method: "POST",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 	return err
 }
 
-func (client *Client) DeleteHermesTriggerByEventAndPipeline(event string, pipeline string) error {
+func (client *Client) DeleteHermesTriggerByEventAndPipeline(ctx context.Context, event string, pipeline string) error {
 	fullPath := fmt.Sprintf("/hermes/triggers/%s/%s", UriEncodeEvent(event), pipeline)
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "DELETE",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
-		return fmt.Errorf("failed to delete Trigger: \n%v", err)
+		return fmt.Errorf("failed to delete Trigger: \n%w", err)
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package cfclient
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -10,9 +11,13 @@ type HermesTriggerEvent struct {
 	Filter string            `json:"filter,omitempty"`
 	Secret string            `json:"secret,omitempty"`
 	Values map[string]string `json:"values,omitempty"`
+	// Uri, Account and Endpoint are computed by Codefresh, Endpoint is the URL the external system must call
+	Uri      string `json:"uri,omitempty"`
+	Account  string `json:"account,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
 }
 
-func (client *Client) GetHermesTriggerEvent(event string) (*HermesTriggerEvent, error) {
+func (client *Client) GetHermesTriggerEvent(ctx context.Context, event string) (*HermesTriggerEvent, error) {
 	fullPath := fmt.Sprintf("/hermes/triggers/%s", UriEncodeEvent(event))
 
 	opts := RequestOptions{
@@ -20,9 +25,9 @@ func (client *Client) GetHermesTriggerEvent(event string) (*HermesTriggerEvent,
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve Trigger Event: \n%v", err)
+		return nil, fmt.Errorf("failed to retrieve Trigger Event: \n%w", err)
 	}
 
 	var hermesTriggerEvent HermesTriggerEvent
@@ -34,7 +39,7 @@ func (client *Client) GetHermesTriggerEvent(event string) (*HermesTriggerEvent,
 	return &hermesTriggerEvent, nil
 }
 
-func (client *Client) CreateHermesTriggerEvent(event *HermesTriggerEvent) (string, error) {
+func (client *Client) CreateHermesTriggerEvent(ctx context.Context, event *HermesTriggerEvent) (string, error) {
 
 	body, err := EncodeToJSON(event)
 	if err != nil {
@@ -48,7 +53,7 @@ func (client *Client) CreateHermesTriggerEvent(event *HermesTriggerEvent) (strin
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
 		return "", fmt.Errorf("failed to create Trigger Event: \n%v", err)
 	}
@@ -61,3 +66,18 @@ func (client *Client) CreateHermesTriggerEvent(event *HermesTriggerEvent) (strin
 
 	return eventString, err
 }
+
+func (client *Client) DeleteHermesTriggerEvent(ctx context.Context, event string) error {
+	fullPath := fmt.Sprintf("/hermes/events/%s", UriEncodeEvent(event))
+	opts := RequestOptions{
+		Path:   fullPath,
+		Method: "DELETE",
+	}
+
+	_, err := client.RequestAPI(ctx, &opts)
+	if err != nil {
+		return fmt.Errorf("failed to delete Trigger Event: \n%w", err)
+	}
+
+	return nil
+}
@@ -1,9 +1,11 @@
 package cfclient
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/url"
+	"slices"
 )
 
 type IDP struct {
@@ -97,7 +99,7 @@ func getAPIEndpoint(isGlobal bool) string {
 
 // Currently on create the API sometimes (like when creating saml idps) returns a different structure for accounts than on read making the client crash on decode
 // For now we are disabling response decode and in the resource will instead call the read function again
-func (client *Client) CreateIDP(idp *IDP, isGlobal bool) (id string, err error) {
+func (client *Client) CreateIDP(ctx context.Context, idp *IDP, isGlobal bool) (id string, err error) {
 
 	body, err := EncodeToJSON(idp)
 
@@ -110,7 +112,7 @@ func (client *Client) CreateIDP(idp *IDP, isGlobal bool) (id string, err error)
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		log.Printf("[DEBUG] Call to API for IDP creation failed with Error = %v for Body %v", err, body)
@@ -129,7 +131,7 @@ func (client *Client) CreateIDP(idp *IDP, isGlobal bool) (id string, err error)
 
 // Currently on update the API returns a different structure for accounts than on read making the client crash on decode
 // For now we are disabling response decode and in the resource will instead call the read function again
-func (client *Client) UpdateIDP(idp *IDP, isGlobal bool) error {
+func (client *Client) UpdateIDP(ctx context.Context, idp *IDP, isGlobal bool) error {
 
 	body, err := EncodeToJSON(idp)
 
@@ -142,7 +144,7 @@ func (client *Client) UpdateIDP(idp *IDP, isGlobal bool) error {
 		Body:   body,
 	}
 
-	_, err = client.RequestAPI(&opts)
+	_, err = client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		log.Printf("[DEBUG] Call to API for IDP update failed with Error = %v for Body %v", err, body)
@@ -158,7 +160,7 @@ func (client *Client) UpdateIDP(idp *IDP, isGlobal bool) error {
 	return nil
 }
 
-func (client *Client) DeleteIDP(id string) error {
+func (client *Client) DeleteIDP(ctx context.Context, id string) error {
 	baseUrl := getAPIEndpoint(true)
 	fullPath := fmt.Sprintf("%s/%s", baseUrl, url.PathEscape(id))
 	opts := RequestOptions{
@@ -166,7 +168,7 @@ func (client *Client) DeleteIDP(id string) error {
 		Method: "DELETE",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
@@ -175,7 +177,7 @@ func (client *Client) DeleteIDP(id string) error {
 	return nil
 }
 
-func (client *Client) DeleteIDPAccount(id string) error {
+func (client *Client) DeleteIDPAccount(ctx context.Context, id string) error {
 
 	body, err := EncodeToJSON(map[string]interface{}{"id": id})
 
@@ -189,7 +191,7 @@ func (client *Client) DeleteIDPAccount(id string) error {
 		Body:   body,
 	}
 
-	_, err = client.RequestAPI(&opts)
+	_, err = client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
@@ -199,14 +201,26 @@ func (client *Client) DeleteIDPAccount(id string) error {
 }
 
 // get all idps
-func (client *Client) GetIDPs() (*[]IDP, error) {
+func (client *Client) GetIDPs(ctx context.Context) (*[]IDP, error) {
+	list, err := cached(ctx, client, cacheKeyIDPs, func() (*[]IDP, error) {
+		return client.getIDPs(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	idps := slices.Clone(*list)
+	return &idps, nil
+}
+
+func (client *Client) getIDPs(ctx context.Context) (*[]IDP, error) {
 	fullPath := "/admin/idp"
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -223,9 +237,9 @@ func (client *Client) GetIDPs() (*[]IDP, error) {
 }
 
 // get idp id by idp name
-func (client *Client) GetIdpByName(idpName string) (*IDP, error) {
+func (client *Client) GetIdpByName(ctx context.Context, idpName string) (*IDP, error) {
 
-	idpList, err := client.GetIDPs()
+	idpList, err := client.GetIDPs(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -236,12 +250,12 @@ func (client *Client) GetIdpByName(idpName string) (*IDP, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("[ERROR] IDP with name %s isn't found.", idpName)
+	return nil, notFoundf("[ERROR] IDP with name %s isn't found.", idpName)
 }
 
-func (client *Client) GetIdpByID(idpID string) (*IDP, error) {
+func (client *Client) GetIdpByID(ctx context.Context, idpID string) (*IDP, error) {
 
-	idpList, err := client.GetIDPs()
+	idpList, err := client.GetIDPs(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -252,18 +266,30 @@ func (client *Client) GetIdpByID(idpID string) (*IDP, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("[ERROR] IDP with ID %s isn't found.", idpID)
+	return nil, notFoundf("[ERROR] IDP with ID %s isn't found.", idpID)
 }
 
 // get account idps
-func (client *Client) GetAccountIDPs() (*[]IDP, error) {
+func (client *Client) GetAccountIDPs(ctx context.Context) (*[]IDP, error) {
+	list, err := cached(ctx, client, cacheKeyAccountIDPs, func() (*[]IDP, error) {
+		return client.getAccountIDPs(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	idps := slices.Clone(*list)
+	return &idps, nil
+}
+
+func (client *Client) getAccountIDPs(ctx context.Context) (*[]IDP, error) {
 	fullPath := "/idp/account"
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -279,9 +305,9 @@ func (client *Client) GetAccountIDPs() (*[]IDP, error) {
 	return &idps, nil
 }
 
-func (client *Client) GetAccountIdpByID(idpID string) (*IDP, error) {
+func (client *Client) GetAccountIdpByID(ctx context.Context, idpID string) (*IDP, error) {
 
-	idpList, err := client.GetAccountIDPs()
+	idpList, err := client.GetAccountIDPs(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -292,11 +318,11 @@ func (client *Client) GetAccountIdpByID(idpID string) (*IDP, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("[ERROR] IDP with ID %s isn't found.", idpID)
+	return nil, notFoundf("[ERROR] IDP with ID %s isn't found.", idpID)
 }
 
 // add account to idp
-func (client *Client) AddAccountToIDP(accountId, idpId string) error {
+func (client *Client) AddAccountToIDP(ctx context.Context, accountId, idpId string) error {
 
 	body := fmt.Sprintf(`{"accountId":"%s","IDPConfigId":"%s"}`, accountId, idpId)
 
@@ -306,7 +332,7 @@ func (client *Client) AddAccountToIDP(accountId, idpId string) error {
 		Body:   []byte(body),
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,195 @@
+package cfclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// redactedValue replaces sensitive values in logs
+const redactedValue = "***"
+
+// maxLoggedBodySize bounds the size of the request and response bodies written to the logs
+const maxLoggedBodySize = 64 * 1024
+
+// redactedHeaders are the request headers whose value is never logged
+var redactedHeaders = []string{"Authorization", "x-access-token"}
+
+// sensitiveKeyParts are matched, case insensitively, against JSON keys to find values that must not be logged
+var sensitiveKeyParts = []string{"password", "secret", "token", "keyfile", "privatekey", "apikey"}
+
+// rawTokenPaths are the API paths answering with a bare token instead of a JSON object
+var rawTokenPaths = []string{"/auth/key"}
+
+// logRequest logs an attempt to send request, with credentials and secrets masked
+func (client *Client) logRequest(ctx context.Context, request *http.Request, attempt int) {
+	fields := map[string]interface{}{
+		"http_method":          request.Method,
+		"http_url":             request.URL.Redacted(),
+		"http_attempt":         attempt,
+		"http_request_headers": redactHeaders(request.Header),
+	}
+	if request.GetBody != nil {
+		if reader, err := request.GetBody(); err == nil {
+			body, _ := io.ReadAll(reader)
+			if len(body) > 0 {
+				fields["http_request_body"] = redactBody(body, false)
+			}
+		}
+	}
+	tflog.Debug(client.maskToken(ctx), "Sending Codefresh API request", fields)
+}
+
+// logResponse logs the response to request, with secrets masked
+func (client *Client) logResponse(ctx context.Context, request *http.Request, resp *http.Response, body []byte, latency time.Duration) {
+	fields := map[string]interface{}{
+		"http_method":      request.Method,
+		"http_url":         request.URL.Redacted(),
+		"http_status_code": resp.StatusCode,
+		"http_duration_ms": latency.Milliseconds(),
+	}
+	if len(body) > 0 {
+		fields["http_response_body"] = redactBody(body, isRawTokenPath(request.URL.Path))
+	}
+	tflog.Debug(client.maskToken(ctx), "Received Codefresh API response", fields)
+}
+
+// logRequestError logs a request that failed without a response
+func (client *Client) logRequestError(ctx context.Context, request *http.Request, err error, latency time.Duration) {
+	tflog.Debug(client.maskToken(ctx), "Codefresh API request failed", map[string]interface{}{
+		"http_method":      request.Method,
+		"http_url":         request.URL.Redacted(),
+		"http_duration_ms": latency.Milliseconds(),
+		"error":            err.Error(),
+	})
+}
+
+// maskToken makes sure the client token is masked wherever it appears in the logged fields
+func (client *Client) maskToken(ctx context.Context) context.Context {
+	if client.Token == "" {
+		return ctx
+	}
+	return tflog.MaskAllFieldValuesStrings(ctx, client.Token)
+}
+
+func redactHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name := range header {
+		headers[name] = header.Get(name)
+	}
+	for _, name := range redactedHeaders {
+		if header.Get(name) != "" {
+			headers[http.CanonicalHeaderKey(name)] = redactedValue
+		}
+	}
+	return headers
+}
+
+func isRawTokenPath(path string) bool {
+	for _, tokenPath := range rawTokenPaths {
+		if strings.Contains(path, tokenPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactBody returns the body as a string with the values of sensitive JSON fields masked.
+// Bodies that are not JSON are logged as is, unless rawToken is set in which case they are fully masked.
+func redactBody(body []byte, rawToken bool) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		if rawToken {
+			return redactedValue
+		}
+		return truncateBody(string(body))
+	}
+	if _, ok := value.(string); ok && rawToken {
+		return redactedValue
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return redactedValue
+	}
+	return truncateBody(string(redacted))
+}
+
+func truncateBody(body string) string {
+	if len(body) > maxLoggedBodySize {
+		return body[:maxLoggedBodySize] + "...(truncated)"
+	}
+	return body
+}
+
+// redactValue walks a decoded JSON value and masks:
+//   - the values of keys looking like credentials (passwords, tokens, secrets...)
+//   - the data of secret contexts
+//   - the values of encrypted variables
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		isSecretContext := false
+		if contextType, ok := v["type"].(string); ok && strings.Contains(contextType, "secret") {
+			isSecretContext = true
+		}
+		isEncryptedVariable := false
+		if encrypted, ok := v["encrypted"].(bool); ok && encrypted {
+			isEncryptedVariable = true
+		}
+
+		for key, child := range v {
+			switch {
+			case isSensitiveKey(key):
+				v[key] = redactLeaves(child)
+			case isSecretContext && key == "data":
+				v[key] = redactLeaves(child)
+			case isEncryptedVariable && key == "value":
+				v[key] = redactLeaves(child)
+			default:
+				v[key] = redactValue(child)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactValue(child)
+		}
+		return v
+	}
+	return value
+}
+
+// redactLeaves masks every scalar value found in value, keeping its structure so that keys stay visible
+func redactLeaves(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = redactLeaves(child)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactLeaves(child)
+		}
+		return v
+	case nil, bool:
+		return v
+	}
+	return redactedValue
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package cfclient
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	cases := map[string]struct {
+		body     string
+		rawToken bool
+		secrets  []string
+		kept     []string
+	}{
+		"registry": {
+			body:    `{"name":"dockerhub","kind":"standard","password":"hunter2","username":"me"}`,
+			secrets: []string{"hunter2"},
+			kept:    []string{"dockerhub", "me"},
+		},
+		"secret context": {
+			body:    `{"metadata":{"name":"ctx"},"spec":{"type":"secret-yaml","data":{"db":{"password":"p4ss","host":"db.local"}}}}`,
+			secrets: []string{"p4ss", "db.local"},
+			kept:    []string{"ctx", `"db"`, `"host"`},
+		},
+		"plain context": {
+			body: `{"metadata":{"name":"ctx"},"spec":{"type":"config","data":{"host":"db.local"}}}`,
+			kept: []string{"db.local"},
+		},
+		"encrypted variables": {
+			body:    `{"spec":{"variables":[{"key":"PLAIN","value":"visible"},{"key":"SECRET","value":"s3cr3t","encrypted":true}]}}`,
+			secrets: []string{"s3cr3t"},
+			kept:    []string{"visible", "SECRET"},
+		},
+		"access token": {
+			body:    `{"accessToken":"abc.def"}`,
+			secrets: []string{"abc.def"},
+		},
+		"raw token": {
+			body:     `5f1a.0123456789`,
+			rawToken: true,
+			secrets:  []string{"5f1a.0123456789"},
+		},
+		"not json": {
+			body: `Not Found`,
+			kept: []string{"Not Found"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			redacted := redactBody([]byte(c.body), c.rawToken)
+			for _, secret := range c.secrets {
+				if strings.Contains(redacted, secret) {
+					t.Errorf("expected %q to be masked in %s", secret, redacted)
+				}
+			}
+			for _, kept := range c.kept {
+				if !strings.Contains(redacted, kept) {
+					t.Errorf("expected %q to be kept in %s", kept, redacted)
+				}
+			}
+		})
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "token")
+	header.Set("x-access-token", "other-token")
+	header.Set("Content-Type", "application/json")
+
+	headers := redactHeaders(header)
+	if headers["Authorization"] != redactedValue || headers["X-Access-Token"] != redactedValue {
+		t.Fatalf("expected the credentials to be masked, got %v", headers)
+	}
+	if headers["Content-Type"] != "application/json" {
+		t.Fatalf("expected other headers to be kept, got %v", headers)
+	}
+}
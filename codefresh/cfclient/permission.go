@@ -1,6 +1,7 @@
 package cfclient
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -26,14 +27,14 @@ type NewPermission struct {
 	Tags            []string `json:"tags,omitempty"`
 }
 
-func (client *Client) GetPermissionList(teamID, action, resource string) ([]Permission, error) {
+func (client *Client) GetPermissionList(ctx context.Context, teamID, action, resource string) ([]Permission, error) {
 	fullPath := "/abac"
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -63,14 +64,14 @@ func (client *Client) GetPermissionList(teamID, action, resource string) ([]Perm
 }
 
 // GetPermissionByID -
-func (client *Client) GetPermissionByID(id string) (*Permission, error) {
+func (client *Client) GetPermissionByID(ctx context.Context, id string) (*Permission, error) {
 	fullPath := fmt.Sprintf("/abac/%s", id)
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +85,7 @@ func (client *Client) GetPermissionByID(id string) (*Permission, error) {
 	return &permission, nil
 }
 
-func (client *Client) CreatePermission(permission *Permission) (*Permission, error) {
+func (client *Client) CreatePermission(ctx context.Context, permission *Permission) (*Permission, error) {
 
 	newPermission := &NewPermission{
 		ID:              permission.ID,
@@ -107,7 +108,7 @@ func (client *Client) CreatePermission(permission *Permission) (*Permission, err
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -124,17 +125,17 @@ func (client *Client) CreatePermission(permission *Permission) (*Permission, err
 
 	newPermissionID := permissionResp[0].ID
 
-	return client.GetPermissionByID(newPermissionID)
+	return client.GetPermissionByID(ctx, newPermissionID)
 }
 
-func (client *Client) DeletePermission(id string) error {
+func (client *Client) DeletePermission(ctx context.Context, id string) error {
 	fullPath := fmt.Sprintf("/abac/%s", id)
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "DELETE",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
@@ -143,7 +144,7 @@ func (client *Client) DeletePermission(id string) error {
 	return nil
 }
 
-func (client *Client) UpdatePermissionTags(permission *Permission) error {
+func (client *Client) UpdatePermissionTags(ctx context.Context, permission *Permission) error {
 
 	fullPath := fmt.Sprintf("/abac/tags/rule/%s", permission.ID)
 
@@ -155,7 +156,7 @@ func (client *Client) UpdatePermissionTags(permission *Permission) error {
 		Body:   body,
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
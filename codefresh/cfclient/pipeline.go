@@ -1,8 +1,10 @@
 package cfclient
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -107,6 +109,13 @@ func (t *Trigger) SetVariables(variables map[string]interface{}, encrypted bool)
 	}
 }
 
+// HasMaskedVariables returns true if the trigger has encrypted variables that were read without being decrypted
+func (t *Trigger) HasMaskedVariables() bool {
+	return slices.ContainsFunc(t.Variables, func(variable Variable) bool {
+		return variable.Encrypted && variable.Value == MaskedVariableValue
+	})
+}
+
 func (t *CronTrigger) SetVariables(variables map[string]interface{}, encrypted bool) {
 	for key, value := range variables {
 		t.Variables = append(t.Variables, Variable{Key: key, Value: value.(string), Encrypted: encrypted})
@@ -194,14 +203,25 @@ func (pipeline *Pipeline) GetID() string {
 	}
 }
 
-func (client *Client) GetPipeline(name string) (*Pipeline, error) {
+func (client *Client) GetPipeline(ctx context.Context, name string) (*Pipeline, error) {
+	return client.getPipeline(ctx, name, nil)
+}
+
+// GetPipelineDecrypted returns the pipeline with the values of the encrypted variables of its spec and triggers decrypted,
+// unless the account forbids decryption, in which case they are masked as by GetPipeline
+func (client *Client) GetPipelineDecrypted(ctx context.Context, name string) (*Pipeline, error) {
+	return client.getPipeline(ctx, name, client.decryptVariablesQS(ctx))
+}
+
+func (client *Client) getPipeline(ctx context.Context, name string, qs map[string]string) (*Pipeline, error) {
 	fullPath := fmt.Sprintf("/pipelines/%s", strings.Replace(name, "/", "%2F", 1))
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "GET",
+		QS:     qs,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -217,14 +237,14 @@ func (client *Client) GetPipeline(name string) (*Pipeline, error) {
 	return &pipeline, nil
 }
 
-func (client *Client) GetPipelines() (*[]Pipeline, error) {
+func (client *Client) GetPipelines(ctx context.Context) (*[]Pipeline, error) {
 	fullPath := "/pipelines"
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -240,7 +260,7 @@ func (client *Client) GetPipelines() (*[]Pipeline, error) {
 	return &getPipelines.Docs, nil
 }
 
-func (client *Client) CreatePipeline(pipeline *Pipeline) (*Pipeline, error) {
+func (client *Client) CreatePipeline(ctx context.Context, pipeline *Pipeline) (*Pipeline, error) {
 
 	body, err := EncodeToJSON(pipeline)
 
@@ -253,7 +273,7 @@ func (client *Client) CreatePipeline(pipeline *Pipeline) (*Pipeline, error) {
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -269,7 +289,7 @@ func (client *Client) CreatePipeline(pipeline *Pipeline) (*Pipeline, error) {
 
 }
 
-func (client *Client) UpdatePipeline(pipeline *Pipeline) (*Pipeline, error) {
+func (client *Client) UpdatePipeline(ctx context.Context, pipeline *Pipeline) (*Pipeline, error) {
 
 	body, err := EncodeToJSON(pipeline)
 
@@ -289,7 +309,7 @@ func (client *Client) UpdatePipeline(pipeline *Pipeline) (*Pipeline, error) {
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
 		return nil, err
 	}
@@ -303,7 +323,7 @@ func (client *Client) UpdatePipeline(pipeline *Pipeline) (*Pipeline, error) {
 	return &respPipeline, nil
 }
 
-func (client *Client) DeletePipeline(name string) error {
+func (client *Client) DeletePipeline(ctx context.Context, name string) error {
 
 	fullPath := fmt.Sprintf("/pipelines/%s", strings.Replace(name, "/", "%2F", 1))
 	opts := RequestOptions{
@@ -311,7 +331,7 @@ func (client *Client) DeletePipeline(name string) error {
 		Method: "DELETE",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
@@ -319,3 +339,132 @@ func (client *Client) DeletePipeline(name string) error {
 
 	return nil
 }
+
+// GetPipelineTrigger returns the git trigger of the pipeline with the given name, with its encrypted variables decrypted as by GetPipelineDecrypted
+func (client *Client) GetPipelineTrigger(ctx context.Context, pipelineID string, name string) (*Trigger, error) {
+	pipeline, err := client.GetPipelineDecrypted(ctx, pipelineID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, trigger := range pipeline.Spec.Triggers {
+		if trigger.Name == name {
+			return &trigger, nil
+		}
+	}
+
+	return nil, notFoundf("trigger %s not found in pipeline %s", name, pipelineID)
+}
+
+// SetPipelineTrigger adds the git trigger to the pipeline, or replaces the trigger with the same name
+func (client *Client) SetPipelineTrigger(ctx context.Context, pipelineID string, trigger *Trigger) error {
+	encoded, err := EncodeToJSON(trigger)
+	if err != nil {
+		return err
+	}
+	var newTrigger map[string]interface{}
+	err = DecodeResponseInto(encoded, &newTrigger)
+	if err != nil {
+		return err
+	}
+
+	return client.updatePipelineTriggers(ctx, pipelineID, func(triggers []interface{}) ([]interface{}, error) {
+		for i, current := range triggers {
+			if currentTrigger, ok := current.(map[string]interface{}); ok && currentTrigger["name"] == trigger.Name {
+				// keep the ID given to the trigger by Codefresh
+				if id, ok := currentTrigger["id"]; ok {
+					newTrigger["id"] = id
+				}
+				triggers[i] = newTrigger
+				return triggers, nil
+			}
+		}
+		return append(triggers, newTrigger), nil
+	})
+}
+
+// DeletePipelineTrigger removes the git trigger with the given name from the pipeline
+func (client *Client) DeletePipelineTrigger(ctx context.Context, pipelineID string, name string) error {
+	return client.updatePipelineTriggers(ctx, pipelineID, func(triggers []interface{}) ([]interface{}, error) {
+		for i, current := range triggers {
+			if currentTrigger, ok := current.(map[string]interface{}); ok && currentTrigger["name"] == name {
+				return append(triggers[:i], triggers[i+1:]...), nil
+			}
+		}
+		return nil, notFoundf("trigger %s not found in pipeline %s", name, pipelineID)
+	})
+}
+
+// updatePipelineTriggers replaces the triggers of the pipeline with the ones returned by update.
+// The pipeline is sent back as returned by the API, so that the attributes unknown to Pipeline are kept.
+// Its encrypted variables are read decrypted, so that they are not overwritten with their masked value:
+// when the account forbids decryption, the update is refused if encrypted variables other than the ones set by update would be sent back.
+func (client *Client) updatePipelineTriggers(ctx context.Context, pipelineID string, update func(triggers []interface{}) ([]interface{}, error)) error {
+	fullPath := fmt.Sprintf("/pipelines/%s", strings.Replace(pipelineID, "/", "%2F", 1))
+	qs := client.decryptVariablesQS(ctx)
+	resp, err := client.RequestAPI(ctx, &RequestOptions{
+		Path:   fullPath,
+		Method: "GET",
+		QS:     qs,
+	})
+	if err != nil {
+		return err
+	}
+
+	var pipeline map[string]interface{}
+	err = DecodeResponseInto(resp, &pipeline)
+	if err != nil {
+		return err
+	}
+	spec, ok := pipeline["spec"].(map[string]interface{})
+	if !ok {
+		spec = map[string]interface{}{}
+		pipeline["spec"] = spec
+	}
+	triggers, _ := spec["triggers"].([]interface{})
+
+	triggers, err = update(triggers)
+	if err != nil {
+		return err
+	}
+	if triggers == nil {
+		triggers = []interface{}{}
+	}
+	spec["triggers"] = triggers
+	if qs == nil && containsMaskedVariables(pipeline) {
+		return fmt.Errorf("cannot update the triggers of pipeline %s: the account forbids decrypting variables, and its encrypted variables would be overwritten with their masked value", pipelineID)
+	}
+
+	body, err := EncodeToJSON(pipeline)
+	if err != nil {
+		return err
+	}
+	_, err = client.RequestAPI(ctx, &RequestOptions{
+		Path:   fullPath,
+		Method: "PUT",
+		Body:   body,
+	})
+	return err
+}
+
+// containsMaskedVariables returns true if value, a document returned by the API, contains an encrypted variable with its masked value
+func containsMaskedVariables(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if encrypted, _ := v["encrypted"].(bool); encrypted && v["value"] == MaskedVariableValue {
+			return true
+		}
+		for _, child := range v {
+			if containsMaskedVariables(child) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if containsMaskedVariables(child) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package cfclient
 
 import (
+	"context"
 	"errors"
 	"fmt"
 )
@@ -26,14 +27,14 @@ func (project *Project) SetVariables(variables map[string]interface{}, encrypted
 }
 
 // GetProjectByName get project object by name
-func (client *Client) GetProjectByName(name string) (*Project, error) {
+func (client *Client) GetProjectByName(ctx context.Context, name string) (*Project, error) {
 	fullPath := fmt.Sprintf("/projects/name/%s", name)
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -50,14 +51,25 @@ func (client *Client) GetProjectByName(name string) (*Project, error) {
 }
 
 // GetProjectByID get project object by id
-func (client *Client) GetProjectByID(id string) (*Project, error) {
+func (client *Client) GetProjectByID(ctx context.Context, id string) (*Project, error) {
+	return client.getProjectByID(ctx, id, nil)
+}
+
+// GetProjectByIDDecrypted get project object by id, with the values of its encrypted variables decrypted,
+// unless the account forbids decryption, in which case they are masked as by GetProjectByID
+func (client *Client) GetProjectByIDDecrypted(ctx context.Context, id string) (*Project, error) {
+	return client.getProjectByID(ctx, id, client.decryptVariablesQS(ctx))
+}
+
+func (client *Client) getProjectByID(ctx context.Context, id string, qs map[string]string) (*Project, error) {
 	fullPath := fmt.Sprintf("/projects/%s", id)
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "GET",
+		QS:     qs,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -73,8 +85,34 @@ func (client *Client) GetProjectByID(id string) (*Project, error) {
 	return &project, nil
 }
 
+// GetProjects get the projects of the account
+func (client *Client) GetProjects(ctx context.Context) ([]Project, error) {
+	opts := RequestOptions{
+		Path:   "/projects",
+		Method: "GET",
+		QS:     map[string]string{"limit": "10000"},
+	}
+
+	resp, err := client.RequestAPI(ctx, &opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var projects struct {
+		Projects []Project `json:"projects"`
+	}
+
+	err = DecodeResponseInto(resp, &projects)
+	if err != nil {
+		return nil, err
+	}
+
+	return projects.Projects, nil
+}
+
 // CreateProject POST project
-func (client *Client) CreateProject(project *Project) (*Project, error) {
+func (client *Client) CreateProject(ctx context.Context, project *Project) (*Project, error) {
 
 	body, err := EncodeToJSON(project)
 
@@ -87,7 +125,7 @@ func (client *Client) CreateProject(project *Project) (*Project, error) {
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -103,7 +141,7 @@ func (client *Client) CreateProject(project *Project) (*Project, error) {
 }
 
 // UpdateProject PATCH project
-func (client *Client) UpdateProject(project *Project) error {
+func (client *Client) UpdateProject(ctx context.Context, project *Project) error {
 
 	body, err := EncodeToJSON(project)
 
@@ -123,7 +161,7 @@ func (client *Client) UpdateProject(project *Project) error {
 		Body:   body,
 	}
 
-	_, err = client.RequestAPI(&opts)
+	_, err = client.RequestAPI(ctx, &opts)
 	if err != nil {
 		return err
 	}
@@ -132,14 +170,14 @@ func (client *Client) UpdateProject(project *Project) error {
 }
 
 // DeleteProject DELETE
-func (client *Client) DeleteProject(id string) error {
+func (client *Client) DeleteProject(ctx context.Context, id string) error {
 	fullPath := fmt.Sprintf("/projects/%s", id)
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "DELETE",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
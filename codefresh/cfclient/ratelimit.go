@@ -0,0 +1,57 @@
+package cfclient
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// DefaultMaxRequestsPerSecond is the default rate at which requests are sent to each Codefresh API host
+const DefaultMaxRequestsPerSecond = 10
+
+// rateLimiter is a token bucket shared by all the requests sent to one API host.
+// The bucket holds up to one second worth of requests, so short bursts are sent right away.
+// Tokens can be borrowed ahead of time, callers then wait in the order in which they asked for a token.
+type rateLimiter struct {
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{}
+}
+
+// wait blocks until a request can be sent at the given rate, or until ctx is done.
+// A zero or negative rate disables the limiter.
+func (l *rateLimiter) wait(ctx context.Context, requestsPerSecond float64) error {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	burst := math.Max(1, requestsPerSecond)
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.last.IsZero() {
+		l.tokens = burst
+	} else {
+		l.tokens = math.Min(burst, l.tokens+now.Sub(l.last).Seconds()*requestsPerSecond)
+	}
+	l.last = now
+	l.tokens--
+	wait := time.Duration(-l.tokens / requestsPerSecond * float64(time.Second))
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	if err := sleepWithContext(ctx, wait); err != nil {
+		// give the token back, the request will not be sent
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
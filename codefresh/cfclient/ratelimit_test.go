@@ -0,0 +1,73 @@
+package cfclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllowsBurstThenWaits(t *testing.T) {
+	limiter := newRateLimiter()
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := limiter.wait(ctx, 5); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("expected the burst to be sent right away, waited %s", elapsed)
+	}
+
+	if err := limiter.wait(ctx, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("expected the request after the burst to wait, waited %s", elapsed)
+	}
+}
+
+func TestRateLimiterIsDisabledByZeroRate(t *testing.T) {
+	limiter := newRateLimiter()
+	for i := 0; i < 100; i++ {
+		if err := limiter.wait(context.Background(), 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestRateLimiterStopsWaitingWhenContextIsDone(t *testing.T) {
+	limiter := newRateLimiter()
+	_ = limiter.wait(context.Background(), 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.wait(ctx, 1); err != context.DeadlineExceeded {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestRequestAPIAndSendGqlRequestUseSeparateLimiters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, server.URL, "token", "")
+	client.MaxRequestsPerSecond = 1
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.RequestAPI(ctx, &RequestOptions{Path: "/pipelines", Method: "GET"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.SendGqlRequest(ctx, GraphQLRequest{Query: "query AccountInfo { me { id } }"}); err != nil {
+		t.Fatalf("expected the gitops API to have its own limiter, got %v", err)
+	}
+	if _, err := client.RequestAPI(ctx, &RequestOptions{Path: "/pipelines", Method: "GET"}); err != context.DeadlineExceeded {
+		t.Fatalf("expected the second REST call to be rate limited, got %v", err)
+	}
+}
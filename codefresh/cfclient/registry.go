@@ -1,6 +1,7 @@
 package cfclient
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/url"
@@ -45,14 +46,14 @@ func (registry *Registry) GetID() string {
 }
 
 // GetRegistry identifier is ObjectId or name
-func (client *Client) GetRegistry(identifier string) (*Registry, error) {
+func (client *Client) GetRegistry(ctx context.Context, identifier string) (*Registry, error) {
 	fullPath := fmt.Sprintf("/registries/%s", url.PathEscape(identifier))
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -67,7 +68,28 @@ func (client *Client) GetRegistry(identifier string) (*Registry, error) {
 
 }
 
-func (client *Client) CreateRegistry(registry *Registry) (*Registry, error) {
+// GetRegistries returns the registries of the account
+func (client *Client) GetRegistries(ctx context.Context) ([]Registry, error) {
+	opts := RequestOptions{
+		Path:   "/registries",
+		Method: "GET",
+	}
+
+	resp, err := client.RequestAPI(ctx, &opts)
+
+	if err != nil {
+		return nil, err
+	}
+	var registries []Registry
+	err = DecodeResponseInto(resp, &registries)
+	if err != nil {
+		return nil, err
+	}
+
+	return registries, nil
+}
+
+func (client *Client) CreateRegistry(ctx context.Context, registry *Registry) (*Registry, error) {
 
 	body, err := EncodeToJSON(registry)
 
@@ -80,7 +102,7 @@ func (client *Client) CreateRegistry(registry *Registry) (*Registry, error) {
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		log.Printf("[DEBUG] Call to API for registry creation failed with Error = %v for Body %v", err, body)
@@ -97,7 +119,7 @@ func (client *Client) CreateRegistry(registry *Registry) (*Registry, error) {
 
 }
 
-func (client *Client) UpdateRegistry(registry *Registry) (*Registry, error) {
+func (client *Client) UpdateRegistry(ctx context.Context, registry *Registry) (*Registry, error) {
 
 	body, err := EncodeToJSON(registry)
 
@@ -112,7 +134,7 @@ func (client *Client) UpdateRegistry(registry *Registry) (*Registry, error) {
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -128,7 +150,7 @@ func (client *Client) UpdateRegistry(registry *Registry) (*Registry, error) {
 
 }
 
-func (client *Client) DeleteRegistry(name string) error {
+func (client *Client) DeleteRegistry(ctx context.Context, name string) error {
 
 	fullPath := fmt.Sprintf("/registries/%s", url.PathEscape(name))
 	opts := RequestOptions{
@@ -136,7 +158,7 @@ func (client *Client) DeleteRegistry(name string) error {
 		Method: "DELETE",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
@@ -0,0 +1,158 @@
+package cfclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// DefaultRetryMaxAttempts is the default number of attempts (including the first one) made for a single API call
+	DefaultRetryMaxAttempts = 4
+	// DefaultRetryMinBackoff is the initial wait between two attempts
+	DefaultRetryMinBackoff = 500 * time.Millisecond
+	// DefaultRetryMaxBackoff is the maximum wait between two attempts
+	DefaultRetryMaxBackoff = 30 * time.Second
+)
+
+// isRetryableStatus returns true for responses that indicate a transient failure of the Codefresh API
+func isRetryableStatus(statusCode int) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return statusCode >= 500 && statusCode != http.StatusNotImplemented
+}
+
+// isIdempotentMethod returns true for HTTP methods that can be safely sent more than once
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header of the response, which can be either a number of seconds or an HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+func (client *Client) newBackOff() *backoff.ExponentialBackOff {
+	expBackoff := backoff.NewExponentialBackOff()
+	expBackoff.InitialInterval = client.RetryMinBackoff
+	if expBackoff.InitialInterval <= 0 {
+		expBackoff.InitialInterval = DefaultRetryMinBackoff
+	}
+	expBackoff.MaxInterval = client.RetryMaxBackoff
+	if expBackoff.MaxInterval <= 0 {
+		expBackoff.MaxInterval = DefaultRetryMaxBackoff
+	}
+	if expBackoff.InitialInterval > expBackoff.MaxInterval {
+		expBackoff.InitialInterval = expBackoff.MaxInterval
+	}
+	// The number of attempts is what bounds the retries, not the elapsed time
+	expBackoff.MaxElapsedTime = 0
+	expBackoff.Reset()
+	return expBackoff
+}
+
+// do sends the request built by newRequest and reads the whole response body.
+// Transient failures (429 and 5xx responses, as well as network errors) are retried with an exponential backoff,
+// honoring the Retry-After header when the API sends one.
+// Requests that are not idempotent are only retried on 429 responses carrying a Retry-After header,
+// with which the server states that it rejected the request before processing it and when to send it again.
+// The request is rebuilt for every attempt so that its body can be sent again.
+// Every attempt first waits for the rate limiter of the target host.
+// Waiting between attempts is interrupted as soon as ctx is done.
+func (client *Client) do(ctx context.Context, limiter *rateLimiter, newRequest func() (*http.Request, error), retryable bool) (*http.Response, []byte, error) {
+	maxAttempts := client.RetryMaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	expBackoff := client.newBackOff()
+
+	for attempt := 1; ; attempt++ {
+		request, err := newRequest()
+		if err != nil {
+			return nil, nil, err
+		}
+		canRetry := retryable || isIdempotentMethod(request.Method)
+
+		if err := limiter.wait(ctx, client.MaxRequestsPerSecond); err != nil {
+			return nil, nil, err
+		}
+		client.logRequest(ctx, request, attempt)
+		start := time.Now()
+		resp, err := client.Client.Do(request)
+		if err != nil {
+			client.logRequestError(ctx, request, err, time.Since(start))
+			if !canRetry || attempt >= maxAttempts || ctx.Err() != nil {
+				return nil, nil, err
+			}
+			wait := expBackoff.NextBackOff()
+			tflog.Debug(ctx, "Retrying Codefresh API request", map[string]interface{}{"http_attempt": attempt, "http_max_attempts": maxAttempts, "wait": wait.String()})
+			if err := sleepWithContext(ctx, wait); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+		client.logResponse(ctx, request, resp, body, time.Since(start))
+
+		if !isRetryableStatus(resp.StatusCode) || attempt >= maxAttempts {
+			return resp, body, nil
+		}
+		after, hasRetryAfter := retryAfter(resp)
+		if !canRetry && (resp.StatusCode != http.StatusTooManyRequests || !hasRetryAfter) {
+			return resp, body, nil
+		}
+
+		wait := expBackoff.NextBackOff()
+		if hasRetryAfter {
+			wait = after
+			if wait > expBackoff.MaxInterval {
+				wait = expBackoff.MaxInterval
+			}
+		}
+		tflog.Debug(ctx, "Retrying Codefresh API request", map[string]interface{}{"http_attempt": attempt, "http_max_attempts": maxAttempts, "wait": wait.String()})
+		if err := sleepWithContext(ctx, wait); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// sleepWithContext waits for the given duration, returning early with the context error if ctx is done
+func sleepWithContext(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
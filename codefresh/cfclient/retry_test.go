@@ -0,0 +1,187 @@
+package cfclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newRetryTestClient(url string) *Client {
+	client := NewClient(url, url, "token", "")
+	client.RetryMinBackoff = time.Millisecond
+	client.RetryMaxBackoff = 10 * time.Millisecond
+	return client
+}
+
+func TestRequestAPIRetriesTransientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL)
+	if _, err := client.RequestAPI(context.Background(), &RequestOptions{Path: "/pipelines", Method: "GET"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRequestAPIStopsAfterMaxAttempts(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL)
+	client.RetryMaxAttempts = 2
+	if _, err := client.RequestAPI(context.Background(), &RequestOptions{Path: "/pipelines", Method: "GET"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestRequestAPIDoesNotRetryNonIdempotentRequests(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL)
+	if _, err := client.RequestAPI(context.Background(), &RequestOptions{Path: "/pipelines", Method: "POST", Body: []byte(`{}`)}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestRequestAPIRetriesRateLimitedRequestsWithRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL)
+	if _, err := client.RequestAPI(context.Background(), &RequestOptions{Path: "/pipelines", Method: "POST", Body: []byte(`{}`)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestRequestAPIDoesNotRetryRateLimitedNonIdempotentRequestsWithoutRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL)
+	if _, err := client.RequestAPI(context.Background(), &RequestOptions{Path: "/pipelines", Method: "POST", Body: []byte(`{}`)}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+
+	calls = 0
+	if _, err := client.RequestAPI(context.Background(), &RequestOptions{Path: "/pipelines", Method: "GET"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != DefaultRetryMaxAttempts {
+		t.Fatalf("expected %d calls for an idempotent request, got %d", DefaultRetryMaxAttempts, calls)
+	}
+}
+
+func TestSendGqlRequestRetriesQueriesOnly(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL)
+	if _, err := client.SendGqlRequest(context.Background(), GraphQLRequest{Query: "query AccountInfo { me { id } }"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != DefaultRetryMaxAttempts {
+		t.Fatalf("expected %d calls for a query, got %d", DefaultRetryMaxAttempts, calls)
+	}
+
+	calls = 0
+	if _, err := client.SendGqlRequest(context.Background(), GraphQLRequest{Query: "mutation RemoveAbacRule { removeAbacRule }"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call for a mutation, got %d", calls)
+	}
+}
+
+func TestRequestAPIStopsRetryingWhenContextIsDone(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL)
+	client.RetryMaxBackoff = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.RequestAPI(ctx, &RequestOptions{Path: "/pipelines", Method: "GET"})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("expected the retry wait to be interrupted, waited %s", elapsed)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	if _, ok := retryAfter(resp); ok {
+		t.Fatal("expected no Retry-After")
+	}
+
+	resp.Header.Set("Retry-After", "7")
+	if wait, ok := retryAfter(resp); !ok || wait != 7*time.Second {
+		t.Fatalf("expected 7s, got %s", wait)
+	}
+
+	resp.Header.Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	if wait, ok := retryAfter(resp); !ok || wait != 0 {
+		t.Fatalf("expected 0s for a date in the past, got %s", wait)
+	}
+}
@@ -1,6 +1,7 @@
 package cfclient
 
 import (
+	"context"
 	"fmt"
 	"slices"
 )
@@ -32,14 +33,25 @@ func (serviceuser *ServiceUser) HasAdminRole() bool {
 	return slices.Contains(serviceuser.Roles, "Admin")
 }
 
-func (client *Client) GetServiceUserList() ([]ServiceUser, error) {
+func (client *Client) GetServiceUserList(ctx context.Context) ([]ServiceUser, error) {
+	list, err := cached(ctx, client, cacheKeyServiceUsers, func() ([]ServiceUser, error) {
+		return client.getServiceUserList(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(list), nil
+}
+
+func (client *Client) getServiceUserList(ctx context.Context) ([]ServiceUser, error) {
 	fullPath := "/service-users"
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -55,9 +67,9 @@ func (client *Client) GetServiceUserList() ([]ServiceUser, error) {
 	return serviceusers, nil
 }
 
-func (client *Client) GetServiceUserByName(name string) (*ServiceUser, error) {
+func (client *Client) GetServiceUserByName(ctx context.Context, name string) (*ServiceUser, error) {
 
-	serviceusers, err := client.GetServiceUserList()
+	serviceusers, err := client.GetServiceUserList(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +83,7 @@ func (client *Client) GetServiceUserByName(name string) (*ServiceUser, error) {
 	return nil, nil
 }
 
-func (client *Client) GetServiceUserByID(id string) (*ServiceUser, error) {
+func (client *Client) GetServiceUserByID(ctx context.Context, id string) (*ServiceUser, error) {
 
 	fullPath := fmt.Sprintf("/service-users/%s", id)
 	opts := RequestOptions{
@@ -79,7 +91,7 @@ func (client *Client) GetServiceUserByID(id string) (*ServiceUser, error) {
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -95,7 +107,7 @@ func (client *Client) GetServiceUserByID(id string) (*ServiceUser, error) {
 	return &serviceuser, nil
 }
 
-func (client *Client) CreateServiceUser(serviceUserCreateUpdate *ServiceUserCreateUpdate) (*ServiceUser, error) {
+func (client *Client) CreateServiceUser(ctx context.Context, serviceUserCreateUpdate *ServiceUserCreateUpdate) (*ServiceUser, error) {
 
 	fullPath := "/service-users"
 	body, err := EncodeToJSON(serviceUserCreateUpdate)
@@ -110,7 +122,7 @@ func (client *Client) CreateServiceUser(serviceUserCreateUpdate *ServiceUserCrea
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -126,7 +138,7 @@ func (client *Client) CreateServiceUser(serviceUserCreateUpdate *ServiceUserCrea
 	return &serviceuser, nil
 }
 
-func (client *Client) UpdateServiceUser(serviceUserCreateUpdate *ServiceUserCreateUpdate) (*ServiceUser, error) {
+func (client *Client) UpdateServiceUser(ctx context.Context, serviceUserCreateUpdate *ServiceUserCreateUpdate) (*ServiceUser, error) {
 
 	fullPath := fmt.Sprintf("/service-users/%s", serviceUserCreateUpdate.ID)
 	body, err := EncodeToJSON(serviceUserCreateUpdate)
@@ -141,7 +153,7 @@ func (client *Client) UpdateServiceUser(serviceUserCreateUpdate *ServiceUserCrea
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -157,14 +169,14 @@ func (client *Client) UpdateServiceUser(serviceUserCreateUpdate *ServiceUserCrea
 	return &serviceuser, nil
 }
 
-func (client *Client) DeleteServiceUser(id string) error {
+func (client *Client) DeleteServiceUser(ctx context.Context, id string) error {
 	fullPath := fmt.Sprintf("/service-users/%s", id)
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "DELETE",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
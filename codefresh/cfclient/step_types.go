@@ -1,6 +1,7 @@
 package cfclient
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/url"
@@ -36,14 +37,14 @@ func (stepTypes *StepTypes) GetID() string {
 	return stepTypes.Metadata["name"].(string)
 }
 
-func (client *Client) GetStepTypesVersions(name string) ([]string, error) {
+func (client *Client) GetStepTypesVersions(ctx context.Context, name string) ([]string, error) {
 	fullPath := fmt.Sprintf("/step-types/%s/versions", url.PathEscape(name))
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -56,14 +57,14 @@ func (client *Client) GetStepTypesVersions(name string) ([]string, error) {
 	return respStepTypesVersions, nil
 }
 
-func (client *Client) GetStepTypes(identifier string) (*StepTypes, error) {
+func (client *Client) GetStepTypes(ctx context.Context, identifier string) (*StepTypes, error) {
 	fullPath := fmt.Sprintf("/step-types/%s", url.PathEscape(identifier))
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -78,7 +79,30 @@ func (client *Client) GetStepTypes(identifier string) (*StepTypes, error) {
 
 }
 
-func (client *Client) CreateStepTypes(stepTypes *StepTypes) (*StepTypes, error) {
+// GetStepTypesList returns the latest version of each step type owned by the account, leaving out the public marketplace ones
+func (client *Client) GetStepTypesList(ctx context.Context) ([]StepTypes, error) {
+	opts := RequestOptions{
+		Path:   "/step-types",
+		Method: "GET",
+		QS:     map[string]string{"accountOnly": "true", "limit": "10000"},
+	}
+
+	resp, err := client.RequestAPI(ctx, &opts)
+
+	if err != nil {
+		return nil, err
+	}
+	var respStepTypes struct {
+		Docs []StepTypes `json:"docs"`
+	}
+	err = DecodeResponseInto(resp, &respStepTypes)
+	if err != nil {
+		return nil, err
+	}
+	return respStepTypes.Docs, nil
+}
+
+func (client *Client) CreateStepTypes(ctx context.Context, stepTypes *StepTypes) (*StepTypes, error) {
 
 	body, err := EncodeToJSON(stepTypes)
 
@@ -91,7 +115,7 @@ func (client *Client) CreateStepTypes(stepTypes *StepTypes) (*StepTypes, error)
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
 		return nil, err
 	}
@@ -106,7 +130,7 @@ func (client *Client) CreateStepTypes(stepTypes *StepTypes) (*StepTypes, error)
 
 }
 
-func (client *Client) UpdateStepTypes(stepTypes *StepTypes) (*StepTypes, error) {
+func (client *Client) UpdateStepTypes(ctx context.Context, stepTypes *StepTypes) (*StepTypes, error) {
 
 	body, err := EncodeToJSON(stepTypes)
 
@@ -121,7 +145,7 @@ func (client *Client) UpdateStepTypes(stepTypes *StepTypes) (*StepTypes, error)
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -137,7 +161,7 @@ func (client *Client) UpdateStepTypes(stepTypes *StepTypes) (*StepTypes, error)
 
 }
 
-func (client *Client) DeleteStepTypes(name string) error {
+func (client *Client) DeleteStepTypes(ctx context.Context, name string) error {
 
 	fullPath := fmt.Sprintf("/step-types/%s", url.PathEscape(name))
 	opts := RequestOptions{
@@ -145,7 +169,7 @@ func (client *Client) DeleteStepTypes(name string) error {
 		Method: "DELETE",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
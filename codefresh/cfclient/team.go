@@ -1,7 +1,9 @@
 package cfclient
 
 import (
+	"context"
 	"fmt"
+	"slices"
 )
 
 type TeamUser struct {
@@ -35,14 +37,25 @@ func (team *Team) GetID() string {
 	return team.ID
 }
 
-func (client *Client) GetTeamList() ([]Team, error) {
+func (client *Client) GetTeamList(ctx context.Context) ([]Team, error) {
+	list, err := cached(ctx, client, cacheKeyTeams, func() ([]Team, error) {
+		return client.getTeamList(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(list), nil
+}
+
+func (client *Client) getTeamList(ctx context.Context) ([]Team, error) {
 	fullPath := "/team"
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -58,9 +71,9 @@ func (client *Client) GetTeamList() ([]Team, error) {
 	return teams, nil
 }
 
-func (client *Client) GetTeamByName(name string) (*Team, error) {
+func (client *Client) GetTeamByName(ctx context.Context, name string) (*Team, error) {
 
-	teams, err := client.GetTeamList()
+	teams, err := client.GetTeamList(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -74,9 +87,9 @@ func (client *Client) GetTeamByName(name string) (*Team, error) {
 	return nil, nil
 }
 
-func (client *Client) GetTeamByID(id string) (*Team, error) {
+func (client *Client) GetTeamByID(ctx context.Context, id string) (*Team, error) {
 
-	teams, err := client.GetTeamList()
+	teams, err := client.GetTeamList(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +121,7 @@ func ConvertToNewTeam(team *Team) *NewTeam {
 }
 
 // CreateTeam POST team
-func (client *Client) CreateTeam(team *Team) (*NewTeam, error) {
+func (client *Client) CreateTeam(ctx context.Context, team *Team) (*NewTeam, error) {
 
 	newTeam := ConvertToNewTeam(team)
 	body, err := EncodeToJSON(newTeam)
@@ -122,7 +135,7 @@ func (client *Client) CreateTeam(team *Team) (*NewTeam, error) {
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -138,14 +151,14 @@ func (client *Client) CreateTeam(team *Team) (*NewTeam, error) {
 }
 
 // DeleteTeam
-func (client *Client) DeleteTeam(id string) error {
+func (client *Client) DeleteTeam(ctx context.Context, id string) error {
 	fullPath := fmt.Sprintf("/team/%s", id)
 	opts := RequestOptions{
 		Path:   fullPath,
 		Method: "DELETE",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
@@ -154,7 +167,7 @@ func (client *Client) DeleteTeam(id string) error {
 	return nil
 }
 
-func (client *Client) SynchronizeClientWithGroup(name, ssoType string, notifications bool) error {
+func (client *Client) SynchronizeClientWithGroup(ctx context.Context, name, ssoType string, notifications bool) error {
 
 	fullPath := fmt.Sprintf("/team/group/synchronize/name/%s/type/%s?disableNotifications=%t", name, ssoType, notifications)
 	opts := RequestOptions{
@@ -162,7 +175,7 @@ func (client *Client) SynchronizeClientWithGroup(name, ssoType string, notificat
 		Method: "GET",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
@@ -171,7 +184,7 @@ func (client *Client) SynchronizeClientWithGroup(name, ssoType string, notificat
 	return nil
 }
 
-func (client *Client) AddUserToTeam(teamID, userID string) error {
+func (client *Client) AddUserToTeam(ctx context.Context, teamID, userID string) error {
 
 	fullPath := fmt.Sprintf("/team/%s/%s/assignUserToTeam", teamID, userID)
 	opts := RequestOptions{
@@ -179,7 +192,7 @@ func (client *Client) AddUserToTeam(teamID, userID string) error {
 		Method: "PUT",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
 		return err
 	}
@@ -187,7 +200,7 @@ func (client *Client) AddUserToTeam(teamID, userID string) error {
 	return nil
 }
 
-func (client *Client) DeleteUserFromTeam(teamID, userID string) error {
+func (client *Client) DeleteUserFromTeam(ctx context.Context, teamID, userID string) error {
 
 	fullPath := fmt.Sprintf("/team/%s/%s/deleteUserFromTeam", teamID, userID)
 	opts := RequestOptions{
@@ -195,7 +208,7 @@ func (client *Client) DeleteUserFromTeam(teamID, userID string) error {
 		Method: "PUT",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
@@ -204,7 +217,7 @@ func (client *Client) DeleteUserFromTeam(teamID, userID string) error {
 	return nil
 }
 
-func (client *Client) RenameTeam(teamID, name string) error {
+func (client *Client) RenameTeam(ctx context.Context, teamID, name string) error {
 
 	fullPath := fmt.Sprintf("/team/%s/renameTeam", teamID)
 
@@ -221,7 +234,7 @@ func (client *Client) RenameTeam(teamID, name string) error {
 		Body:   body,
 	}
 
-	_, err = client.RequestAPI(&opts)
+	_, err = client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
@@ -0,0 +1,90 @@
+package cfclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// TransportOptions configures how the client connects to the Codefresh APIs
+type TransportOptions struct {
+	// CACertFile is the path of a PEM bundle of certificate authorities trusted in addition to the system ones
+	CACertFile string
+	// CACertPEM is a PEM bundle of certificate authorities trusted in addition to the system ones
+	CACertPEM string
+	// InsecureSkipVerify disables the verification of the server certificate
+	InsecureSkipVerify bool
+	// ProxyURL is the URL of the proxy used for all requests, the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are used when empty
+	ProxyURL string
+	// ClientCertFile and ClientKeyFile are the paths of the PEM encoded client certificate and key used for mutual TLS
+	ClientCertFile string
+	ClientKeyFile  string
+	// ClientCertPEM and ClientKeyPEM are the PEM encoded client certificate and key used for mutual TLS
+	ClientCertPEM string
+	ClientKeyPEM  string
+}
+
+// NewTransport returns an HTTP transport applying the given TLS and proxy settings on top of the defaults of net/http
+func NewTransport(opts TransportOptions) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.ProxyURL != "" {
+		proxyURL, err := url.Parse(opts.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %w", opts.ProxyURL, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+
+	if opts.CACertFile != "" || opts.CACertPEM != "" {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil || rootCAs == nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if opts.CACertFile != "" {
+			pem, err := os.ReadFile(opts.CACertFile)
+			if err != nil {
+				return nil, fmt.Errorf("cannot read CA certificates: %w", err)
+			}
+			if !rootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no valid PEM certificate found in %s", opts.CACertFile)
+			}
+		}
+		if opts.CACertPEM != "" && !rootCAs.AppendCertsFromPEM([]byte(opts.CACertPEM)) {
+			return nil, fmt.Errorf("no valid PEM certificate found in the CA certificates")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	certPEM, keyPEM := []byte(opts.ClientCertPEM), []byte(opts.ClientKeyPEM)
+	if opts.ClientCertFile != "" {
+		var err error
+		if certPEM, err = os.ReadFile(opts.ClientCertFile); err != nil {
+			return nil, fmt.Errorf("cannot read client certificate: %w", err)
+		}
+	}
+	if opts.ClientKeyFile != "" {
+		var err error
+		if keyPEM, err = os.ReadFile(opts.ClientKeyFile); err != nil {
+			return nil, fmt.Errorf("cannot read client key: %w", err)
+		}
+	}
+	if len(certPEM) > 0 || len(keyPEM) > 0 {
+		certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
@@ -0,0 +1,99 @@
+package cfclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTLSTestClient(t *testing.T, url string, opts TransportOptions) *Client {
+	transport, err := NewTransport(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client := NewClient(url, url, "token", "")
+	client.RetryMaxAttempts = 1
+	client.Client.Transport = transport
+	return client
+}
+
+func TestNewTransportTrustsCustomCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	ctx := context.Background()
+	request := &RequestOptions{Path: "/pipelines", Method: "GET"}
+
+	client := newTLSTestClient(t, server.URL, TransportOptions{})
+	if _, err := client.RequestAPI(ctx, request); err == nil {
+		t.Fatal("expected the self-signed certificate to be rejected")
+	}
+
+	client = newTLSTestClient(t, server.URL, TransportOptions{CACertPEM: caPEM})
+	if _, err := client.RequestAPI(ctx, request); err != nil {
+		t.Fatalf("expected the custom CA to be trusted, got %v", err)
+	}
+	if _, err := client.SendGqlRequest(ctx, GraphQLRequest{Query: "query AccountInfo { me { id } }"}); err != nil {
+		t.Fatalf("expected the GraphQL API to use the same transport, got %v", err)
+	}
+
+	client = newTLSTestClient(t, server.URL, TransportOptions{InsecureSkipVerify: true})
+	if _, err := client.RequestAPI(ctx, request); err != nil {
+		t.Fatalf("expected the certificate not to be verified, got %v", err)
+	}
+}
+
+func TestNewTransportSendsClientCertificate(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	// the test server certificate and key are reused as the client certificate
+	serverCert := server.TLS.Certificates[0]
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: serverCert.Certificate[0]}))
+	key, err := x509.MarshalPKCS8PrivateKey(serverCert.PrivateKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}))
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	ctx := context.Background()
+	request := &RequestOptions{Path: "/pipelines", Method: "GET"}
+
+	client := newTLSTestClient(t, server.URL, TransportOptions{CACertPEM: caPEM})
+	if _, err := client.RequestAPI(ctx, request); err == nil {
+		t.Fatal("expected the request without a client certificate to be rejected")
+	}
+
+	client = newTLSTestClient(t, server.URL, TransportOptions{CACertPEM: caPEM, ClientCertPEM: certPEM, ClientKeyPEM: keyPEM})
+	if _, err := client.RequestAPI(ctx, request); err != nil {
+		t.Fatalf("expected the client certificate to be accepted, got %v", err)
+	}
+}
+
+func TestNewTransportRejectsInvalidSettings(t *testing.T) {
+	cases := map[string]TransportOptions{
+		"missing CA file":     {CACertFile: "/does/not/exist.pem"},
+		"invalid CA":          {CACertPEM: "not a certificate"},
+		"invalid client cert": {ClientCertPEM: "not a certificate", ClientKeyPEM: "not a key"},
+		"invalid proxy":       {ProxyURL: "http://[::1"},
+	}
+
+	for name, opts := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewTransport(opts); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package cfclient
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
@@ -76,7 +77,7 @@ func generateUserDetailsBody(userName, userEmail string) string {
 	return userDetails
 }
 
-func (client *Client) AddNewUserToAccount(accountId, userName, userEmail string) (*User, error) {
+func (client *Client) AddNewUserToAccount(ctx context.Context, accountId, userName, userEmail string) (*User, error) {
 
 	fullPath := fmt.Sprintf("/accounts/%s/adduser", accountId)
 	opts := RequestOptions{
@@ -85,7 +86,7 @@ func (client *Client) AddNewUserToAccount(accountId, userName, userEmail string)
 		Body:   []byte(generateUserDetailsBody(userName, userEmail)),
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
 		return nil, err
 	}
@@ -100,7 +101,7 @@ func (client *Client) AddNewUserToAccount(accountId, userName, userEmail string)
 	return &user, nil
 }
 
-func (client *Client) AddPendingUser(user *NewUser) (*User, error) {
+func (client *Client) AddPendingUser(ctx context.Context, user *NewUser) (*User, error) {
 
 	body, err := EncodeToJSON(user)
 	if err != nil {
@@ -112,7 +113,7 @@ func (client *Client) AddPendingUser(user *NewUser) (*User, error) {
 		Body:   body,
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
 		return nil, err
 	}
@@ -128,9 +129,9 @@ func (client *Client) AddPendingUser(user *NewUser) (*User, error) {
 }
 
 // AddUserToTeamByAdmin - adds user to team with swich account
-func (client *Client) AddUserToTeamByAdmin(userID string, accountID string, team string) error {
+func (client *Client) AddUserToTeamByAdmin(ctx context.Context, userID string, accountID string, team string) error {
 	// get first accountAdmin and its token
-	account, err := client.GetAccountByID(accountID)
+	account, err := client.GetAccountByID(ctx, accountID)
 	if err != nil {
 		return err
 	}
@@ -139,13 +140,13 @@ func (client *Client) AddUserToTeamByAdmin(userID string, accountID string, team
 	}
 
 	accountAdminUserID := account.Admins[0]
-	accountAdminToken, err := client.GetXAccessToken(accountAdminUserID, accountID)
+	accountAdminToken, err := client.GetXAccessToken(ctx, accountAdminUserID, accountID)
 	if err != nil {
 		return err
 	}
 	// new Client for accountAdmin
-	accountAdminClient := NewClient(client.Host, "", accountAdminToken, "x-access-token")
-	usersTeam, err := accountAdminClient.GetTeamByName(team)
+	accountAdminClient := client.withToken(accountAdminToken, "x-access-token")
+	usersTeam, err := accountAdminClient.GetTeamByName(ctx, team)
 	if err != nil {
 		return err
 	}
@@ -161,19 +162,19 @@ func (client *Client) AddUserToTeamByAdmin(userID string, accountID string, team
 		}
 	}
 
-	err = accountAdminClient.AddUserToTeam(usersTeam.ID, userID)
+	err = accountAdminClient.AddUserToTeam(ctx, usersTeam.ID, userID)
 
 	return err
 }
 
-func (client *Client) ActivateUser(userId string) error {
+func (client *Client) ActivateUser(ctx context.Context, userId string) error {
 
 	opts := RequestOptions{
 		Path:   fmt.Sprintf("/admin/user/%s/activate", userId),
 		Method: "POST",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
@@ -182,14 +183,14 @@ func (client *Client) ActivateUser(userId string) error {
 	return nil
 }
 
-func (client *Client) SetUserAsAccountAdmin(accountId, userId string) error {
+func (client *Client) SetUserAsAccountAdmin(ctx context.Context, accountId, userId string) error {
 
 	opts := RequestOptions{
 		Path:   fmt.Sprintf("/accounts/%s/%s/admin", accountId, userId),
 		Method: "POST",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
 		return err
 	}
@@ -197,14 +198,14 @@ func (client *Client) SetUserAsAccountAdmin(accountId, userId string) error {
 	return nil
 }
 
-func (client *Client) DeleteUserAsAccountAdmin(accountId, userId string) error {
+func (client *Client) DeleteUserAsAccountAdmin(ctx context.Context, accountId, userId string) error {
 
 	opts := RequestOptions{
 		Path:   fmt.Sprintf("/accounts/%s/%s/admin", accountId, userId),
 		Method: "DELETE",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
 		return err
 	}
@@ -212,7 +213,7 @@ func (client *Client) DeleteUserAsAccountAdmin(accountId, userId string) error {
 	return nil
 }
 
-func (client *Client) GetAllUsers() (*[]User, error) {
+func (client *Client) GetAllUsers(ctx context.Context) (*[]User, error) {
 
 	limitPerQuery := 100
 	bIsDone := false
@@ -230,7 +231,7 @@ func (client *Client) GetAllUsers() (*[]User, error) {
 			Method: "GET",
 		}
 
-		resp, err := client.RequestAPI(&opts)
+		resp, err := client.RequestAPI(ctx, &opts)
 
 		if err != nil {
 			return nil, err
@@ -253,14 +254,14 @@ func (client *Client) GetAllUsers() (*[]User, error) {
 	return &allUsers, nil
 }
 
-func (client *Client) GetUserByID(userId string) (*User, error) {
+func (client *Client) GetUserByID(ctx context.Context, userId string) (*User, error) {
 
 	opts := RequestOptions{
 		Path:   fmt.Sprintf("/admin/user/id/%s", userId),
 		Method: "GET",
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return nil, err
@@ -278,7 +279,7 @@ func (client *Client) GetUserByID(userId string) (*User, error) {
 
 }
 
-func (client *Client) DeleteUser(userName string) error {
+func (client *Client) DeleteUser(ctx context.Context, userName string) error {
 
 	opts := RequestOptions{
 		Path:   fmt.Sprintf("/admin/user/%s", userName),
@@ -288,7 +289,7 @@ func (client *Client) DeleteUser(userName string) error {
 	// The API will return a 500 error if the user cannot be found
 	// In this case the DeleteUser function should not return an error.
 	// Return error only if the body of the return message does not contain "User does not exist"
-	res, err := client.RequestAPI(&opts)
+	res, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
 		if !strings.Contains(string(res), "User does not exist") {
 			return err
@@ -298,14 +299,14 @@ func (client *Client) DeleteUser(userName string) error {
 	return nil
 }
 
-func (client *Client) DeleteUserFromAccount(accountId, userId string) error {
+func (client *Client) DeleteUserFromAccount(ctx context.Context, accountId, userId string) error {
 
 	opts := RequestOptions{
 		Path:   fmt.Sprintf("/accounts/%s/%s", accountId, userId),
 		Method: "DELETE",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
 		return err
 	}
@@ -325,11 +326,11 @@ func ToSlimAccounts(accounts []Account) []Account {
 	return result
 }
 
-func (client *Client) UpdateUserAccounts(userId string, accounts []Account) error {
+func (client *Client) UpdateUserAccounts(ctx context.Context, userId string, accounts []Account) error {
 
 	// API call '/accounts/{accountId}/{userId}/adduser' doesn't work
 
-	user, err := client.GetUserByID(userId)
+	user, err := client.GetUserByID(ctx, userId)
 	if err != nil {
 		return err
 	}
@@ -352,7 +353,7 @@ func (client *Client) UpdateUserAccounts(userId string, accounts []Account) erro
 		Body:   body,
 	}
 
-	_, err = client.RequestAPI(&opts)
+	_, err = client.RequestAPI(ctx, &opts)
 	if err != nil {
 		return err
 	}
@@ -360,7 +361,7 @@ func (client *Client) UpdateUserAccounts(userId string, accounts []Account) erro
 	return nil
 }
 
-func (client *Client) UpdateUserDetails(accountId, userId, userName, userEmail string) (*User, error) {
+func (client *Client) UpdateUserDetails(ctx context.Context, accountId, userId, userName, userEmail string) (*User, error) {
 
 	fullPath := fmt.Sprintf("/accounts/%s/%s/updateuser", accountId, userId)
 	opts := RequestOptions{
@@ -369,7 +370,7 @@ func (client *Client) UpdateUserDetails(accountId, userId, userName, userEmail s
 		Body:   []byte(generateUserDetailsBody(userName, userEmail)),
 	}
 
-	resp, err := client.RequestAPI(&opts)
+	resp, err := client.RequestAPI(ctx, &opts)
 	if err != nil {
 		return nil, err
 	}
@@ -384,7 +385,7 @@ func (client *Client) UpdateUserDetails(accountId, userId, userName, userEmail s
 	return &respUser, nil
 }
 
-func (client *Client) UpdateLocalUserPassword(userName, password string) error {
+func (client *Client) UpdateLocalUserPassword(ctx context.Context, userName, password string) error {
 
 	fullPath := "/admin/user/localProvider"
 
@@ -396,7 +397,7 @@ func (client *Client) UpdateLocalUserPassword(userName, password string) error {
 		Body:   []byte(requestBody),
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
@@ -405,7 +406,7 @@ func (client *Client) UpdateLocalUserPassword(userName, password string) error {
 	return nil
 }
 
-func (client *Client) DeleteLocalUserPassword(userName string) error {
+func (client *Client) DeleteLocalUserPassword(ctx context.Context, userName string) error {
 
 	fullPath := fmt.Sprintf("/admin/user/localProvider?userName=%s", userName)
 
@@ -414,7 +415,7 @@ func (client *Client) DeleteLocalUserPassword(userName string) error {
 		Method: "DELETE",
 	}
 
-	_, err := client.RequestAPI(&opts)
+	_, err := client.RequestAPI(ctx, &opts)
 
 	if err != nil {
 		return err
@@ -12,6 +12,9 @@ type Variable struct {
 	Encrypted bool   `json:"encrypted,omitempty"`
 }
 
+// MaskedVariableValue is the value returned for encrypted variables that are not decrypted
+const MaskedVariableValue = "*****"
+
 // CodefreshObject codefresh interface
 type CodefreshObject interface {
 	GetID() string
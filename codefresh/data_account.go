@@ -1,16 +1,18 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceAccount() *schema.Resource {
 	return &schema.Resource{
 		Description: "This data source retrieves an account by _id or name. Requires a Codefresh admin token and applies only to Codefresh on-premises installations.",
-		Read:        dataSourceAccountRead,
+		ReadContext: dataSourceAccountRead,
 		Schema: map[string]*schema.Schema{
 			"_id": {
 				Type:     schema.TypeString,
@@ -31,28 +33,28 @@ func dataSourceAccount() *schema.Resource {
 	}
 }
 
-func dataSourceAccountRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceAccountRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 	var account *cfclient.Account
 	var err error
 
 	if _id, _idOk := d.GetOk("_id"); _idOk {
-		account, err = client.GetAccountByID(_id.(string))
+		account, err = client.GetAccountByID(ctx, _id.(string))
 	} else if name, nameOk := d.GetOk("name"); nameOk {
-		account, err = client.GetAccountByName(name.(string))
+		account, err = client.GetAccountByName(ctx, name.(string))
 	} else {
-		return fmt.Errorf("data.codefresh_account - must specify _id or name")
+		return diag.Errorf("data.codefresh_account - must specify _id or name")
 	}
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if account == nil {
-		return fmt.Errorf("data.codefresh_account - cannot find account")
+		return diag.Errorf("data.codefresh_account - cannot find account")
 	}
 
-	return mapDataAccountToResource(account, d)
+	return diag.FromErr(mapDataAccountToResource(account, d))
 }
 
 func mapDataAccountToResource(account *cfclient.Account, d *schema.ResourceData) error {
@@ -1,16 +1,18 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceAccountGitopsSettings() *schema.Resource {
 	return &schema.Resource{
 		Description: "This data source retrieves gitops settings for the active account",
-		Read:        dataSourceAccountGitopsSettingsRead,
+		ReadContext: dataSourceAccountGitopsSettingsRead,
 		Schema: map[string]*schema.Schema{
 			"id": {
 				Type:        schema.TypeString,
@@ -48,18 +50,18 @@ func dataSourceAccountGitopsSettings() *schema.Resource {
 	}
 }
 
-func dataSourceAccountGitopsSettingsRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceAccountGitopsSettingsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 	var accountGitopsInfo *cfclient.GitopsActiveAccountInfo
 
-	accountGitopsInfo, err := client.GetActiveGitopsAccountInfo()
+	accountGitopsInfo, err := client.GetActiveGitopsAccountInfo(ctx)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return mapDataAccountGitopsSettingsToResource(accountGitopsInfo, d)
+	return diag.FromErr(mapDataAccountGitopsSettingsToResource(accountGitopsInfo, d))
 }
 
 func mapDataAccountGitopsSettingsToResource(account *cfclient.GitopsActiveAccountInfo, d *schema.ResourceData) error {
@@ -1,16 +1,17 @@
 package codefresh
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceAccountIdp() *schema.Resource {
 	return &schema.Resource{
 		Description: "This data source retrieves an account level identity provider",
-		Read:        dataSourceAccountIdpRead,
+		ReadContext: dataSourceAccountIdpRead,
 		Schema:      AccountIdpSchema(),
 	}
 }
@@ -54,13 +55,13 @@ func AccountIdpSchema() map[string]*schema.Schema {
 	}
 }
 
-func dataSourceAccountIdpRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceAccountIdpRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
-	idps, err := client.GetAccountIDPs()
+	idps, err := client.GetAccountIDPs(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	_id, _idOk := d.GetOk("_id")
@@ -76,12 +77,12 @@ func dataSourceAccountIdpRead(d *schema.ResourceData, meta interface{}) error {
 
 		err = mapDataAccountIdpToResource(idp, d)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	if d.Id() == "" {
-		return fmt.Errorf("[EROOR] Idp wasn't found")
+		return diag.Errorf("[EROOR] Idp wasn't found")
 	}
 
 	return nil
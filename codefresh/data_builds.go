@@ -0,0 +1,224 @@
+package codefresh
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceBuilds() *schema.Resource {
+	return &schema.Resource{
+		Description: "This data source retrieves the most recent builds, optionally filtered by pipeline, branch, status, trigger and creation time. For example, to check that the last build of a pipeline on a branch succeeded.",
+		ReadContext: dataSourceBuildsRead,
+		Schema: map[string]*schema.Schema{
+			"pipeline_id": {
+				Description: "Only return the builds of the pipeline with this ID, for example from the `codefresh_pipelines` data source.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"branch": {
+				Description: "Only return the builds of this branch.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"status": {
+				Description:  fmt.Sprintf("Only return the builds with this status, one of `%s`.", strings.Join(cfclient.BuildStatuses, "`, `")),
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(cfclient.BuildStatuses, false),
+			},
+			"trigger": {
+				Description: "Only return the builds started by this type of trigger, for example `build` for builds run manually or through the API, `webhook` or `cron`.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"since": {
+				Description:  "Only return the builds created at or after this RFC 3339 time.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"until": {
+				Description:  "Only return the builds created at or before this RFC 3339 time.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"limit": {
+				Description:  "The maximum number of builds to return.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validation.IntBetween(1, 1000),
+			},
+			"builds": {
+				Description: "The returned builds, the most recent first.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Description: "The ID of the build.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"pipeline_id": {
+							Description: "The ID of the pipeline of the build.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"pipeline_name": {
+							Description: "The name of the pipeline of the build.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"branch": {
+							Description: "The branch of the build.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"revision": {
+							Description: "The commit SHA of the build.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"status": {
+							Description: "The status of the build.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"trigger": {
+							Description: "The type of trigger that started the build, for example `build`, `webhook` or `cron`.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"created": {
+							Description: "The RFC 3339 time the build was created at.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"started": {
+							Description: "The RFC 3339 time the build started at, empty until it started.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"finished": {
+							Description: "The RFC 3339 time the build finished at, empty until it is finished.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"duration": {
+							Description: "The number of seconds the build ran for, 0 until it is finished.",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"total_duration": {
+							Description: "The number of seconds from the creation of the build until it finished, including the time it waited to start, 0 until it is finished.",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"images": {
+							Description: "The names of the images built.",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"url": {
+							Description: "The URL of the build in the Codefresh UI.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceBuildsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+
+	client := meta.(*cfclient.Client)
+
+	filter := cfclient.BuildsFilter{
+		PipelineID: d.Get("pipeline_id").(string),
+		Branch:     d.Get("branch").(string),
+		Status:     d.Get("status").(string),
+		Trigger:    d.Get("trigger").(string),
+		Limit:      d.Get("limit").(int),
+	}
+	// the times are validated by the schema
+	if since, ok := d.GetOk("since"); ok {
+		filter.Since, _ = time.Parse(time.RFC3339, since.(string))
+	}
+	if until, ok := d.GetOk("until"); ok {
+		filter.Until, _ = time.Parse(time.RFC3339, until.(string))
+	}
+
+	builds, err := client.GetBuilds(ctx, &filter)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = mapDataBuildsToResource(builds, client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(time.Now().UTC().String())
+
+	return nil
+}
+
+func mapDataBuildsToResource(builds []cfclient.PipelineBuild, client *cfclient.Client, d *schema.ResourceData) error {
+	var res = make([]map[string]interface{}, len(builds))
+	for i, build := range builds {
+		m := make(map[string]interface{})
+		m["id"] = build.ID
+		m["pipeline_id"] = build.PipelineID
+		m["pipeline_name"] = build.PipelineName
+		m["branch"] = build.Branch
+		m["revision"] = build.Revision
+		m["status"] = build.Status
+		m["trigger"] = build.Trigger
+		m["created"] = formatBuildTime(build.Created)
+		m["started"] = formatBuildTime(build.Started)
+		m["finished"] = formatBuildTime(build.Finished)
+		// durations are only known once the build is finished
+		if !build.Finished.IsZero() {
+			if !build.Started.IsZero() {
+				m["duration"] = int(build.Finished.Sub(build.Started).Seconds())
+			}
+			if !build.Created.IsZero() {
+				m["total_duration"] = int(build.Finished.Sub(build.Created).Seconds())
+			}
+		}
+		m["images"] = datautil.FlattenStringArr(build.Images)
+		m["url"] = client.BuildURL(build.ID)
+
+		res[i] = m
+	}
+
+	err := d.Set("builds", res)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// formatBuildTime returns t in RFC 3339, or an empty string if not set
+func formatBuildTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
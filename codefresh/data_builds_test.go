@@ -0,0 +1,72 @@
+package codefresh
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceBuildsFiltersByPipelineBranchAndStatus(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+	client := cfclient.NewClient(server.APIURL(), server.GraphQLURL(), fakeapi.APIKey, "")
+	ctx := context.Background()
+
+	pipeline, err := client.CreatePipeline(ctx, &cfclient.Pipeline{Metadata: cfclient.Metadata{Name: "project/app"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	other, err := client.CreatePipeline(ctx, &cfclient.Pipeline{Metadata: cfclient.Metadata{Name: "project/other"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// run builds to completion, the fake server moves a build to its next status on each read
+	run := func(pipelineID, branch, result string) string {
+		server.BuildResult = result
+		id, err := client.RunPipeline(ctx, pipelineID, &cfclient.BuildRequest{Branch: branch})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := 0; i < 2; i++ {
+			if _, err := client.GetBuild(ctx, id); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		return id
+	}
+	run(pipeline.GetID(), "main", "success")
+	failed := run(pipeline.GetID(), "main", "error")
+	run(pipeline.GetID(), "feature/x", "success")
+	run(other.GetID(), "main", "success")
+
+	dataSource := dataSourceBuilds()
+	d := schema.TestResourceDataRaw(t, dataSource.Schema, map[string]interface{}{
+		"pipeline_id": pipeline.GetID(),
+		"branch":      "main",
+		"limit":       1,
+	})
+	if diags := dataSource.ReadContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Get("builds.#") != 1 || d.Get("builds.0.id") != failed || d.Get("builds.0.status") != "error" || d.Get("builds.0.pipeline_name") != "project/app" {
+		t.Fatalf("expected the last build of the pipeline on main, got %v", d.Get("builds"))
+	}
+	if d.Get("builds.0.finished") == "" || d.Get("builds.0.url") != client.BuildURL(failed) {
+		t.Fatalf("unexpected build %v", d.Get("builds.0"))
+	}
+
+	d = schema.TestResourceDataRaw(t, dataSource.Schema, map[string]interface{}{
+		"pipeline_id": pipeline.GetID(),
+		"status":      "success",
+	})
+	if diags := dataSource.ReadContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Get("builds.#") != 2 || d.Get("builds.0.branch") != "feature/x" || d.Get("builds.1.branch") != "main" {
+		t.Fatalf("expected the successful builds of the pipeline, the most recent first, got %v", d.Get("builds"))
+	}
+}
@@ -1,17 +1,19 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
 	"github.com/ghodss/yaml"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceContext() *schema.Resource {
 	return &schema.Resource{
 		Description: "This data source allows to retrieve information on any defined context.",
-		Read:        dataSourceContextRead,
+		ReadContext: dataSourceContextRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -29,26 +31,26 @@ func dataSourceContext() *schema.Resource {
 	}
 }
 
-func dataSourceContextRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceContextRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 	var context *cfclient.Context
 	var err error
 
 	if name, nameOk := d.GetOk("name"); nameOk {
-		context, err = client.GetContext(name.(string))
+		context, err = client.GetContext(ctx, name.(string))
 	} else {
-		return fmt.Errorf("data.codefresh_context - must specify name")
+		return diag.Errorf("data.codefresh_context - must specify name")
 	}
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if context == nil {
-		return fmt.Errorf("data.codefresh_context - cannot find context")
+		return diag.Errorf("data.codefresh_context - cannot find context")
 	}
 
-	return mapDataContextToResource(context, d)
+	return diag.FromErr(mapDataContextToResource(context, d))
 }
 
 func mapDataContextToResource(context *cfclient.Context, d *schema.ResourceData) error {
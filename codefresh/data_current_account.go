@@ -1,16 +1,18 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceCurrentAccount() *schema.Resource {
 	return &schema.Resource{
 		Description: "Returns the current account (owner of the token) and its users.",
-		Read:        dataSourceCurrentAccountRead,
+		ReadContext: dataSourceCurrentAccountRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -44,21 +46,21 @@ func dataSourceCurrentAccount() *schema.Resource {
 	}
 }
 
-func dataSourceCurrentAccountRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceCurrentAccountRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 	var currentAccount *cfclient.CurrentAccount
 	var err error
 
-	currentAccount, err = client.GetCurrentAccount()
+	currentAccount, err = client.GetCurrentAccount(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if currentAccount == nil {
-		return fmt.Errorf("data.codefresh_current_account - failed to get current_account")
+		return diag.Errorf("data.codefresh_current_account - failed to get current_account")
 	}
 
-	return mapDataCurrentAccountToResource(currentAccount, d)
+	return diag.FromErr(mapDataCurrentAccountToResource(currentAccount, d))
 
 }
 
@@ -1,16 +1,18 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceCurrentAccountUser() *schema.Resource {
 	return &schema.Resource{
 		Description: "Returns a user the current Codefresh account by name or email.",
-		Read:        dataSourceCurrentAccountUserRead,
+		ReadContext: dataSourceCurrentAccountUserRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:         schema.TypeString,
@@ -26,19 +28,19 @@ func dataSourceCurrentAccountUser() *schema.Resource {
 	}
 }
 
-func dataSourceCurrentAccountUserRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceCurrentAccountUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 	var currentAccount *cfclient.CurrentAccount
 	var err error
 
-	currentAccount, err = client.GetCurrentAccount()
+	currentAccount, err = client.GetCurrentAccount(ctx)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if currentAccount == nil {
-		return fmt.Errorf("data.codefresh_current_account - failed to get current_account")
+		return diag.Errorf("data.codefresh_current_account - failed to get current_account")
 	}
 
 	var (
@@ -53,10 +55,10 @@ func dataSourceCurrentAccountUserRead(d *schema.ResourceData, meta interface{})
 		userAttributeName = "name"
 		userAttributeValue = _name.(string)
 	} else {
-		return fmt.Errorf("data.codefresh_current_account_user - must specify name or email")
+		return diag.Errorf("data.codefresh_current_account_user - must specify name or email")
 	}
 
-	return mapDataCurrentAccountUserToResource(currentAccount, d, userAttributeName, userAttributeValue)
+	return diag.FromErr(mapDataCurrentAccountUserToResource(currentAccount, d, userAttributeName, userAttributeValue))
 
 }
 
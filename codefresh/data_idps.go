@@ -1,17 +1,18 @@
 package codefresh
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceIdps() *schema.Resource {
 	return &schema.Resource{
 		Description: "This data source retrieves all Identity Providers (IdPs) in the system. Requires a Codefresh admin token and applies only to Codefresh on-premises installations.",
-		Read:        dataSourceIdpRead,
+		ReadContext: dataSourceIdpRead,
 		Schema:      IdpSchema(),
 	}
 }
@@ -84,13 +85,13 @@ func IdpSchema() map[string]*schema.Schema {
 	}
 }
 
-func dataSourceIdpRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceIdpRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
-	idps, err := client.GetIDPs()
+	idps, err := client.GetIDPs(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	_id, _idOk := d.GetOk("_id")
@@ -99,7 +100,7 @@ func dataSourceIdpRead(d *schema.ResourceData, meta interface{}) error {
 	clientType, clientTypeOk := d.GetOk("client_type")
 
 	if !(_idOk || clientNameOk || displayNameOk || clientTypeOk) {
-		return fmt.Errorf("[ERROR] data.codefresh_idp - no parameters specified")
+		return diag.Errorf("[ERROR] data.codefresh_idp - no parameters specified")
 	}
 	for _, idp := range *idps {
 		if clientNameOk && clientName.(string) != idp.ClientName {
@@ -116,12 +117,12 @@ func dataSourceIdpRead(d *schema.ResourceData, meta interface{}) error {
 		}
 		err = mapDataIdpToResource(idp, d)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	if d.Id() == "" {
-		return fmt.Errorf("[EROOR] Idp wasn't found")
+		return diag.Errorf("[EROOR] Idp wasn't found")
 	}
 
 	return nil
@@ -1,18 +1,20 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"time"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourcePipelines() *schema.Resource {
 	return &schema.Resource{
 		Description: "This resource retrives all pipelines belonging to the current user, which can be optionally filtered by the name.",
-		Read:        dataSourcePipelinesRead,
+		ReadContext: dataSourcePipelinesRead,
 		Schema: map[string]*schema.Schema{
 			"name_regex": {
 				Description: "The name regular expression to filter pipelines by.",
@@ -57,18 +59,18 @@ func dataSourcePipelines() *schema.Resource {
 	}
 }
 
-func dataSourcePipelinesRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourcePipelinesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
-	pipelines, err := client.GetPipelines()
+	pipelines, err := client.GetPipelines(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	err = mapDataPipelinesToResource(*pipelines, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(time.Now().UTC().String())
@@ -1,16 +1,18 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 
 	cfClient "github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceProject() *schema.Resource {
 	return &schema.Resource{
 		Description: "This data source retrieves a project by its ID or name.",
-		Read:        dataSourceProjectRead,
+		ReadContext: dataSourceProjectRead,
 		Schema: map[string]*schema.Schema{
 			"_id": {
 				Type:     schema.TypeString,
@@ -31,27 +33,27 @@ func dataSourceProject() *schema.Resource {
 	}
 }
 
-func dataSourceProjectRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceProjectRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfClient.Client)
 	var project *cfClient.Project
 	var err error
 
 	if _id, _idOk := d.GetOk("_id"); _idOk {
-		project, err = client.GetProjectByID(_id.(string))
+		project, err = client.GetProjectByID(ctx, _id.(string))
 	} else if name, nameOk := d.GetOk("name"); nameOk {
-		project, err = client.GetProjectByName(name.(string))
+		project, err = client.GetProjectByName(ctx, name.(string))
 	}
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if project == nil {
-		return fmt.Errorf("data.codefresh_project - cannot find project")
+		return diag.Errorf("data.codefresh_project - cannot find project")
 	}
 
-	return mapDataProjectToResource(project, d)
+	return diag.FromErr(mapDataProjectToResource(project, d))
 
 }
 
@@ -1,16 +1,18 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceRegistry() *schema.Resource {
 	return &schema.Resource{
 		Description: "This data source allows retrieving information on any existing registry.",
-		Read:        dataSourceRegistryRead,
+		ReadContext: dataSourceRegistryRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -48,26 +50,26 @@ func dataSourceRegistry() *schema.Resource {
 	}
 }
 
-func dataSourceRegistryRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceRegistryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 	var registry *cfclient.Registry
 	var err error
 
 	if name, nameOk := d.GetOk("name"); nameOk {
-		registry, err = client.GetRegistry(name.(string))
+		registry, err = client.GetRegistry(ctx, name.(string))
 	} else {
-		return fmt.Errorf("data.codefresh_registry - must specify name")
+		return diag.Errorf("data.codefresh_registry - must specify name")
 	}
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if registry == nil {
-		return fmt.Errorf("data.codefresh_registry - cannot find registry")
+		return diag.Errorf("data.codefresh_registry - cannot find registry")
 	}
 
-	return mapDataRegistryToResource(registry, d)
+	return diag.FromErr(mapDataRegistryToResource(registry, d))
 }
 
 func mapDataRegistryToResource(registry *cfclient.Registry, d *schema.ResourceData) error {
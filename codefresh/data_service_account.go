@@ -1,16 +1,18 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 
 	cfClient "github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceServiceAccount() *schema.Resource {
 	return &schema.Resource{
 		Description: "This data source retrieves a Codefresh service account by its ID or name.",
-		Read:        dataSourceServiceAccountRead,
+		ReadContext: dataSourceServiceAccountRead,
 		Schema: map[string]*schema.Schema{
 			"_id": {
 				Type:     schema.TypeString,
@@ -39,27 +41,27 @@ func dataSourceServiceAccount() *schema.Resource {
 	}
 }
 
-func dataSourceServiceAccountRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceServiceAccountRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfClient.Client)
 	var serviceAccount *cfClient.ServiceUser
 	var err error
 
 	if _id, _idOk := d.GetOk("_id"); _idOk {
-		serviceAccount, err = client.GetServiceUserByID(_id.(string))
+		serviceAccount, err = client.GetServiceUserByID(ctx, _id.(string))
 	} else if name, nameOk := d.GetOk("name"); nameOk {
-		serviceAccount, err = client.GetServiceUserByName(name.(string))
+		serviceAccount, err = client.GetServiceUserByName(ctx, name.(string))
 	}
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if serviceAccount == nil {
-		return fmt.Errorf("data.codefresh_service_account - cannot find service account")
+		return diag.Errorf("data.codefresh_service_account - cannot find service account")
 	}
 
-	return mapDataServiceAccountToResource(serviceAccount, d)
+	return diag.FromErr(mapDataServiceAccountToResource(serviceAccount, d))
 
 }
 
@@ -1,17 +1,18 @@
 package codefresh
 
 import (
-	"fmt"
+	"context"
 	"log"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceStepTypes() *schema.Resource {
 	return &schema.Resource{
 		Description: "This data source allows to retrieve the published versions of step-types.",
-		Read:        dataSourceStepTypesRead,
+		ReadContext: dataSourceStepTypesRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -37,7 +38,7 @@ func dataSourceStepTypes() *schema.Resource {
 	}
 }
 
-func dataSourceStepTypesRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceStepTypesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 	var err error
@@ -45,17 +46,17 @@ func dataSourceStepTypesRead(d *schema.ResourceData, meta interface{}) error {
 	stepTypesIdentifier := d.Get("name").(string)
 
 	d.SetId(stepTypesIdentifier)
-	if versions, err = client.GetStepTypesVersions(stepTypesIdentifier); err == nil {
+	if versions, err = client.GetStepTypesVersions(ctx, stepTypesIdentifier); err == nil {
 		var stepVersions cfclient.StepTypesVersions
 		stepVersions.Name = stepTypesIdentifier
 		err = d.Set("versions", versions)
 
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		for _, version := range versions {
-			stepTypes, err := client.GetStepTypes(stepTypesIdentifier + ":" + version)
+			stepTypes, err := client.GetStepTypes(ctx, stepTypesIdentifier+":"+version)
 			if err != nil {
 				log.Printf("[DEBUG] Skipping version %v due to error %v", version, err)
 			} else {
@@ -66,10 +67,10 @@ func dataSourceStepTypesRead(d *schema.ResourceData, meta interface{}) error {
 				stepVersions.Versions = append(stepVersions.Versions, stepVersion)
 			}
 		}
-		return mapStepTypesVersionsToResource(stepVersions, d)
+		return diag.FromErr(mapStepTypesVersionsToResource(stepVersions, d))
 	}
 
-	return fmt.Errorf("data.codefresh_step_types - was unable to retrieve the versions for step_type %s", stepTypesIdentifier)
+	return diag.Errorf("data.codefresh_step_types - was unable to retrieve the versions for step_type %s", stepTypesIdentifier)
 
 }
 
@@ -1,16 +1,18 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceTeam() *schema.Resource {
 	return &schema.Resource{
 		Description: "This data source retrieves a team by its ID or name.",
-		Read:        dataSourceTeamRead,
+		ReadContext: dataSourceTeamRead,
 		Schema: map[string]*schema.Schema{
 			"_id": {
 				Type:     schema.TypeString,
@@ -46,28 +48,28 @@ func dataSourceTeam() *schema.Resource {
 	}
 }
 
-func dataSourceTeamRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceTeamRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 	var team *cfclient.Team
 	var err error
 
 	if _id, _idOk := d.GetOk("_id"); _idOk {
-		team, err = client.GetTeamByID(_id.(string))
+		team, err = client.GetTeamByID(ctx, _id.(string))
 	} else if name, nameOk := d.GetOk("name"); nameOk {
 		// accountID, accountOk := d.GetOk("account_id");
-		team, err = client.GetTeamByName(name.(string))
+		team, err = client.GetTeamByName(ctx, name.(string))
 	}
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if team == nil {
-		return fmt.Errorf("data.codefresh_team - cannot find team")
+		return diag.Errorf("data.codefresh_team - cannot find team")
 	}
 
-	return mapDataTeamToResource(team, d)
+	return diag.FromErr(mapDataTeamToResource(team, d))
 
 }
 
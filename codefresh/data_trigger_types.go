@@ -0,0 +1,105 @@
+package codefresh
+
+import (
+	"context"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/triggertypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceTriggerTypes() *schema.Resource {
+	return &schema.Resource{
+		Description: "This data source lists the trigger events supported by the provider: the types and kinds of Hermes events, with the values they require, and the events of git triggers. Trigger events are checked against this catalog when planning: events missing from it only raise a warning, as Codefresh may support events the provider does not know about.",
+		ReadContext: dataSourceTriggerTypesRead,
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Description: "Only list the trigger types of this type, for example `registry`.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"trigger_types": {
+				Description: "The supported types and kinds of Hermes trigger events.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Description: "The type of the event.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"kind": {
+							Description: "The kind of the event.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"description": {
+							Description: "The description of the event.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"values": {
+							Description: "The names of the `values` required by the `codefresh_trigger_event` resource for this type and kind.",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"uri_template": {
+							Description: "The template of the URI of the events, Codefresh may append a suffix such as the account.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"git_events": {
+				Description: "The supported events of git triggers, for the `events` of the `trigger` blocks of the `codefresh_pipeline` resource.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTriggerTypesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+
+	eventType := d.Get("type").(string)
+
+	triggerTypes := make([]map[string]interface{}, 0)
+	for _, t := range triggertypes.HermesTypes() {
+		if eventType != "" && t.Type != eventType {
+			continue
+		}
+		triggerTypes = append(triggerTypes, map[string]interface{}{
+			"type":         t.Type,
+			"kind":         t.Kind,
+			"description":  t.Description,
+			"values":       datautil.FlattenStringArr(t.Values),
+			"uri_template": t.URITemplate(),
+		})
+	}
+
+	err := d.Set("trigger_types", triggerTypes)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = d.Set("git_events", triggertypes.GitEvents())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("trigger-types")
+	if eventType != "" {
+		d.SetId("trigger-types/" + eventType)
+	}
+
+	return nil
+}
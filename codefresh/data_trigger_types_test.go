@@ -0,0 +1,67 @@
+package codefresh
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceTriggerTypesFiltersByType(t *testing.T) {
+	dataSource := dataSourceTriggerTypes()
+	d := schema.TestResourceDataRaw(t, dataSource.Schema, map[string]interface{}{"type": "registry"})
+	if diags := dataSource.ReadContext(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	kinds := []string{}
+	for _, triggerType := range d.Get("trigger_types").([]interface{}) {
+		kinds = append(kinds, triggerType.(map[string]interface{})["kind"].(string))
+	}
+	if expected := []string{"dockerhub", "quay", "azure", "jfrog", "ecr"}; !slices.Equal(kinds, expected) {
+		t.Fatalf("expected the registry kinds %v, got %v", expected, kinds)
+	}
+	if d.Get("trigger_types.0.uri_template") != "registry:dockerhub:{{namespace}}:{{name}}:{{action}}" || d.Get("trigger_types.0.values.#") != 3 {
+		t.Fatalf("unexpected trigger type %v", d.Get("trigger_types.0"))
+	}
+	if !slices.Contains(d.Get("git_events").([]interface{}), "push.heads") {
+		t.Fatalf("expected push.heads in the git events, got %v", d.Get("git_events"))
+	}
+}
+
+func TestTriggerEventsAreValidatedAgainstTheCatalog(t *testing.T) {
+	gitEvent := resourcePipeline().Schema["spec"].Elem.(*schema.Resource).Schema["trigger"].Elem.(*schema.Resource).Schema["events"].Elem.(*schema.Schema)
+	if diags := gitEvent.ValidateDiagFunc("push.heads", cty.Path{}); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	// events unknown to the provider may be supported by Codefresh
+	if diags := gitEvent.ValidateDiagFunc("push.head", cty.Path{}); diags.HasError() || len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("expected a warning about an unknown git event, got %v", diags)
+	}
+
+	event := resourcePipelineEventTrigger().Schema["event"]
+	if diags := event.ValidateDiagFunc("registry:ecr:eu-west-1:app:push:5f1a", cty.Path{}); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if diags := event.ValidateDiagFunc("registry:dockerhub:app", cty.Path{}); !diags.HasError() {
+		t.Fatal("expected an event URI without its values to be rejected")
+	}
+	if diags := event.ValidateDiagFunc("registry:gcr:app:push", cty.Path{}); diags.HasError() || len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("expected a warning about an unknown event type, got %v", diags)
+	}
+
+	triggerEvent := resourceTriggerEvent()
+	for eventType, expectWarning := range map[string]bool{"registry": false, "artifact": true} {
+		config := cty.ObjectVal(map[string]cty.Value{"type": cty.StringVal(eventType), "kind": cty.StringVal("dockerhub")})
+		resp := &schema.ValidateResourceConfigFuncResponse{}
+		for _, validate := range triggerEvent.ValidateRawResourceConfigFuncs {
+			validate(context.Background(), schema.ValidateResourceConfigFuncRequest{RawConfig: config}, resp)
+		}
+		if resp.Diagnostics.HasError() || (len(resp.Diagnostics) == 1) != expectWarning {
+			t.Fatalf("unexpected diagnostics for the type %s: %v", eventType, resp.Diagnostics)
+		}
+	}
+}
@@ -1,27 +1,28 @@
 package codefresh
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceUser() *schema.Resource {
 	return &schema.Resource{
 		Description: "This data source retrieves a user by email. Requires a Codefresh admin token and applies only to Codefresh on-premises installations.",
-		Read:        dataSourceUserRead,
+		ReadContext: dataSourceUserRead,
 		Schema:      *UserSchema(),
 	}
 }
 
-func dataSourceUserRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
-	users, err := client.GetAllUsers()
+	users, err := client.GetAllUsers(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	email := d.Get("email").(string)
@@ -30,13 +31,13 @@ func dataSourceUserRead(d *schema.ResourceData, meta interface{}) error {
 		if user.Email == email {
 			err = mapDataUserToResource(user, d)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 	}
 
 	if d.Id() == "" {
-		return fmt.Errorf("[EROOR] User %s wasn't found", email)
+		return diag.Errorf("[EROOR] User %s wasn't found", email)
 	}
 
 	return nil
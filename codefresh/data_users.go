@@ -1,16 +1,18 @@
 package codefresh
 
 import (
+	"context"
 	"time"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceUsers() *schema.Resource {
 	return &schema.Resource{
 		Description: "This data source retrieves all users in the system. Requires a Codefresh admin token and applies only to Codefresh on-premises installations.",
-		Read:        dataSourceUsersRead,
+		ReadContext: dataSourceUsersRead,
 		Schema: map[string]*schema.Schema{
 			"users": {
 				Type:     schema.TypeList,
@@ -23,18 +25,18 @@ func dataSourceUsers() *schema.Resource {
 	}
 }
 
-func dataSourceUsersRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceUsersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
-	users, err := client.GetAllUsers()
+	users, err := client.GetAllUsers(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	err = mapDataUsersToResource(*users, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(time.Now().UTC().String())
@@ -0,0 +1,119 @@
+package codefresh
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// maskedVariableValue is the value Codefresh returns for encrypted variables that are not decrypted
+const maskedVariableValue = cfclient.MaskedVariableValue
+
+// encryptedVariableDriftMarker replaces in state the value of an encrypted variable changed outside of Terraform,
+// so that the plan sets it back to its configured value without the new value being written to state
+const encryptedVariableDriftMarker = "(changed outside of Terraform)"
+
+func encryptedVariablesFingerprintsSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "The salted SHA-256 fingerprints of the encrypted variables last applied. They are compared with the decrypted values read from Codefresh to detect the encrypted variables changed outside of Terraform, which are then shown as changed in the plan. Drift is not detected when the account forbids decryption.",
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+// fingerprintEncryptedVariable returns a salted SHA-256 hash of value, as the hex encoded salt and hash separated by a colon
+func fingerprintEncryptedVariable(value string) string {
+	salt := make([]byte, 16)
+	// rand.Read never returns an error
+	_, _ = rand.Read(salt)
+	return hashEncryptedVariable(salt, value)
+}
+
+func hashEncryptedVariable(salt []byte, value string) string {
+	hash := sha256.Sum256(append(salt, value...))
+	return fmt.Sprintf("%s:%s", hex.EncodeToString(salt), hex.EncodeToString(hash[:]))
+}
+
+// matchesEncryptedVariableFingerprint returns true if fingerprint is the fingerprint of value
+func matchesEncryptedVariableFingerprint(fingerprint, value string) bool {
+	encodedSalt, _, ok := strings.Cut(fingerprint, ":")
+	if !ok {
+		return false
+	}
+	salt, err := hex.DecodeString(encodedSalt)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hashEncryptedVariable(salt, value)), []byte(fingerprint)) == 1
+}
+
+// encryptedVariablesFingerprints tracks the fingerprints of the encrypted variables of a resource, in its encrypted_variables_fingerprints attribute.
+// Each variable is identified by a prefix, e.g. the trigger it belongs to, followed by its key.
+type encryptedVariablesFingerprints struct {
+	previous map[string]interface{}
+	current  map[string]interface{}
+}
+
+func newEncryptedVariablesFingerprints(d *schema.ResourceData) *encryptedVariablesFingerprints {
+	return &encryptedVariablesFingerprints{
+		previous: d.Get("encrypted_variables_fingerprints").(map[string]interface{}),
+		current:  map[string]interface{}{},
+	}
+}
+
+// apply records the fingerprints of the encrypted variables about to be applied, keeping the ones that still match their value
+func (f *encryptedVariablesFingerprints) apply(prefix string, variables map[string]interface{}) {
+	for key, value := range variables {
+		id := prefix + key
+		if fingerprint, ok := f.previous[id].(string); ok && matchesEncryptedVariableFingerprint(fingerprint, value.(string)) {
+			f.current[id] = fingerprint
+		} else {
+			f.current[id] = fingerprintEncryptedVariable(value.(string))
+		}
+	}
+}
+
+// read replaces the values read from Codefresh for the encrypted variables with their values in state, at schemaPath.
+// A decrypted value that does not match the fingerprint of the value last applied is replaced with encryptedVariableDriftMarker instead.
+func (f *encryptedVariablesFingerprints) read(d *schema.ResourceData, prefix string, schemaPath string, variables map[string]string) {
+	for key, value := range variables {
+		id := prefix + key
+		stateValue := d.Get(fmt.Sprintf("%s.%s", schemaPath, key)).(string)
+		fingerprint, hasFingerprint := f.previous[id].(string)
+
+		switch {
+		case value == maskedVariableValue:
+			variables[key] = stateValue
+		case hasFingerprint:
+			variables[key] = stateValue
+			if !matchesEncryptedVariableFingerprint(fingerprint, value) {
+				variables[key] = encryptedVariableDriftMarker
+			}
+		case value == stateValue:
+			// applied before fingerprints were recorded
+			variables[key] = stateValue
+			fingerprint, hasFingerprint = fingerprintEncryptedVariable(value), true
+		case stateValue != "":
+			variables[key] = encryptedVariableDriftMarker
+		default:
+			variables[key] = stateValue
+		}
+
+		if hasFingerprint {
+			f.current[id] = fingerprint
+		}
+	}
+}
+
+func (f *encryptedVariablesFingerprints) set(d *schema.ResourceData) error {
+	return d.Set("encrypted_variables_fingerprints", f.current)
+}
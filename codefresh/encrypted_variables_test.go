@@ -0,0 +1,19 @@
+package codefresh
+
+import "testing"
+
+func TestEncryptedVariableFingerprints(t *testing.T) {
+	fingerprint := fingerprintEncryptedVariable("s3cr3t")
+	if !matchesEncryptedVariableFingerprint(fingerprint, "s3cr3t") {
+		t.Fatalf("expected %s to match its value", fingerprint)
+	}
+	if matchesEncryptedVariableFingerprint(fingerprint, "rotated") {
+		t.Fatalf("expected %s not to match another value", fingerprint)
+	}
+	if other := fingerprintEncryptedVariable("s3cr3t"); other == fingerprint {
+		t.Fatalf("expected fingerprints of the same value to be salted differently, got %s twice", fingerprint)
+	}
+	if matchesEncryptedVariableFingerprint("not a fingerprint", "s3cr3t") {
+		t.Fatal("expected an invalid fingerprint not to match")
+	}
+}
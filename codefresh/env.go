@@ -6,6 +6,10 @@ const (
 	ENV_CODEFRESH_API_URL         = "CODEFRESH_API_URL"
 	ENV_CODEFRESH_API2_URL        = "CODEFRESH_API2_URL"
 	ENV_CODEFRESH_API_KEY         = "CODEFRESH_API_KEY"
+	ENV_CODEFRESH_CONFIG_PATH     = "CODEFRESH_CONFIG_PATH"
+	ENV_CODEFRESH_CONFIG_CONTEXT  = "CODEFRESH_CONFIG_CONTEXT"
+	ENV_CODEFRESH_FAKE_API        = "CODEFRESH_FAKE_API"
+	ENV_CODEFRESH_RECORD          = "CODEFRESH_RECORD_CASSETTES"
 	DEFAULT_CODEFRESH_API_URL     = "https://g.codefresh.io/api"
 	DEFAULT_CODEFRESH_API2_URL    = "https://g.codefresh.io/2.0/api/graphql"
 	DEFAULT_CODEFRESH_PLUGIN_ADDR = "registry.terraform.io/codefresh-io/codefresh"
@@ -0,0 +1,317 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Mode selects whether a Transport records or replays interactions
+type Mode int
+
+const (
+	// Replay answers requests from the cassette and fails requests that were not recorded
+	Replay Mode = iota
+	// Record sends requests to the API and stores the sanitized interactions in the cassette
+	Record
+)
+
+// redactedValue replaces sensitive values in cassettes
+const redactedValue = "REDACTED"
+
+// sensitiveKeySuffixes are matched, case insensitively, against the end of JSON keys to find values that must not be recorded.
+// Suffixes are used instead of substrings so that keys like tokenURL or tokenPrefix are kept.
+var sensitiveKeySuffixes = []string{"password", "secret", "token", "keyfile", "privatekey", "apikey"}
+
+// recordedHeaders are the only headers kept in cassettes
+var recordedHeaders = []string{"Content-Type"}
+
+// Request is a recorded request, identified by its method, its path and query without the host, and its body
+type Request struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// Response is a recorded response. JSON bodies are stored as is in Body and other bodies as text in RawBody.
+type Response struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+	RawBody string            `json:"raw_body,omitempty"`
+}
+
+// Interaction is a request and the response it got
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette is the content of a cassette file
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Transport is an http.RoundTripper recording interactions into, or replaying them from, a cassette file
+type Transport struct {
+	path string
+	mode Mode
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New returns a transport for the cassette at path. In Record mode requests are sent with next, in Replay mode the
+// cassette must exist and next is not used.
+func New(path string, mode Mode, next http.RoundTripper) (*Transport, error) {
+	t := &Transport{path: path, mode: mode, next: next}
+	if mode == Record {
+		t.cassette.Interactions = []Interaction{}
+		return t, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read cassette: %w", err)
+	}
+	if err := json.Unmarshal(data, &t.cassette); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+	t.used = make([]bool, len(t.cassette.Interactions))
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+	}
+	request := Request{
+		Method: req.Method,
+		URL:    requestURL(req),
+		Body:   sanitizeRequestBody(body),
+	}
+
+	if t.mode == Record {
+		return t.record(req, body, request)
+	}
+	return t.replay(req, request)
+}
+
+func (t *Transport) record(req *http.Request, body []byte, request Request) (*http.Response, error) {
+	outgoing := req.Clone(req.Context())
+	outgoing.Body = io.NopCloser(bytes.NewReader(body))
+	resp, err := t.next.RoundTrip(outgoing)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	response := Response{Status: resp.StatusCode, Headers: map[string]string{}}
+	for _, header := range recordedHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			response.Headers[header] = value
+		}
+	}
+	response.Body, response.RawBody = sanitizeResponseBody(respBody, isRawTokenPath(req.URL.Path))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cassette.Interactions = append(t.cassette.Interactions, Interaction{Request: request, Response: response})
+	return resp, nil
+}
+
+// replay answers with the first unused interaction with the same method and URL, preferring one with the same body
+func (t *Transport) replay(req *http.Request, request Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	match := -1
+	for i, interaction := range t.cassette.Interactions {
+		recorded := interaction.Request
+		if t.used[i] || recorded.Method != request.Method || recorded.URL != request.URL {
+			continue
+		}
+		if sameJSON(recorded.Body, request.Body) {
+			match = i
+			break
+		}
+		if match < 0 {
+			match = i
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("cassette %s has no interaction left for %s %s", t.path, request.Method, request.URL)
+	}
+	t.used[match] = true
+
+	response := t.cassette.Interactions[match].Response
+	resp := &http.Response{
+		StatusCode: response.Status,
+		Status:     fmt.Sprintf("%d %s", response.Status, http.StatusText(response.Status)),
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Request:    req,
+	}
+	for name, value := range response.Headers {
+		resp.Header.Set(name, value)
+	}
+	body := []byte(response.RawBody)
+	if len(response.Body) > 0 {
+		body = response.Body
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return resp, nil
+}
+
+// Save writes the recorded interactions to the cassette file, it does nothing in Replay mode
+func (t *Transport) Save() error {
+	if t.mode != Record {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	data, err := json.MarshalIndent(t.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(t.path, append(data, '\n'), 0644)
+}
+
+// requestURL returns the path and query of the request, leaving out the host which differs between environments
+func requestURL(req *http.Request) string {
+	if req.URL.RawQuery == "" {
+		return req.URL.EscapedPath()
+	}
+	return req.URL.EscapedPath() + "?" + req.URL.RawQuery
+}
+
+func isRawTokenPath(path string) bool {
+	return strings.Contains(path, "/auth/key")
+}
+
+func sameJSON(a, b json.RawMessage) bool {
+	var valueA, valueB interface{}
+	if json.Unmarshal(a, &valueA) != nil || json.Unmarshal(b, &valueB) != nil {
+		return bytes.Equal(a, b)
+	}
+	normalizedA, _ := json.Marshal(valueA)
+	normalizedB, _ := json.Marshal(valueB)
+	return bytes.Equal(normalizedA, normalizedB)
+}
+
+func sanitizeRequestBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	sanitized, raw := sanitizeResponseBody(body, false)
+	if sanitized != nil {
+		return sanitized
+	}
+	// request bodies are always JSON for the Codefresh API, keep anything else as a JSON string
+	encoded, _ := json.Marshal(raw)
+	return encoded
+}
+
+// sanitizeResponseBody returns JSON bodies with their sensitive values masked, or other bodies as text.
+// Bodies made of a bare token are fully masked when rawToken is set.
+func sanitizeResponseBody(body []byte, rawToken bool) (json.RawMessage, string) {
+	if len(body) == 0 {
+		return nil, ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		if rawToken {
+			return nil, redactedValue
+		}
+		return nil, string(body)
+	}
+	if _, ok := value.(string); ok && rawToken {
+		value = redactedValue
+	}
+	sanitized, err := json.Marshal(sanitize(value))
+	if err != nil {
+		return nil, redactedValue
+	}
+	return sanitized, ""
+}
+
+// sanitize masks, in place, the values of credential-like keys, the data of secret contexts and the values of encrypted variables
+func sanitize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		contextType, _ := v["type"].(string)
+		isSecretContext := strings.Contains(contextType, "secret")
+		isEncryptedVariable, _ := v["encrypted"].(bool)
+		for key, child := range v {
+			switch {
+			case isSensitiveKey(key),
+				isSecretContext && key == "data",
+				isEncryptedVariable && key == "value":
+				v[key] = redactLeaves(child)
+			default:
+				v[key] = sanitize(child)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = sanitize(child)
+		}
+		return v
+	}
+	return value
+}
+
+func redactLeaves(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = redactLeaves(child)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactLeaves(child)
+		}
+		return v
+	case nil, bool:
+		return v
+	}
+	return redactedValue
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	for _, suffix := range sensitiveKeySuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
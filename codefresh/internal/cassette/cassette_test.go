@@ -0,0 +1,121 @@
+package cassette
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+)
+
+func newClient(t *testing.T, url, path string, mode Mode) (*cfclient.Client, *Transport) {
+	client := cfclient.NewClient(url+"/api", url+"/2.0/api/graphql", "api-token", "")
+	client.RetryMaxAttempts = 1
+	var transport *Transport
+	client.WrapTransport(func(next http.RoundTripper) http.RoundTripper {
+		var err error
+		if transport, err = New(path, mode, next); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return transport
+	})
+	return client, transport
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/registries":
+			_, _ = w.Write([]byte(`{"_id":"r1","name":"dockerhub","username":"me","password":"hunter2"}`))
+		case "/api/auth/key/service-user/su1":
+			_, _ = w.Write([]byte(`5f1a.0123456789`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"status":404,"message":"not found"}`))
+		}
+	}))
+	path := filepath.Join(t.TempDir(), "cassettes", "registry.json")
+	ctx := context.Background()
+
+	client, transport := newClient(t, server.URL, path, Record)
+	registry, err := client.CreateRegistry(ctx, &cfclient.Registry{Name: "dockerhub", Password: "hunter2"})
+	if err != nil || registry.Password != "hunter2" {
+		t.Fatalf("expected the recorded response to be returned untouched, got %+v %v", registry, err)
+	}
+	if _, err := client.CreateApiKeyServiceUser(ctx, "su1", &cfclient.ApiKey{Name: "key"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetPipeline(ctx, "missing"); !cfclient.IsNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+	if err := transport.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, secret := range []string{"hunter2", "5f1a.0123456789", "api-token", "127.0.0.1"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("expected %q not to be recorded in %s", secret, data)
+		}
+	}
+
+	client, _ = newClient(t, "https://g.codefresh.io", path, Replay)
+	registry, err = client.CreateRegistry(ctx, &cfclient.Registry{Name: "dockerhub", Password: "other"})
+	if err != nil || registry.Id != "r1" || registry.Username != "me" || registry.Password != redactedValue {
+		t.Fatalf("expected the sanitized response to be replayed, got %+v %v", registry, err)
+	}
+	token, err := client.CreateApiKeyServiceUser(ctx, "su1", &cfclient.ApiKey{Name: "key"})
+	if err != nil || token != redactedValue {
+		t.Fatalf("expected the masked token to be replayed, got %q %v", token, err)
+	}
+	if _, err := client.GetPipeline(ctx, "missing"); !cfclient.IsNotFound(err) {
+		t.Fatalf("expected the not found error to be replayed, got %v", err)
+	}
+	if _, err := client.GetPipeline(ctx, "missing"); err == nil || !strings.Contains(err.Error(), "no interaction left") {
+		t.Fatalf("expected an interaction to be replayed once, got %v", err)
+	}
+}
+
+func TestReplayPrefersInteractionsWithTheSameBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cassette := `{"interactions":[
+		{"request":{"method":"POST","url":"/api/contexts","body":{"metadata":{"name":"first"}}},"response":{"status":200,"body":{"metadata":{"name":"first"}}}},
+		{"request":{"method":"POST","url":"/api/contexts","body":{"metadata":{"name":"second"}}},"response":{"status":200,"body":{"metadata":{"name":"second"}}}}
+	]}`
+	if err := os.WriteFile(path, []byte(cassette), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client, _ := newClient(t, "https://g.codefresh.io", path, Replay)
+	body, err := client.RequestAPI(context.Background(), &cfclient.RequestOptions{
+		Path:   "/contexts",
+		Method: http.MethodPost,
+		Body:   []byte(`{"metadata": {"name": "second"}}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(body), "second") {
+		t.Fatalf("expected the interaction with the same body to be replayed, got %s", body)
+	}
+}
+
+func TestSanitizeKeepsNonSensitiveKeys(t *testing.T) {
+	body, _ := sanitizeResponseBody([]byte(`{"tokenURL":"https://github.com/login/oauth/access_token","tokenPrefix":"5f1a","clientSecret":"s","accessToken":"t"}`), false)
+	for _, kept := range []string{"https://github.com/login/oauth/access_token", "5f1a"} {
+		if !strings.Contains(string(body), kept) {
+			t.Errorf("expected %q to be kept in %s", kept, body)
+		}
+	}
+	if strings.Contains(string(body), `"s"`) || strings.Contains(string(body), `"t"`) {
+		t.Errorf("expected the secret and the token to be masked in %s", body)
+	}
+}
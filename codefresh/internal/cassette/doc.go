@@ -0,0 +1,2 @@
+// Package cassette records the HTTP interactions of the Codefresh API client into sanitized cassette files and replays them in tests.
+package cassette
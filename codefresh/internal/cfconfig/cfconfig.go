@@ -0,0 +1,91 @@
+package cfconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultFileName is the name of the Codefresh CLI configuration file in the home directory
+const DefaultFileName = ".cfconfig"
+
+// Context is a named set of credentials of the Codefresh CLI
+type Context struct {
+	Name  string `yaml:"name"`
+	Type  string `yaml:"type"`
+	URL   string `yaml:"url"`
+	Token string `yaml:"token"`
+}
+
+// Config is the content of the Codefresh CLI configuration file
+type Config struct {
+	Contexts       map[string]Context `yaml:"contexts"`
+	CurrentContext string             `yaml:"current-context"`
+}
+
+// DefaultPath returns the path of the configuration file used by the Codefresh CLI, ~/.cfconfig
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, DefaultFileName), nil
+}
+
+// Load reads and parses the configuration file at path
+func Load(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("cannot parse Codefresh CLI config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// Context returns the context with the given name, or the current context if name is empty
+func (c *Config) Context(name string) (*Context, error) {
+	if name == "" {
+		name = c.CurrentContext
+	}
+	if name == "" {
+		return nil, fmt.Errorf("no context name given and no current-context set in the Codefresh CLI config")
+	}
+
+	context, ok := c.Contexts[name]
+	if !ok {
+		names := make([]string, 0, len(c.Contexts))
+		for contextName := range c.Contexts {
+			names = append(names, contextName)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("context %q not found in the Codefresh CLI config, available contexts: %s", name, strings.Join(names, ", "))
+	}
+	if context.Name == "" {
+		context.Name = name
+	}
+	return &context, nil
+}
+
+// APIURL returns the URL of the Codefresh API of the context, empty if the context has no URL
+func (c *Context) APIURL() string {
+	if c.URL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(c.URL, "/") + "/api"
+}
+
+// APIURLV2 returns the URL of the Codefresh gitops API of the context, empty if the context has no URL
+func (c *Context) APIURLV2() string {
+	if c.URL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(c.URL, "/") + "/2.0/api/graphql"
+}
@@ -0,0 +1,63 @@
+package cfconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testConfig = `contexts:
+  prod:
+    type: APIKey
+    name: prod
+    url: https://g.codefresh.io
+    token: prod-token
+  on-prem:
+    type: APIKey
+    name: on-prem
+    url: https://codefresh.example.com/
+    token: on-prem-token
+current-context: prod
+`
+
+func loadTestConfig(t *testing.T) *Config {
+	path := filepath.Join(t.TempDir(), DefaultFileName)
+	if err := os.WriteFile(path, []byte(testConfig), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return config
+}
+
+func TestConfigContext(t *testing.T) {
+	config := loadTestConfig(t)
+
+	current, err := config.Context("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current.Token != "prod-token" || current.APIURL() != "https://g.codefresh.io/api" {
+		t.Fatalf("expected the current context, got %+v", current)
+	}
+
+	onPrem, err := config.Context("on-prem")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if onPrem.APIURL() != "https://codefresh.example.com/api" || onPrem.APIURLV2() != "https://codefresh.example.com/2.0/api/graphql" {
+		t.Fatalf("unexpected URLs %s and %s", onPrem.APIURL(), onPrem.APIURLV2())
+	}
+
+	if _, err := config.Context("staging"); err == nil {
+		t.Fatal("expected an error for a missing context")
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), DefaultFileName)); !os.IsNotExist(err) {
+		t.Fatalf("expected a not exist error, got %v", err)
+	}
+}
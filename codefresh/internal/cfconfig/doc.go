@@ -0,0 +1,2 @@
+// Package cfconfig reads the configuration file of the Codefresh CLI.
+package cfconfig
@@ -23,6 +23,15 @@ func ConvertAndMapStringArr(ifaceArr []interface{}, f func(string) string) []str
 	return arr
 }
 
+// ConvertStringMap converts a map of interfaces to a map of strings.
+func ConvertStringMap(ifaceMap map[string]interface{}) map[string]string {
+	m := make(map[string]string, len(ifaceMap))
+	for k, v := range ifaceMap {
+		m[k] = v.(string)
+	}
+	return m
+}
+
 // ConvertVariables converts an array of cfclient. Variables to 2 maps of key/value pairs - first one for un-encrypted variables second one for encrypted variables.
 func ConvertVariables(vars []cfclient.Variable) (map[string]string, map[string]string) {
 
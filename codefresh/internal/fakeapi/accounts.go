@@ -0,0 +1,736 @@
+package fakeapi
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (s *Server) registerAccountRoutes(mux *http.ServeMux) {
+	s.handle(mux, "GET /user", s.getCurrentUser)
+	s.handle(mux, "POST /user/changeaccount/{account}", s.changeAccount)
+
+	s.handle(mux, "GET /admin/accounts", s.listAccounts)
+	s.handle(mux, "POST /admin/accounts", s.createAccount)
+	s.handle(mux, "GET /admin/accounts/{account}", s.getAccount)
+	s.handle(mux, "POST /admin/accounts/{account}/update", s.updateAccount)
+	s.handle(mux, "DELETE /admin/accounts/{account}", s.deleteAccount)
+	s.handle(mux, "POST /admin/accounts/addpendinguser", s.addPendingUser)
+	s.handle(mux, "POST /features/{account}", s.setAccountFeature(true))
+	s.handle(mux, "PUT /features/switchOff/{account}", s.setAccountFeature(false))
+
+	s.handle(mux, "GET /accounts/{account}/users", s.listAccountUsers)
+	s.handle(mux, "POST /accounts/{account}/adduser", s.addUserToAccount)
+	s.handle(mux, "POST /accounts/{account}/{user}/updateuser", s.updateAccountUser)
+	s.handle(mux, "POST /accounts/{account}/{user}/admin", s.setAccountAdmin(true))
+	s.handle(mux, "DELETE /accounts/{account}/{user}/admin", s.setAccountAdmin(false))
+	s.handle(mux, "DELETE /accounts/{account}/{user}", s.removeUserFromAccount)
+
+	s.handle(mux, "GET /admin/user", s.listUsers)
+	s.handle(mux, "GET /admin/user/id/{user}", s.getUser)
+	s.handle(mux, "DELETE /admin/user/{userName}", s.deleteUser)
+	s.handle(mux, "POST /admin/user/{user}/activate", s.activateUser)
+	s.handle(mux, "POST /admin/user/account", s.setUserAccounts)
+	s.handle(mux, "POST /admin/user/localProvider", s.setLocalPassword(true))
+	s.handle(mux, "DELETE /admin/user/localProvider", s.setLocalPassword(false))
+	s.handle(mux, "GET /admin/user/loginAsUser", s.loginAsUser)
+
+	s.handle(mux, "GET /team", s.listTeams)
+	s.handle(mux, "POST /team", s.createTeam)
+	s.handle(mux, "DELETE /team/{team}", s.deleteTeam)
+	s.handle(mux, "PUT /team/{team}/{user}/assignUserToTeam", s.setTeamMember(true))
+	s.handle(mux, "PUT /team/{team}/{user}/deleteUserFromTeam", s.setTeamMember(false))
+	s.handle(mux, "PUT /team/{team}/renameTeam", s.renameTeam)
+	s.handle(mux, "GET /team/group/synchronize/name/{name}/type/{type}", func(w http.ResponseWriter, r *http.Request, sess session) {
+		writeJSON(w, http.StatusOK, document{})
+	})
+
+	s.handle(mux, "GET /service-users", s.listServiceUsers)
+	s.handle(mux, "POST /service-users", s.createServiceUser)
+	s.handle(mux, "GET /service-users/{serviceUser}", s.getServiceUser)
+	s.handle(mux, "PATCH /service-users/{serviceUser}", s.updateServiceUser)
+	s.handle(mux, "DELETE /service-users/{serviceUser}", s.deleteServiceUser)
+
+	s.handle(mux, "POST /auth/key", s.createAPIKey)
+	s.handle(mux, "GET /auth/keys", s.listAPIKeys)
+	s.handle(mux, "GET /auth/key/{key}", s.getAPIKey)
+	s.handle(mux, "PATCH /auth/key/{key}", s.updateAPIKey)
+	s.handle(mux, "DELETE /auth/key/{key}", s.deleteAPIKey)
+	s.handle(mux, "POST /auth/key/service-user/{serviceUser}", s.createAPIKey)
+	s.handle(mux, "GET /auth/key/service-user/{serviceUser}/{key}", s.getAPIKey)
+	s.handle(mux, "PATCH /auth/key/service-user/{serviceUser}/{key}", s.updateAPIKey)
+	s.handle(mux, "DELETE /auth/key/service-user/{serviceUser}/{key}", s.deleteAPIKey)
+}
+
+// accounts
+
+func (s *Server) findAccount(w http.ResponseWriter, id string) (document, bool) {
+	i := indexOf(s.accounts, "_id", id)
+	if i < 0 {
+		writeNotFound(w, "account", id)
+		return nil, false
+	}
+	return s.accounts[i], true
+}
+
+func (s *Server) getCurrentUser(w http.ResponseWriter, r *http.Request, sess session) {
+	user, ok := s.findUser(w, sess.userID)
+	if !ok {
+		return
+	}
+	response := s.renderUser(user)
+	accounts := make([]interface{}, 0)
+	for _, id := range stringSlice(user["account"]) {
+		if i := indexOf(s.accounts, "_id", id); i >= 0 {
+			account := clone(s.accounts[i])
+			account["id"] = id
+			accounts = append(accounts, account)
+			if id == sess.accountID {
+				response["activeAccountName"] = account["name"]
+			}
+		}
+	}
+	response["account"] = accounts
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) changeAccount(w http.ResponseWriter, r *http.Request, sess session) {
+	accountID := r.PathValue("account")
+	user, ok := s.findUser(w, sess.userID)
+	if !ok {
+		return
+	}
+	if !slices.Contains(stringSlice(user["account"]), accountID) {
+		writeError(w, http.StatusForbidden, "user %s is not a member of account %s", sess.userID, accountID)
+		return
+	}
+	writeJSON(w, http.StatusOK, document{"accessToken": s.issueToken(sess.userID, accountID)})
+}
+
+func (s *Server) listAccounts(w http.ResponseWriter, r *http.Request, sess session) {
+	name := r.URL.Query().Get("filter[name]")
+	accounts := make([]document, 0, len(s.accounts))
+	for _, account := range s.accounts {
+		if name == "" || str(account["name"]) == name {
+			accounts = append(accounts, clone(account))
+		}
+	}
+	writeJSON(w, http.StatusOK, accounts)
+}
+
+func (s *Server) createAccount(w http.ResponseWriter, r *http.Request, sess session) {
+	var account document
+	if !readJSON(w, r, &account) {
+		return
+	}
+	if indexOf(s.accounts, "name", str(account["name"])) >= 0 {
+		writeError(w, http.StatusConflict, "account %s already exists", account["name"])
+		return
+	}
+	account["_id"] = s.newID()
+	s.accounts = append(s.accounts, account)
+	writeJSON(w, http.StatusOK, clone(account))
+}
+
+func (s *Server) getAccount(w http.ResponseWriter, r *http.Request, sess session) {
+	if account, ok := s.findAccount(w, r.PathValue("account")); ok {
+		writeJSON(w, http.StatusOK, clone(account))
+	}
+}
+
+func (s *Server) updateAccount(w http.ResponseWriter, r *http.Request, sess session) {
+	account, ok := s.findAccount(w, r.PathValue("account"))
+	if !ok {
+		return
+	}
+	var update document
+	if !readJSON(w, r, &update) {
+		return
+	}
+	for key, value := range update {
+		if key != "_id" {
+			account[key] = value
+		}
+	}
+	writeJSON(w, http.StatusOK, clone(account))
+}
+
+func (s *Server) deleteAccount(w http.ResponseWriter, r *http.Request, sess session) {
+	i := indexOf(s.accounts, "_id", r.PathValue("account"))
+	if i < 0 {
+		writeNotFound(w, "account", r.PathValue("account"))
+		return
+	}
+	s.accounts = removeAt(s.accounts, i)
+	writeJSON(w, http.StatusOK, document{})
+}
+
+func (s *Server) setAccountFeature(enabled bool) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, sess session) {
+		account, ok := s.findAccount(w, r.PathValue("account"))
+		if !ok {
+			return
+		}
+		var body struct {
+			Feature string `json:"feature"`
+		}
+		if !readJSON(w, r, &body) {
+			return
+		}
+		features, _ := account["features"].(map[string]interface{})
+		if features == nil {
+			features = document{}
+			account["features"] = features
+		}
+		features[body.Feature] = enabled
+		writeJSON(w, http.StatusOK, document{})
+	}
+}
+
+func (s *Server) setAccountAdmin(admin bool) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, sess session) {
+		account, ok := s.findAccount(w, r.PathValue("account"))
+		if !ok {
+			return
+		}
+		if _, ok := s.findUser(w, r.PathValue("user")); !ok {
+			return
+		}
+		account["admins"] = toArray(setMember(stringSlice(account["admins"]), r.PathValue("user"), admin))
+		writeJSON(w, http.StatusOK, document{})
+	}
+}
+
+// users
+
+func (s *Server) findUser(w http.ResponseWriter, id string) (document, bool) {
+	i := indexOf(s.users, "_id", id)
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "User does not exist: %s", id)
+		return nil, false
+	}
+	return s.users[i], true
+}
+
+// renderUser returns user with its accounts expanded to objects, as returned by the admin API
+func (s *Server) renderUser(user document) document {
+	rendered := clone(user)
+	accounts := make([]interface{}, 0)
+	for _, id := range stringSlice(user["account"]) {
+		if i := indexOf(s.accounts, "_id", id); i >= 0 {
+			accounts = append(accounts, document{"_id": id, "name": s.accounts[i]["name"]})
+		}
+	}
+	rendered["account"] = accounts
+	return rendered
+}
+
+func (s *Server) newUser(userName, email string, accounts []string) document {
+	if userName == "" {
+		userName, _, _ = strings.Cut(email, "@")
+	}
+	user := document{
+		"_id":           s.newID(),
+		"userName":      userName,
+		"email":         email,
+		"status":        "pending",
+		"roles":         []interface{}{"User"},
+		"account":       toArray(accounts),
+		"register_date": time.Now().UTC().Format(time.RFC3339),
+	}
+	s.users = append(s.users, user)
+	return user
+}
+
+func (s *Server) listAccountUsers(w http.ResponseWriter, r *http.Request, sess session) {
+	accountID := r.PathValue("account")
+	if _, ok := s.findAccount(w, accountID); !ok {
+		return
+	}
+	users := make([]document, 0)
+	for _, user := range s.users {
+		if slices.Contains(stringSlice(user["account"]), accountID) {
+			users = append(users, document{
+				"_id":      user["_id"],
+				"userName": user["userName"],
+				"email":    user["email"],
+				"status":   user["status"],
+			})
+		}
+	}
+	writeJSON(w, http.StatusOK, users)
+}
+
+// userDetails reads the two body formats accepted when adding or updating the user of an account
+func userDetails(w http.ResponseWriter, r *http.Request) (userName, email string, ok bool) {
+	var body struct {
+		UserDetails string `json:"userDetails"`
+		UserName    string `json:"userName"`
+		Email       string `json:"email"`
+	}
+	if !readJSON(w, r, &body) {
+		return "", "", false
+	}
+	if body.UserDetails != "" {
+		return "", body.UserDetails, true
+	}
+	return body.UserName, body.Email, true
+}
+
+func (s *Server) addUserToAccount(w http.ResponseWriter, r *http.Request, sess session) {
+	accountID := r.PathValue("account")
+	if _, ok := s.findAccount(w, accountID); !ok {
+		return
+	}
+	userName, email, ok := userDetails(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderUser(s.newUser(userName, email, []string{accountID})))
+}
+
+func (s *Server) addPendingUser(w http.ResponseWriter, r *http.Request, sess session) {
+	var body document
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if indexOf(s.users, "userName", str(body["userName"])) >= 0 {
+		writeError(w, http.StatusConflict, "user %s already exists", body["userName"])
+		return
+	}
+	user := s.newUser(str(body["userName"]), str(body["email"]), stringSlice(body["account"]))
+	for _, key := range []string{"roles", "personal"} {
+		if value, ok := body[key]; ok {
+			user[key] = value
+		}
+	}
+	writeJSON(w, http.StatusOK, s.renderUser(user))
+}
+
+func (s *Server) updateAccountUser(w http.ResponseWriter, r *http.Request, sess session) {
+	user, ok := s.findUser(w, r.PathValue("user"))
+	if !ok {
+		return
+	}
+	userName, email, ok := userDetails(w, r)
+	if !ok {
+		return
+	}
+	if userName != "" {
+		user["userName"] = userName
+	}
+	user["email"] = email
+	writeJSON(w, http.StatusOK, s.renderUser(user))
+}
+
+func (s *Server) removeUserFromAccount(w http.ResponseWriter, r *http.Request, sess session) {
+	accountID, userID := r.PathValue("account"), r.PathValue("user")
+	account, ok := s.findAccount(w, accountID)
+	if !ok {
+		return
+	}
+	user, ok := s.findUser(w, userID)
+	if !ok {
+		return
+	}
+	user["account"] = toArray(setMember(stringSlice(user["account"]), accountID, false))
+	account["admins"] = toArray(setMember(stringSlice(account["admins"]), userID, false))
+	writeJSON(w, http.StatusOK, document{})
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request, sess session) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = len(s.users)
+	}
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	docs := make([]document, 0, limit)
+	for i := (page - 1) * limit; i < len(s.users) && i < page*limit; i++ {
+		docs = append(docs, s.renderUser(s.users[i]))
+	}
+	writeJSON(w, http.StatusOK, document{"docs": docs})
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request, sess session) {
+	if user, ok := s.findUser(w, r.PathValue("user")); ok {
+		writeJSON(w, http.StatusOK, s.renderUser(user))
+	}
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request, sess session) {
+	i := indexOf(s.users, "userName", r.PathValue("userName"))
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "User does not exist: %s", r.PathValue("userName"))
+		return
+	}
+	userID := str(s.users[i]["_id"])
+	s.users = removeAt(s.users, i)
+	for _, account := range s.accounts {
+		account["admins"] = toArray(setMember(stringSlice(account["admins"]), userID, false))
+	}
+	for _, team := range s.teams {
+		team["users"] = toArray(setMember(stringSlice(team["users"]), userID, false))
+	}
+	writeJSON(w, http.StatusOK, document{})
+}
+
+func (s *Server) activateUser(w http.ResponseWriter, r *http.Request, sess session) {
+	if user, ok := s.findUser(w, r.PathValue("user")); ok {
+		user["status"] = "active"
+		writeJSON(w, http.StatusOK, s.renderUser(user))
+	}
+}
+
+func (s *Server) setUserAccounts(w http.ResponseWriter, r *http.Request, sess session) {
+	var body struct {
+		UserName string `json:"userName"`
+		Account  []struct {
+			ID string `json:"_id"`
+		} `json:"account"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	i := indexOf(s.users, "userName", body.UserName)
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "User does not exist: %s", body.UserName)
+		return
+	}
+	accounts := make([]string, 0, len(body.Account))
+	for _, account := range body.Account {
+		accounts = append(accounts, account.ID)
+	}
+	s.users[i]["account"] = toArray(accounts)
+	writeJSON(w, http.StatusOK, s.renderUser(s.users[i]))
+}
+
+func (s *Server) setLocalPassword(hasPassword bool) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, sess session) {
+		userName := r.URL.Query().Get("userName")
+		if hasPassword {
+			var body struct {
+				UserName string `json:"userName"`
+			}
+			if !readJSON(w, r, &body) {
+				return
+			}
+			userName = body.UserName
+		}
+		i := indexOf(s.users, "userName", userName)
+		if i < 0 {
+			writeError(w, http.StatusNotFound, "User does not exist: %s", userName)
+			return
+		}
+		s.users[i]["hasPassword"] = hasPassword
+		writeJSON(w, http.StatusOK, document{})
+	}
+}
+
+func (s *Server) loginAsUser(w http.ResponseWriter, r *http.Request, sess session) {
+	user, ok := s.findUser(w, r.URL.Query().Get("userId"))
+	if !ok {
+		return
+	}
+	accountID := ""
+	if accounts := stringSlice(user["account"]); len(accounts) > 0 {
+		accountID = accounts[0]
+	}
+	writeJSON(w, http.StatusOK, document{
+		"accessToken": s.issueToken(str(user["_id"]), accountID),
+		"user":        document{"userName": user["userName"], "email": user["email"]},
+	})
+}
+
+// teams
+
+func (s *Server) findTeam(w http.ResponseWriter, id string) (document, bool) {
+	i := indexOf(s.teams, "_id", id)
+	if i < 0 {
+		writeNotFound(w, "team", id)
+		return nil, false
+	}
+	return s.teams[i], true
+}
+
+func (s *Server) listTeams(w http.ResponseWriter, r *http.Request, sess session) {
+	teams := make([]document, 0, len(s.teams))
+	for _, team := range s.teams {
+		rendered := clone(team)
+		users := make([]interface{}, 0)
+		for _, id := range stringSlice(team["users"]) {
+			member := document{"_id": id}
+			if i := indexOf(s.users, "_id", id); i >= 0 {
+				member["userName"], member["email"] = s.users[i]["userName"], s.users[i]["email"]
+			} else if i := indexOf(s.serviceUsers, "_id", id); i >= 0 {
+				member["userName"] = s.serviceUsers[i]["userName"]
+			}
+			users = append(users, member)
+		}
+		rendered["users"] = users
+		teams = append(teams, rendered)
+	}
+	writeJSON(w, http.StatusOK, teams)
+}
+
+func (s *Server) createTeam(w http.ResponseWriter, r *http.Request, sess session) {
+	var team document
+	if !readJSON(w, r, &team) {
+		return
+	}
+	team["_id"] = s.newID()
+	if str(team["type"]) == "" {
+		team["type"] = "default"
+	}
+	if str(team["account"]) == "" {
+		team["account"] = sess.accountID
+	}
+	if _, ok := team["users"]; !ok {
+		team["users"] = []interface{}{}
+	}
+	s.teams = append(s.teams, team)
+	writeJSON(w, http.StatusOK, clone(team))
+}
+
+func (s *Server) deleteTeam(w http.ResponseWriter, r *http.Request, sess session) {
+	i := indexOf(s.teams, "_id", r.PathValue("team"))
+	if i < 0 {
+		writeNotFound(w, "team", r.PathValue("team"))
+		return
+	}
+	s.teams = removeAt(s.teams, i)
+	writeJSON(w, http.StatusOK, document{})
+}
+
+func (s *Server) setTeamMember(member bool) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, sess session) {
+		if team, ok := s.findTeam(w, r.PathValue("team")); ok {
+			team["users"] = toArray(setMember(stringSlice(team["users"]), r.PathValue("user"), member))
+			writeJSON(w, http.StatusOK, document{})
+		}
+	}
+}
+
+func (s *Server) renameTeam(w http.ResponseWriter, r *http.Request, sess session) {
+	team, ok := s.findTeam(w, r.PathValue("team"))
+	if !ok {
+		return
+	}
+	var body struct {
+		Name string `json:"name"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	team["name"] = body.Name
+	writeJSON(w, http.StatusOK, document{})
+}
+
+// service users, whose team memberships are stored on the teams
+
+func (s *Server) renderServiceUser(serviceUser document) document {
+	rendered := clone(serviceUser)
+	teams := make([]interface{}, 0)
+	for _, team := range s.teams {
+		if slices.Contains(stringSlice(team["users"]), str(serviceUser["_id"])) {
+			teams = append(teams, document{"_id": team["_id"], "name": team["name"]})
+		}
+	}
+	rendered["teams"] = teams
+	return rendered
+}
+
+func (s *Server) findServiceUser(w http.ResponseWriter, id string) (document, bool) {
+	i := indexOf(s.serviceUsers, "_id", id)
+	if i < 0 {
+		writeNotFound(w, "service user", id)
+		return nil, false
+	}
+	return s.serviceUsers[i], true
+}
+
+type serviceUserBody struct {
+	UserName        string   `json:"userName"`
+	TeamIDs         []string `json:"teamIds"`
+	AssignAdminRole bool     `json:"assignAdminRole"`
+}
+
+// apply sets the name, role and teams of serviceUser
+func (body serviceUserBody) apply(s *Server, serviceUser document) {
+	serviceUser["userName"] = body.UserName
+	serviceUser["roles"] = []interface{}{"User"}
+	if body.AssignAdminRole {
+		serviceUser["roles"] = []interface{}{"User", "Admin"}
+	}
+	id := str(serviceUser["_id"])
+	for _, team := range s.teams {
+		team["users"] = toArray(setMember(stringSlice(team["users"]), id, slices.Contains(body.TeamIDs, str(team["_id"]))))
+	}
+}
+
+func (s *Server) listServiceUsers(w http.ResponseWriter, r *http.Request, sess session) {
+	serviceUsers := make([]document, 0, len(s.serviceUsers))
+	for _, serviceUser := range s.serviceUsers {
+		serviceUsers = append(serviceUsers, s.renderServiceUser(serviceUser))
+	}
+	writeJSON(w, http.StatusOK, serviceUsers)
+}
+
+func (s *Server) createServiceUser(w http.ResponseWriter, r *http.Request, sess session) {
+	var body serviceUserBody
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if indexOf(s.serviceUsers, "userName", body.UserName) >= 0 {
+		writeError(w, http.StatusConflict, "service user %s already exists", body.UserName)
+		return
+	}
+	serviceUser := document{"_id": s.newID()}
+	body.apply(s, serviceUser)
+	s.serviceUsers = append(s.serviceUsers, serviceUser)
+	writeJSON(w, http.StatusOK, s.renderServiceUser(serviceUser))
+}
+
+func (s *Server) getServiceUser(w http.ResponseWriter, r *http.Request, sess session) {
+	if serviceUser, ok := s.findServiceUser(w, r.PathValue("serviceUser")); ok {
+		writeJSON(w, http.StatusOK, s.renderServiceUser(serviceUser))
+	}
+}
+
+func (s *Server) updateServiceUser(w http.ResponseWriter, r *http.Request, sess session) {
+	serviceUser, ok := s.findServiceUser(w, r.PathValue("serviceUser"))
+	if !ok {
+		return
+	}
+	var body serviceUserBody
+	if !readJSON(w, r, &body) {
+		return
+	}
+	body.apply(s, serviceUser)
+	writeJSON(w, http.StatusOK, s.renderServiceUser(serviceUser))
+}
+
+func (s *Server) deleteServiceUser(w http.ResponseWriter, r *http.Request, sess session) {
+	id := r.PathValue("serviceUser")
+	i := indexOf(s.serviceUsers, "_id", id)
+	if i < 0 {
+		writeNotFound(w, "service user", id)
+		return
+	}
+	s.serviceUsers = removeAt(s.serviceUsers, i)
+	for _, team := range s.teams {
+		team["users"] = toArray(setMember(stringSlice(team["users"]), id, false))
+	}
+	writeJSON(w, http.StatusOK, document{})
+}
+
+// API keys, owned by the user of the session or by the service user in the path
+
+func apiKeySubject(r *http.Request, sess session) document {
+	if serviceUser := r.PathValue("serviceUser"); serviceUser != "" {
+		return document{"type": "serviceUser", "ref": serviceUser}
+	}
+	return document{"type": "user", "ref": sess.userID}
+}
+
+func (s *Server) findAPIKey(w http.ResponseWriter, r *http.Request, sess session) (int, bool) {
+	id := r.PathValue("key")
+	i := indexOf(s.apiKeys, "_id", id)
+	if i < 0 {
+		writeNotFound(w, "api key", id)
+		return -1, false
+	}
+	if subject, _ := s.apiKeys[i]["subject"].(map[string]interface{}); str(subject["ref"]) != str(apiKeySubject(r, sess)["ref"]) {
+		writeNotFound(w, "api key", id)
+		return -1, false
+	}
+	return i, true
+}
+
+// renderAPIKey returns key without the token, which is only returned on creation
+func renderAPIKey(key document) document {
+	rendered := clone(key)
+	delete(rendered, "token")
+	return rendered
+}
+
+func (s *Server) createAPIKey(w http.ResponseWriter, r *http.Request, sess session) {
+	if serviceUser := r.PathValue("serviceUser"); serviceUser != "" {
+		if _, ok := s.findServiceUser(w, serviceUser); !ok {
+			return
+		}
+	}
+	var key document
+	if !readJSON(w, r, &key) {
+		return
+	}
+	id := s.newID()
+	token := id + "." + s.newID()
+	key["_id"] = id
+	key["subject"] = apiKeySubject(r, sess)
+	key["tokenPrefix"] = token[:4]
+	key["created"] = time.Now().UTC().Format(time.RFC3339)
+	key["token"] = token
+	s.apiKeys = append(s.apiKeys, key)
+	s.sessions[token] = session{userID: sess.userID, accountID: sess.accountID}
+
+	// the token is answered as is, not as a JSON string
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte(token))
+}
+
+func (s *Server) listAPIKeys(w http.ResponseWriter, r *http.Request, sess session) {
+	keys := make([]document, 0)
+	for _, key := range s.apiKeys {
+		if subject, _ := key["subject"].(map[string]interface{}); str(subject["ref"]) == sess.userID {
+			keys = append(keys, renderAPIKey(key))
+		}
+	}
+	writeJSON(w, http.StatusOK, keys)
+}
+
+func (s *Server) getAPIKey(w http.ResponseWriter, r *http.Request, sess session) {
+	if i, ok := s.findAPIKey(w, r, sess); ok {
+		writeJSON(w, http.StatusOK, renderAPIKey(s.apiKeys[i]))
+	}
+}
+
+func (s *Server) updateAPIKey(w http.ResponseWriter, r *http.Request, sess session) {
+	i, ok := s.findAPIKey(w, r, sess)
+	if !ok {
+		return
+	}
+	var body struct {
+		Name   string      `json:"name"`
+		Scopes interface{} `json:"scopes"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	s.apiKeys[i]["name"] = body.Name
+	s.apiKeys[i]["scopes"] = body.Scopes
+	writeJSON(w, http.StatusOK, renderAPIKey(s.apiKeys[i]))
+}
+
+func (s *Server) deleteAPIKey(w http.ResponseWriter, r *http.Request, sess session) {
+	i, ok := s.findAPIKey(w, r, sess)
+	if !ok {
+		return
+	}
+	delete(s.sessions, str(s.apiKeys[i]["token"]))
+	s.apiKeys = removeAt(s.apiKeys, i)
+	writeJSON(w, http.StatusOK, document{})
+}
+
+// setMember adds value to values if member is true and removes it otherwise
+func setMember(values []string, value string, member bool) []string {
+	values = slices.DeleteFunc(values, func(v string) bool { return v == value })
+	if member {
+		values = append(values, value)
+	}
+	return values
+}
@@ -0,0 +1,3 @@
+// Package fakeapi provides an in-memory fake of the Codefresh REST and GitOps GraphQL APIs,
+// so that the provider can be tested without a Codefresh account.
+package fakeapi
@@ -0,0 +1,102 @@
+package fakeapi
+
+import (
+	"net/http"
+	"regexp"
+)
+
+// operationRegex extracts the type and name of a GraphQL operation
+var operationRegex = regexp.MustCompile(`^\s*(query|mutation)\s+(\w+)`)
+
+// graphql answers the GitOps GraphQL operations used by the provider, dispatching on the operation name
+func (s *Server) graphql(w http.ResponseWriter, r *http.Request, sess session) {
+	var request struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	operation := operationRegex.FindStringSubmatch(request.Query)
+	if operation == nil {
+		writeGraphQLError(w, "cannot find the operation name")
+		return
+	}
+
+	variables := request.Variables
+	switch operation[2] {
+	case "AccountInfo":
+		writeGraphQLData(w, document{"me": document{"activeAccount": s.gitopsAccountInfo(sess)}})
+	case "updateCsdpSettings":
+		settings := document{}
+		for _, key := range []string{"gitProvider", "gitApiUrl", "sharedConfigRepo"} {
+			settings[key] = variables[key]
+		}
+		s.gitopsSettings[sess.accountID] = settings
+		writeGraphQLData(w, document{"updateCsdpSettings": nil})
+	case "AbacRules":
+		rules := make([]document, 0)
+		for _, rule := range s.abacRules {
+			if str(rule["accountId"]) == sess.accountID && rule["entityType"] == variables["entityType"] {
+				rules = append(rules, clone(rule))
+			}
+		}
+		writeGraphQLData(w, document{"abacRules": rules})
+	case "AbacRule":
+		var rule document
+		if i := s.abacRuleIndex(sess, str(variables["id"])); i >= 0 {
+			rule = clone(s.abacRules[i])
+		}
+		writeGraphQLData(w, document{"abacRule": rule})
+	case "CreateAbacRule":
+		rule, _ := variables["createAbacRuleInput"].(map[string]interface{})
+		if rule == nil {
+			writeGraphQLError(w, "createAbacRuleInput is required")
+			return
+		}
+		rule["id"] = s.newID()
+		rule["accountId"] = sess.accountID
+		s.abacRules = append(s.abacRules, rule)
+		writeGraphQLData(w, document{"createAbacRule": clone(rule)})
+	case "RemoveAbacRule":
+		i := s.abacRuleIndex(sess, str(variables["id"]))
+		if i < 0 {
+			writeGraphQLError(w, "abac rule "+str(variables["id"])+" not found")
+			return
+		}
+		rule := s.abacRules[i]
+		s.abacRules = removeAt(s.abacRules, i)
+		writeGraphQLData(w, document{"removeAbacRule": rule})
+	default:
+		writeGraphQLError(w, "unsupported operation "+operation[2])
+	}
+}
+
+func (s *Server) gitopsAccountInfo(sess session) document {
+	info := document{"id": sess.accountID}
+	if i := indexOf(s.accounts, "_id", sess.accountID); i >= 0 {
+		info["name"] = s.accounts[i]["name"]
+		info["admins"] = s.accounts[i]["admins"]
+	}
+	for key, value := range s.gitopsSettings[sess.accountID] {
+		info[key] = value
+	}
+	return info
+}
+
+func (s *Server) abacRuleIndex(sess session, id string) int {
+	i := indexOf(s.abacRules, "id", id)
+	if i >= 0 && str(s.abacRules[i]["accountId"]) != sess.accountID {
+		return -1
+	}
+	return i
+}
+
+func writeGraphQLData(w http.ResponseWriter, data document) {
+	writeJSON(w, http.StatusOK, document{"data": data})
+}
+
+// writeGraphQLError answers with a GraphQL error, which the GitOps API does with a 200 status code
+func writeGraphQLError(w http.ResponseWriter, message string) {
+	writeJSON(w, http.StatusOK, document{"errors": []document{{"message": message}}})
+}
@@ -0,0 +1,492 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/triggertypes"
+)
+
+func (s *Server) registerPipelineRoutes(mux *http.ServeMux) {
+	s.handle(mux, "GET /projects", s.listProjects)
+	s.handle(mux, "POST /projects", s.createProject)
+	s.handle(mux, "GET /projects/{project}", s.getProject("id"))
+	s.handle(mux, "GET /projects/name/{project}", s.getProject("projectName"))
+	s.handle(mux, "PATCH /projects/{project}", s.updateProject)
+	s.handle(mux, "DELETE /projects/{project}", s.deleteProject)
+
+	s.handle(mux, "GET /pipelines", s.listPipelines)
+	s.handle(mux, "POST /pipelines", s.createPipeline)
+	s.handle(mux, "GET /pipelines/{pipeline}", s.getPipeline)
+	s.handle(mux, "PUT /pipelines/{pipeline}", s.updatePipeline)
+	s.handle(mux, "DELETE /pipelines/{pipeline}", s.deletePipeline)
+
+	s.handle(mux, "POST /hermes/events/{$}", s.createHermesEvent)
+	s.handle(mux, "DELETE /hermes/events/{event}", s.deleteHermesEvent)
+	s.handle(mux, "GET /hermes/triggers/{event}", s.getHermesEvent)
+	s.handle(mux, "GET /hermes/triggers/event/{event}", s.listHermesTriggers)
+	s.handle(mux, "POST /hermes/triggers/{event}/{pipeline}", s.createHermesTrigger)
+	s.handle(mux, "DELETE /hermes/triggers/{event}/{pipeline}", s.deleteHermesTrigger)
+
+	s.handle(mux, "POST /pipelines/run/{pipeline}", s.runPipeline)
+	s.handle(mux, "GET /builds/{build}", s.getBuild)
+	s.handle(mux, "GET /workflow", s.listBuilds)
+}
+
+// projects
+
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request, sess session) {
+	projects := make([]document, 0, len(s.projects))
+	for _, project := range s.projects {
+		projects = append(projects, renderProject(project))
+	}
+	writeJSON(w, http.StatusOK, document{"projects": projects, "total": len(projects)})
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request, sess session) {
+	var project document
+	if !readJSON(w, r, &project) {
+		return
+	}
+	if indexOf(s.projects, "projectName", str(project["projectName"])) >= 0 {
+		writeError(w, http.StatusConflict, "project %s already exists", project["projectName"])
+		return
+	}
+	project["id"] = s.newID()
+	s.projects = append(s.projects, project)
+	writeJSON(w, http.StatusOK, renderProject(project))
+}
+
+// renderProject returns project with the values of its encrypted variables masked
+func renderProject(project document) document {
+	rendered := clone(project)
+	maskEncryptedValues(rendered)
+	return rendered
+}
+
+func (s *Server) getProject(key string) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, sess session) {
+		i := indexOf(s.projects, key, r.PathValue("project"))
+		if i < 0 {
+			writeNotFound(w, "project", r.PathValue("project"))
+			return
+		}
+		if decryptVariables(r) {
+			writeJSON(w, http.StatusOK, clone(s.projects[i]))
+			return
+		}
+		writeJSON(w, http.StatusOK, renderProject(s.projects[i]))
+	}
+}
+
+// decryptVariables returns true if the request asks for the values of the encrypted variables
+func decryptVariables(r *http.Request) bool {
+	return r.URL.Query().Get("decryptVariables") == "true"
+}
+
+func (s *Server) updateProject(w http.ResponseWriter, r *http.Request, sess session) {
+	id := r.PathValue("project")
+	i := indexOf(s.projects, "id", id)
+	if i < 0 {
+		writeNotFound(w, "project", id)
+		return
+	}
+	var project document
+	if !readJSON(w, r, &project) {
+		return
+	}
+	project["id"] = id
+	s.projects[i] = project
+	writeJSON(w, http.StatusOK, renderProject(project))
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request, sess session) {
+	id := r.PathValue("project")
+	i := indexOf(s.projects, "id", id)
+	if i < 0 {
+		writeNotFound(w, "project", id)
+		return
+	}
+	for _, pipeline := range s.pipelines {
+		if str(metadata(pipeline)["projectId"]) == id {
+			writeError(w, http.StatusBadRequest, "project %s still contains pipelines", id)
+			return
+		}
+	}
+	s.projects = removeAt(s.projects, i)
+	writeJSON(w, http.StatusOK, document{})
+}
+
+// pipelines
+
+// metadata returns the metadata object of doc, creating it if missing
+func metadata(doc document) document {
+	m, _ := doc["metadata"].(map[string]interface{})
+	if m == nil {
+		m = document{}
+		doc["metadata"] = m
+	}
+	return m
+}
+
+// pipelineIndex returns the index of the pipeline with the given ID or full name, or -1
+func (s *Server) pipelineIndex(idOrName string) int {
+	for i, pipeline := range s.pipelines {
+		if m := metadata(pipeline); str(m["id"]) == idOrName || str(m["name"]) == idOrName {
+			return i
+		}
+	}
+	return -1
+}
+
+// setPipelineProject fills the project of the pipeline from the prefix of its name, like "project/pipeline"
+func (s *Server) setPipelineProject(pipeline document) {
+	m := metadata(pipeline)
+	delete(m, "project")
+	delete(m, "projectId")
+	if project, _, ok := strings.Cut(str(m["name"]), "/"); ok {
+		m["project"] = project
+		if i := indexOf(s.projects, "projectName", project); i >= 0 {
+			m["projectId"] = s.projects[i]["id"]
+		}
+	}
+}
+
+// renderPipeline returns pipeline with the values of its encrypted variables masked
+func renderPipeline(pipeline document) document {
+	rendered := clone(pipeline)
+	maskEncryptedValues(rendered["spec"])
+	return rendered
+}
+
+func (s *Server) listPipelines(w http.ResponseWriter, r *http.Request, sess session) {
+	docs := make([]document, 0, len(s.pipelines))
+	for _, pipeline := range s.pipelines {
+		docs = append(docs, renderPipeline(pipeline))
+	}
+	writeJSON(w, http.StatusOK, document{"docs": docs, "count": len(docs)})
+}
+
+func (s *Server) createPipeline(w http.ResponseWriter, r *http.Request, sess session) {
+	var pipeline document
+	if !readJSON(w, r, &pipeline) {
+		return
+	}
+	m := metadata(pipeline)
+	name := str(m["name"])
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "pipeline name is required")
+		return
+	}
+	if s.pipelineIndex(name) >= 0 {
+		writeError(w, http.StatusConflict, "pipeline %s already exists", name)
+		return
+	}
+	m["id"] = s.newID()
+	m["accountId"] = sess.accountID
+	m["revision"] = 1
+	s.setPipelineProject(pipeline)
+	s.pipelines = append(s.pipelines, pipeline)
+	writeJSON(w, http.StatusOK, renderPipeline(pipeline))
+}
+
+func (s *Server) getPipeline(w http.ResponseWriter, r *http.Request, sess session) {
+	i := s.pipelineIndex(r.PathValue("pipeline"))
+	if i < 0 {
+		writeNotFound(w, "pipeline", r.PathValue("pipeline"))
+		return
+	}
+	if decryptVariables(r) {
+		writeJSON(w, http.StatusOK, clone(s.pipelines[i]))
+		return
+	}
+	writeJSON(w, http.StatusOK, renderPipeline(s.pipelines[i]))
+}
+
+func (s *Server) updatePipeline(w http.ResponseWriter, r *http.Request, sess session) {
+	i := s.pipelineIndex(r.PathValue("pipeline"))
+	if i < 0 {
+		writeNotFound(w, "pipeline", r.PathValue("pipeline"))
+		return
+	}
+	var pipeline document
+	if !readJSON(w, r, &pipeline) {
+		return
+	}
+	current := metadata(s.pipelines[i])
+	m := metadata(pipeline)
+	if j := s.pipelineIndex(str(m["name"])); j >= 0 && j != i {
+		writeError(w, http.StatusConflict, "pipeline %s already exists", m["name"])
+		return
+	}
+	revision, _ := current["revision"].(int)
+	// an update sending a revision is only applied to that revision, so that concurrent updates conflict
+	if expected, ok := m["revision"].(float64); ok && int(expected) != revision {
+		writeError(w, http.StatusConflict, "pipeline %s was modified: revision %d is not the current revision %d", current["name"], int(expected), revision)
+		return
+	}
+	m["id"] = current["id"]
+	m["accountId"] = current["accountId"]
+	m["revision"] = revision + 1
+	s.setPipelineProject(pipeline)
+	s.pipelines[i] = pipeline
+	writeJSON(w, http.StatusOK, renderPipeline(pipeline))
+}
+
+func (s *Server) deletePipeline(w http.ResponseWriter, r *http.Request, sess session) {
+	i := s.pipelineIndex(r.PathValue("pipeline"))
+	if i < 0 {
+		writeNotFound(w, "pipeline", r.PathValue("pipeline"))
+		return
+	}
+	s.pipelines = removeAt(s.pipelines, i)
+	writeJSON(w, http.StatusOK, document{})
+}
+
+// hermes trigger events and the pipelines they trigger
+
+// eventParam returns the event in the path, which Codefresh expects to be URI encoded twice
+func eventParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	event, err := url.QueryUnescape(r.PathValue("event"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event %s: %v", r.PathValue("event"), err)
+		return "", false
+	}
+	return event, true
+}
+
+func (s *Server) findHermesEvent(w http.ResponseWriter, r *http.Request) (document, bool) {
+	event, ok := eventParam(w, r)
+	if !ok {
+		return nil, false
+	}
+	i := indexOf(s.hermesEvents, "uri", event)
+	if i < 0 {
+		writeNotFound(w, "trigger event", event)
+		return nil, false
+	}
+	return s.hermesEvents[i], true
+}
+
+func (s *Server) createHermesEvent(w http.ResponseWriter, r *http.Request, sess session) {
+	var event document
+	if !readJSON(w, r, &event) {
+		return
+	}
+	values, _ := event["values"].(map[string]interface{})
+	var uri string
+	if str(event["type"]) == "cron" {
+		uri = fmt.Sprintf("cron:codefresh:%s:%s:%s", str(values["expression"]), str(values["message"]), s.newID())
+	} else if triggerType, ok := triggertypes.Find(str(event["type"]), str(event["kind"])); ok {
+		// Codefresh appends the account to the URI of the event
+		parts := []string{triggerType.Type, triggerType.Kind}
+		for _, value := range triggerType.Values {
+			parts = append(parts, str(values[value]))
+		}
+		uri = strings.Join(append(parts, sess.accountID), ":")
+	} else {
+		writeError(w, http.StatusBadRequest, "unsupported event type %s and kind %s", str(event["type"]), str(event["kind"]))
+		return
+	}
+	if str(event["secret"]) == "!generate" {
+		event["secret"] = s.newID()
+	}
+	event["uri"] = uri
+	event["account"] = sess.accountID
+	event["endpoint"] = fmt.Sprintf("%s/nomios/%s?account=%s&secret=%s", s.URL, str(event["kind"]), sess.accountID, str(event["secret"]))
+	s.hermesEvents = append(s.hermesEvents, event)
+	writeJSON(w, http.StatusOK, uri)
+}
+
+func (s *Server) getHermesEvent(w http.ResponseWriter, r *http.Request, sess session) {
+	if event, ok := s.findHermesEvent(w, r); ok {
+		writeJSON(w, http.StatusOK, clone(event))
+	}
+}
+
+// deleteHermesEvent deletes the event and the triggers of pipelines on it
+func (s *Server) deleteHermesEvent(w http.ResponseWriter, r *http.Request, sess session) {
+	event, ok := s.findHermesEvent(w, r)
+	if !ok {
+		return
+	}
+	s.hermesEvents = removeAt(s.hermesEvents, indexOf(s.hermesEvents, "uri", str(event["uri"])))
+	s.hermesTriggers = slices.DeleteFunc(s.hermesTriggers, func(trigger document) bool {
+		return trigger["event"] == event["uri"]
+	})
+	writeJSON(w, http.StatusOK, document{})
+}
+
+func (s *Server) listHermesTriggers(w http.ResponseWriter, r *http.Request, sess session) {
+	event, ok := eventParam(w, r)
+	if !ok {
+		return
+	}
+	triggers := make([]document, 0)
+	for _, trigger := range s.hermesTriggers {
+		if str(trigger["event"]) == event {
+			triggers = append(triggers, clone(trigger))
+		}
+	}
+	writeJSON(w, http.StatusOK, triggers)
+}
+
+func (s *Server) createHermesTrigger(w http.ResponseWriter, r *http.Request, sess session) {
+	event, ok := s.findHermesEvent(w, r)
+	if !ok {
+		return
+	}
+	pipeline := r.PathValue("pipeline")
+	if s.pipelineIndex(pipeline) < 0 {
+		writeNotFound(w, "pipeline", pipeline)
+		return
+	}
+	s.hermesTriggers = append(s.hermesTriggers, document{
+		"event":    event["uri"],
+		"pipeline": pipeline,
+		"event-data": document{
+			"uri":     event["uri"],
+			"type":    event["type"],
+			"kind":    event["kind"],
+			"account": event["account"],
+			"secret":  event["secret"],
+		},
+	})
+	writeJSON(w, http.StatusOK, document{})
+}
+
+func (s *Server) deleteHermesTrigger(w http.ResponseWriter, r *http.Request, sess session) {
+	event, ok := eventParam(w, r)
+	if !ok {
+		return
+	}
+	for i, trigger := range s.hermesTriggers {
+		if str(trigger["event"]) == event && str(trigger["pipeline"]) == r.PathValue("pipeline") {
+			s.hermesTriggers = removeAt(s.hermesTriggers, i)
+			writeJSON(w, http.StatusOK, document{})
+			return
+		}
+	}
+	writeNotFound(w, "trigger", event)
+}
+
+// builds
+
+func (s *Server) runPipeline(w http.ResponseWriter, r *http.Request, sess session) {
+	i := s.pipelineIndex(r.PathValue("pipeline"))
+	if i < 0 {
+		writeNotFound(w, "pipeline", r.PathValue("pipeline"))
+		return
+	}
+	var build document
+	if !readJSON(w, r, &build) {
+		return
+	}
+	result := s.BuildResult
+	if result == "" {
+		result = "success"
+	}
+	build["id"] = s.newID()
+	build["serviceId"] = metadata(s.pipelines[i])["id"]
+	build["serviceName"] = metadata(s.pipelines[i])["name"]
+	build["branchName"] = build["branch"]
+	build["trigger"] = "build"
+	build["created"] = time.Now().UTC().Format(time.RFC3339Nano)
+	build["status"] = "pending"
+	build["result"] = result
+	s.builds = append(s.builds, build)
+	writeJSON(w, http.StatusOK, build["id"])
+}
+
+// getBuild returns the build, after moving it to its next status
+func (s *Server) getBuild(w http.ResponseWriter, r *http.Request, sess session) {
+	i := indexOf(s.builds, "id", r.PathValue("build"))
+	if i < 0 {
+		writeNotFound(w, "build", r.PathValue("build"))
+		return
+	}
+	build := s.builds[i]
+	switch str(build["status"]) {
+	case "pending":
+		build["status"] = "running"
+		build["started"] = time.Now().UTC().Format(time.RFC3339Nano)
+	case "running":
+		build["status"] = build["result"]
+		build["finished"] = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	writeJSON(w, http.StatusOK, renderBuild(build))
+}
+
+// listBuilds returns a page of the builds matching the query, the most recent first
+func (s *Server) listBuilds(w http.ResponseWriter, r *http.Request, sess session) {
+	query := r.URL.Query()
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	builds := make([]document, 0)
+	for i := len(s.builds) - 1; i >= 0; i-- {
+		build := s.builds[i]
+		matches := true
+		for param, key := range map[string]string{"pipeline": "serviceId", "branchName": "branchName", "status": "status", "trigger": "trigger"} {
+			if value := query.Get(param); value != "" && str(build[key]) != value {
+				matches = false
+			}
+		}
+		created, _ := time.Parse(time.RFC3339Nano, str(build["created"]))
+		if since, err := time.Parse(time.RFC3339, query.Get("startDate")); err == nil && created.Before(since) {
+			matches = false
+		}
+		if until, err := time.Parse(time.RFC3339, query.Get("endDate")); err == nil && created.After(until) {
+			matches = false
+		}
+		if matches {
+			builds = append(builds, renderBuild(build))
+		}
+	}
+
+	pages := (len(builds) + limit - 1) / limit
+	start := min((page-1)*limit, len(builds))
+	end := min(start+limit, len(builds))
+	writeJSON(w, http.StatusOK, document{
+		"workflows": document{
+			"docs":  builds[start:end],
+			"total": len(builds),
+			"pages": pages,
+		},
+	})
+}
+
+func renderBuild(build document) document {
+	rendered := clone(build)
+	delete(rendered, "result")
+	return rendered
+}
+
+// Builds returns the builds started on the server, in the shape of the requests to run the pipelines
+func (s *Server) Builds() []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	builds := make([]map[string]interface{}, 0, len(s.builds))
+	for _, build := range s.builds {
+		builds = append(builds, clone(build))
+	}
+	return builds
+}
+
+// PurgeBuilds deletes all the builds, as the retention policy of Codefresh does for old builds
+func (s *Server) PurgeBuilds() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.builds = nil
+}
@@ -0,0 +1,487 @@
+package fakeapi
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// encryptedContextTypes are the context types whose data is only returned when decrypt=true
+var encryptedContextTypes = []string{"secret", "secret-yaml", "storage.s3", "storage.azuref"}
+
+func (s *Server) registerResourceRoutes(mux *http.ServeMux) {
+	s.handle(mux, "GET /contexts", s.listContexts)
+	s.handle(mux, "POST /contexts", s.createContext)
+	s.handle(mux, "GET /contexts/{context}", s.getContext)
+	s.handle(mux, "PUT /contexts/{context}", s.updateContext)
+	s.handle(mux, "DELETE /contexts/{context}", s.deleteContext)
+
+	s.handle(mux, "GET /registries", s.listRegistries)
+	s.handle(mux, "POST /registries", s.createRegistry)
+	s.handle(mux, "GET /registries/{registry}", s.getRegistry)
+	s.handle(mux, "PATCH /registries/{registry}", s.updateRegistry)
+	s.handle(mux, "DELETE /registries/{registry}", s.deleteRegistry)
+
+	s.handle(mux, "GET /step-types", s.listStepTypes)
+	s.handle(mux, "POST /step-types", s.createStepTypes)
+	s.handle(mux, "GET /step-types/{stepTypes}", s.getStepTypes)
+	s.handle(mux, "GET /step-types/{stepTypes}/versions", s.listStepTypesVersions)
+	s.handle(mux, "PUT /step-types/{stepTypes}", s.updateStepTypes)
+	s.handle(mux, "DELETE /step-types/{stepTypes}", s.deleteStepTypes)
+
+	s.handle(mux, "GET /admin/idp", s.listIDPs(&s.idps))
+	s.handle(mux, "POST /admin/idp", s.createIDP(&s.idps))
+	s.handle(mux, "PUT /admin/idp", s.updateIDP(&s.idps))
+	s.handle(mux, "DELETE /admin/idp/{idp}", s.deleteIDP)
+	s.handle(mux, "POST /admin/idp/addAccount", s.addAccountToIDP)
+	s.handle(mux, "GET /idp/account", s.listIDPs(&s.accountIDPs))
+	s.handle(mux, "POST /idp/account", s.createIDP(&s.accountIDPs))
+	s.handle(mux, "PUT /idp/account", s.updateIDP(&s.accountIDPs))
+	s.handle(mux, "DELETE /idp/account", s.deleteAccountIDP)
+
+	s.handle(mux, "GET /abac", s.listPermissions)
+	s.handle(mux, "POST /abac", s.createPermission)
+	s.handle(mux, "GET /abac/{permission}", s.getPermission)
+	s.handle(mux, "DELETE /abac/{permission}", s.deletePermission)
+	s.handle(mux, "POST /abac/tags/rule/{permission}", s.updatePermissionTags)
+}
+
+// contexts, identified by their name
+
+func (s *Server) contextIndex(name string) int {
+	for i, context := range s.contexts {
+		if str(metadata(context)["name"]) == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// listContexts returns the contexts with the data of the encrypted ones masked
+func (s *Server) listContexts(w http.ResponseWriter, r *http.Request, sess session) {
+	contexts := make([]document, 0, len(s.contexts))
+	for _, context := range s.contexts {
+		context = clone(context)
+		spec, _ := context["spec"].(map[string]interface{})
+		if slices.Contains(encryptedContextTypes, str(spec["type"])) {
+			spec["data"] = maskLeaves(spec["data"])
+		}
+		contexts = append(contexts, context)
+	}
+	writeJSON(w, http.StatusOK, contexts)
+}
+
+func (s *Server) createContext(w http.ResponseWriter, r *http.Request, sess session) {
+	var context document
+	if !readJSON(w, r, &context) {
+		return
+	}
+	name := str(metadata(context)["name"])
+	if s.contextIndex(name) >= 0 {
+		writeError(w, http.StatusConflict, "context %s already exists", name)
+		return
+	}
+	s.contexts = append(s.contexts, context)
+	writeJSON(w, http.StatusOK, clone(context))
+}
+
+func (s *Server) getContext(w http.ResponseWriter, r *http.Request, sess session) {
+	name := r.PathValue("context")
+	i := s.contextIndex(name)
+	if i < 0 {
+		writeNotFound(w, "context", name)
+		return
+	}
+	context := clone(s.contexts[i])
+	spec, _ := context["spec"].(map[string]interface{})
+	if r.URL.Query().Get("decrypt") != "true" && slices.Contains(encryptedContextTypes, str(spec["type"])) {
+		spec["data"] = maskLeaves(spec["data"])
+	}
+	writeJSON(w, http.StatusOK, context)
+}
+
+// maskLeaves replaces every string of value the way Codefresh hides encrypted context data
+func maskLeaves(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = maskLeaves(child)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = maskLeaves(child)
+		}
+		return v
+	case string:
+		return "*****"
+	}
+	return value
+}
+
+func (s *Server) updateContext(w http.ResponseWriter, r *http.Request, sess session) {
+	name := r.PathValue("context")
+	i := s.contextIndex(name)
+	if i < 0 {
+		writeNotFound(w, "context", name)
+		return
+	}
+	var context document
+	if !readJSON(w, r, &context) {
+		return
+	}
+	metadata(context)["name"] = name
+	s.contexts[i] = context
+	writeJSON(w, http.StatusOK, clone(context))
+}
+
+func (s *Server) deleteContext(w http.ResponseWriter, r *http.Request, sess session) {
+	name := r.PathValue("context")
+	i := s.contextIndex(name)
+	if i < 0 {
+		writeNotFound(w, "context", name)
+		return
+	}
+	s.contexts = removeAt(s.contexts, i)
+	writeJSON(w, http.StatusOK, document{})
+}
+
+// registries, read by ID or name and written by ID
+
+func (s *Server) registryIndex(idOrName string) int {
+	if i := indexOf(s.registries, "_id", idOrName); i >= 0 {
+		return i
+	}
+	return indexOf(s.registries, "name", idOrName)
+}
+
+func (s *Server) createRegistry(w http.ResponseWriter, r *http.Request, sess session) {
+	var registry document
+	if !readJSON(w, r, &registry) {
+		return
+	}
+	if indexOf(s.registries, "name", str(registry["name"])) >= 0 {
+		writeError(w, http.StatusConflict, "registry %s already exists", registry["name"])
+		return
+	}
+	registry["_id"] = s.newID()
+	s.registries = append(s.registries, registry)
+	writeJSON(w, http.StatusOK, clone(registry))
+}
+
+func (s *Server) listRegistries(w http.ResponseWriter, r *http.Request, sess session) {
+	registries := make([]document, 0, len(s.registries))
+	for _, registry := range s.registries {
+		registries = append(registries, clone(registry))
+	}
+	writeJSON(w, http.StatusOK, registries)
+}
+
+func (s *Server) getRegistry(w http.ResponseWriter, r *http.Request, sess session) {
+	i := s.registryIndex(r.PathValue("registry"))
+	if i < 0 {
+		writeNotFound(w, "registry", r.PathValue("registry"))
+		return
+	}
+	writeJSON(w, http.StatusOK, clone(s.registries[i]))
+}
+
+func (s *Server) updateRegistry(w http.ResponseWriter, r *http.Request, sess session) {
+	id := r.PathValue("registry")
+	i := indexOf(s.registries, "_id", id)
+	if i < 0 {
+		writeNotFound(w, "registry", id)
+		return
+	}
+	var registry document
+	if !readJSON(w, r, &registry) {
+		return
+	}
+	registry["_id"] = id
+	s.registries[i] = registry
+	writeJSON(w, http.StatusOK, clone(registry))
+}
+
+func (s *Server) deleteRegistry(w http.ResponseWriter, r *http.Request, sess session) {
+	i := s.registryIndex(r.PathValue("registry"))
+	if i < 0 {
+		writeNotFound(w, "registry", r.PathValue("registry"))
+		return
+	}
+	s.registries = removeAt(s.registries, i)
+	writeJSON(w, http.StatusOK, document{})
+}
+
+// step types, stored one document per version and identified by "name" or "name:version"
+
+func (s *Server) stepTypesIndex(name, version string) int {
+	for i, stepTypes := range s.stepTypes {
+		if m := metadata(stepTypes); str(m["name"]) == name && str(m["version"]) == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// stepTypesVersions returns the versions of the step type in the order they were created
+func (s *Server) stepTypesVersions(name string) []string {
+	versions := make([]string, 0)
+	for _, stepTypes := range s.stepTypes {
+		if m := metadata(stepTypes); str(m["name"]) == name {
+			versions = append(versions, str(m["version"]))
+		}
+	}
+	return versions
+}
+
+// findStepTypes returns the index of the step type identified by "name:version", or of its latest version when identified by "name"
+func (s *Server) findStepTypes(w http.ResponseWriter, identifier string) (int, bool) {
+	name, version, ok := strings.Cut(identifier, ":")
+	if !ok {
+		if versions := s.stepTypesVersions(name); len(versions) > 0 {
+			version = versions[len(versions)-1]
+		}
+	}
+	i := s.stepTypesIndex(name, version)
+	if i < 0 {
+		writeNotFound(w, "step types", identifier)
+		return -1, false
+	}
+	return i, true
+}
+
+func (s *Server) createStepTypes(w http.ResponseWriter, r *http.Request, sess session) {
+	var stepTypes document
+	if !readJSON(w, r, &stepTypes) {
+		return
+	}
+	m := metadata(stepTypes)
+	if s.stepTypesIndex(str(m["name"]), str(m["version"])) >= 0 {
+		writeError(w, http.StatusConflict, "step types %s:%s already exists", m["name"], m["version"])
+		return
+	}
+	m["accountId"] = sess.accountID
+	s.stepTypes = append(s.stepTypes, stepTypes)
+	writeJSON(w, http.StatusOK, clone(stepTypes))
+}
+
+// listStepTypes returns the latest version of each step type
+func (s *Server) listStepTypes(w http.ResponseWriter, r *http.Request, sess session) {
+	docs := make([]document, 0)
+	for _, stepTypes := range s.stepTypes {
+		m := metadata(stepTypes)
+		versions := s.stepTypesVersions(str(m["name"]))
+		if str(m["version"]) == versions[len(versions)-1] {
+			docs = append(docs, clone(stepTypes))
+		}
+	}
+	writeJSON(w, http.StatusOK, document{"docs": docs, "count": len(docs)})
+}
+
+func (s *Server) getStepTypes(w http.ResponseWriter, r *http.Request, sess session) {
+	if i, ok := s.findStepTypes(w, r.PathValue("stepTypes")); ok {
+		writeJSON(w, http.StatusOK, clone(s.stepTypes[i]))
+	}
+}
+
+func (s *Server) listStepTypesVersions(w http.ResponseWriter, r *http.Request, sess session) {
+	versions := s.stepTypesVersions(r.PathValue("stepTypes"))
+	if len(versions) == 0 {
+		writeNotFound(w, "step types", r.PathValue("stepTypes"))
+		return
+	}
+	writeJSON(w, http.StatusOK, versions)
+}
+
+func (s *Server) updateStepTypes(w http.ResponseWriter, r *http.Request, sess session) {
+	i, ok := s.findStepTypes(w, r.PathValue("stepTypes"))
+	if !ok {
+		return
+	}
+	var stepTypes document
+	if !readJSON(w, r, &stepTypes) {
+		return
+	}
+	current, m := metadata(s.stepTypes[i]), metadata(stepTypes)
+	m["name"], m["version"], m["accountId"] = current["name"], current["version"], current["accountId"]
+	s.stepTypes[i] = stepTypes
+	writeJSON(w, http.StatusOK, clone(stepTypes))
+}
+
+func (s *Server) deleteStepTypes(w http.ResponseWriter, r *http.Request, sess session) {
+	name, version, hasVersion := strings.Cut(r.PathValue("stepTypes"), ":")
+	remaining := slices.DeleteFunc(slices.Clone(s.stepTypes), func(stepTypes document) bool {
+		m := metadata(stepTypes)
+		return str(m["name"]) == name && (!hasVersion || str(m["version"]) == version)
+	})
+	if len(remaining) == len(s.stepTypes) {
+		writeNotFound(w, "step types", r.PathValue("stepTypes"))
+		return
+	}
+	s.stepTypes = remaining
+	writeJSON(w, http.StatusOK, document{})
+}
+
+// identity providers, either platform wide or of the account of the session
+
+func (s *Server) listIDPs(idps *[]document) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, sess session) {
+		result := make([]document, 0, len(*idps))
+		for _, idp := range *idps {
+			result = append(result, clone(idp))
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+func (s *Server) createIDP(idps *[]document) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, sess session) {
+		var idp document
+		if !readJSON(w, r, &idp) {
+			return
+		}
+		id := s.newID()
+		idp["_id"] = id
+		if idps == &s.accountIDPs {
+			idp["accounts"] = []interface{}{sess.accountID}
+		}
+		*idps = append(*idps, idp)
+		writeJSON(w, http.StatusOK, document{"id": id})
+	}
+}
+
+func (s *Server) updateIDP(idps *[]document) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, sess session) {
+		var idp document
+		if !readJSON(w, r, &idp) {
+			return
+		}
+		i := indexOf(*idps, "_id", str(idp["_id"]))
+		if i < 0 {
+			writeNotFound(w, "idp", str(idp["_id"]))
+			return
+		}
+		if _, ok := idp["accounts"]; !ok {
+			idp["accounts"] = (*idps)[i]["accounts"]
+		}
+		(*idps)[i] = idp
+		writeJSON(w, http.StatusOK, clone(idp))
+	}
+}
+
+func (s *Server) deleteIDP(w http.ResponseWriter, r *http.Request, sess session) {
+	i := indexOf(s.idps, "_id", r.PathValue("idp"))
+	if i < 0 {
+		writeNotFound(w, "idp", r.PathValue("idp"))
+		return
+	}
+	s.idps = removeAt(s.idps, i)
+	writeJSON(w, http.StatusOK, document{})
+}
+
+func (s *Server) deleteAccountIDP(w http.ResponseWriter, r *http.Request, sess session) {
+	var body struct {
+		ID string `json:"id"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	i := indexOf(s.accountIDPs, "_id", body.ID)
+	if i < 0 {
+		writeNotFound(w, "idp", body.ID)
+		return
+	}
+	s.accountIDPs = removeAt(s.accountIDPs, i)
+	writeJSON(w, http.StatusOK, document{})
+}
+
+func (s *Server) addAccountToIDP(w http.ResponseWriter, r *http.Request, sess session) {
+	var body struct {
+		AccountID   string `json:"accountId"`
+		IDPConfigID string `json:"IDPConfigId"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	i := indexOf(s.idps, "_id", body.IDPConfigID)
+	if i < 0 {
+		writeNotFound(w, "idp", body.IDPConfigID)
+		return
+	}
+	s.idps[i]["accounts"] = toArray(setMember(stringSlice(s.idps[i]["accounts"]), body.AccountID, true))
+	writeJSON(w, http.StatusOK, document{})
+}
+
+// permissions, created with the team in "team" and returned with it in "role"
+
+func (s *Server) findPermission(w http.ResponseWriter, id string) (int, bool) {
+	i := indexOf(s.permissions, "id", id)
+	if i < 0 {
+		writeNotFound(w, "permission", id)
+		return -1, false
+	}
+	return i, true
+}
+
+func (s *Server) listPermissions(w http.ResponseWriter, r *http.Request, sess session) {
+	permissions := make([]document, 0, len(s.permissions))
+	for _, permission := range s.permissions {
+		permissions = append(permissions, clone(permission))
+	}
+	writeJSON(w, http.StatusOK, permissions)
+}
+
+func (s *Server) createPermission(w http.ResponseWriter, r *http.Request, sess session) {
+	var body struct {
+		Team            string   `json:"team"`
+		Resource        string   `json:"resource"`
+		RelatedResource string   `json:"relatedResource"`
+		Action          string   `json:"action"`
+		Tags            []string `json:"tags"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if _, ok := s.findTeam(w, body.Team); !ok {
+		return
+	}
+	permission := document{
+		"id":         s.newID(),
+		"role":       body.Team,
+		"resource":   body.Resource,
+		"action":     body.Action,
+		"account":    sess.accountID,
+		"attributes": toArray(body.Tags),
+	}
+	if body.RelatedResource != "" {
+		permission["relatedResource"] = body.RelatedResource
+	}
+	s.permissions = append(s.permissions, permission)
+	writeJSON(w, http.StatusOK, []document{clone(permission)})
+}
+
+func (s *Server) getPermission(w http.ResponseWriter, r *http.Request, sess session) {
+	if i, ok := s.findPermission(w, r.PathValue("permission")); ok {
+		writeJSON(w, http.StatusOK, clone(s.permissions[i]))
+	}
+}
+
+func (s *Server) deletePermission(w http.ResponseWriter, r *http.Request, sess session) {
+	if i, ok := s.findPermission(w, r.PathValue("permission")); ok {
+		s.permissions = removeAt(s.permissions, i)
+		writeJSON(w, http.StatusOK, document{})
+	}
+}
+
+func (s *Server) updatePermissionTags(w http.ResponseWriter, r *http.Request, sess session) {
+	i, ok := s.findPermission(w, r.PathValue("permission"))
+	if !ok {
+		return
+	}
+	var tags []string
+	if !readJSON(w, r, &tags) {
+		return
+	}
+	s.permissions[i]["attributes"] = toArray(tags)
+	writeJSON(w, http.StatusOK, clone(s.permissions[i]))
+}
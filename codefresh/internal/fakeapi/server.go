@@ -0,0 +1,244 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// APIKey is the token accepted by the fake server for the seeded admin user
+const APIKey = "fake-api-key"
+
+// document is a JSON object stored by the fake server, kept in the shape returned by the Codefresh API
+type document = map[string]interface{}
+
+// session is the user and account a token acts as
+type session struct {
+	userID    string
+	accountID string
+}
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request, sess session)
+
+// Server is a fake Codefresh API backed by in-memory state.
+// It is seeded with one account, AccountName, administered by one user, AdminUserName, who authenticates with APIKey.
+// All entities live in a single namespace shared by every account.
+type Server struct {
+	*httptest.Server
+
+	AccountID     string
+	AccountName   string
+	AdminUserID   string
+	AdminUserName string
+	// BuildResult is the final status of the builds started after it is set, success if empty.
+	// Builds move from pending to running, then to their final status, on each read.
+	BuildResult string
+
+	mu       sync.Mutex
+	nextID   int
+	sessions map[string]session
+
+	accounts       []document
+	users          []document
+	teams          []document
+	serviceUsers   []document
+	apiKeys        []document
+	permissions    []document
+	projects       []document
+	pipelines      []document
+	contexts       []document
+	registries     []document
+	stepTypes      []document
+	idps           []document
+	accountIDPs    []document
+	hermesEvents   []document
+	hermesTriggers []document
+	builds         []document
+	abacRules      []document
+	gitopsSettings map[string]document
+}
+
+// NewServer starts a fake server seeded with an account and its admin user. The caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		AccountName:    "fake-account",
+		AdminUserName:  "fake-admin",
+		sessions:       map[string]session{},
+		gitopsSettings: map[string]document{},
+	}
+	s.AccountID = s.newID()
+	s.AdminUserID = s.newID()
+	s.accounts = append(s.accounts, document{
+		"_id":      s.AccountID,
+		"name":     s.AccountName,
+		"admins":   []interface{}{s.AdminUserID},
+		"features": document{},
+		"limits":   document{"collaborators": document{"limit": 100}},
+	})
+	s.users = append(s.users, document{
+		"_id":      s.AdminUserID,
+		"userName": s.AdminUserName,
+		"email":    s.AdminUserName + "@codefresh.io",
+		"status":   "active",
+		"roles":    []interface{}{"User", "Admin"},
+		"account":  []interface{}{s.AccountID},
+	})
+	s.sessions[APIKey] = session{userID: s.AdminUserID, accountID: s.AccountID}
+
+	api := http.NewServeMux()
+	s.registerAccountRoutes(api)
+	s.registerPipelineRoutes(api)
+	s.registerResourceRoutes(api)
+
+	root := http.NewServeMux()
+	root.Handle("/api/", http.StripPrefix("/api", api))
+	s.handle(root, "POST /2.0/api/graphql", s.graphql)
+
+	s.Server = httptest.NewServer(root)
+	return s
+}
+
+// APIURL returns the URL of the fake REST API, to be used as the provider api_url
+func (s *Server) APIURL() string {
+	return s.URL + "/api"
+}
+
+// GraphQLURL returns the URL of the fake GitOps GraphQL API, to be used as the provider api_url_v2
+func (s *Server) GraphQLURL() string {
+	return s.URL + "/2.0/api/graphql"
+}
+
+// handle registers handler for pattern, serializing the access to the state and rejecting unauthenticated requests
+func (s *Server) handle(mux *http.ServeMux, pattern string, handler handlerFunc) {
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		sess, ok := s.authenticate(r)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "invalid or missing token")
+			return
+		}
+		handler(w, r, sess)
+	})
+}
+
+// authenticate returns the session of the x-access-token header if set, otherwise of the Authorization header
+func (s *Server) authenticate(r *http.Request) (session, bool) {
+	token := r.Header.Get("x-access-token")
+	if token == "" {
+		token = r.Header.Get("Authorization")
+	}
+	sess, ok := s.sessions[token]
+	return sess, ok
+}
+
+// issueToken returns a new token acting as userID in accountID
+func (s *Server) issueToken(userID, accountID string) string {
+	token := fmt.Sprintf("%s.%s", s.newID(), s.newID())
+	s.sessions[token] = session{userID: userID, accountID: accountID}
+	return token
+}
+
+// newID returns a unique identifier in the format of MongoDB object IDs used by Codefresh
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("%024x", s.nextID)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeError answers with the error body of the Codefresh API
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, document{
+		"status":  status,
+		"message": fmt.Sprintf(format, args...),
+		"error":   http.StatusText(status),
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, kind, id string) {
+	writeError(w, http.StatusNotFound, "%s %s not found", kind, id)
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, target interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(target); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: %v", err)
+		return false
+	}
+	return true
+}
+
+// clone returns a deep copy of doc, so that responses never share state with the store
+func clone(doc document) document {
+	data, _ := json.Marshal(doc)
+	var copied document
+	_ = json.Unmarshal(data, &copied)
+	return copied
+}
+
+// indexOf returns the index of the first document whose key is value, or -1
+func indexOf(docs []document, key, value string) int {
+	for i, doc := range docs {
+		if str(doc[key]) == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func removeAt(docs []document, i int) []document {
+	return append(docs[:i], docs[i+1:]...)
+}
+
+// str returns value if it is a string and "" otherwise
+func str(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
+
+// stringSlice returns the string items of value if it is a JSON array
+func stringSlice(value interface{}) []string {
+	items, _ := value.([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// toArray converts values to a JSON array
+func toArray(values []string) []interface{} {
+	result := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		result = append(result, value)
+	}
+	return result
+}
+
+// maskEncryptedValues replaces, in place, the value of every encrypted variable found in value the way Codefresh does
+func maskEncryptedValues(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if encrypted, ok := v["encrypted"].(bool); ok && encrypted {
+			if _, ok := v["value"]; ok {
+				v["value"] = "*****"
+			}
+		}
+		for _, child := range v {
+			maskEncryptedValues(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			maskEncryptedValues(child)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package fakeapi
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+)
+
+func newTestClient(t *testing.T) (*Server, *cfclient.Client) {
+	server := NewServer()
+	t.Cleanup(server.Close)
+	client := cfclient.NewClient(server.APIURL(), server.GraphQLURL(), APIKey, "")
+	client.RetryMaxAttempts = 1
+	return server, client
+}
+
+func TestServerRejectsUnknownTokens(t *testing.T) {
+	server, _ := newTestClient(t)
+	client := cfclient.NewClient(server.APIURL(), server.GraphQLURL(), "wrong-token", "")
+	client.RetryMaxAttempts = 1
+
+	if _, err := client.GetPipelines(context.Background()); err == nil {
+		t.Fatal("expected the request to be rejected")
+	}
+}
+
+func TestServerCurrentAccount(t *testing.T) {
+	server, client := newTestClient(t)
+
+	account, err := client.GetCurrentAccount(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if account.ID != server.AccountID || account.Name != server.AccountName {
+		t.Fatalf("expected the seeded account, got %s %s", account.ID, account.Name)
+	}
+	if len(account.Admins) != 1 || account.Admins[0].UserName != server.AdminUserName {
+		t.Fatalf("expected the seeded admin, got %v", account.Admins)
+	}
+
+	impersonated, err := client.ImpersonateAccount(context.Background(), server.AccountID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := impersonated.GetPipelines(context.Background()); err != nil {
+		t.Fatalf("expected the impersonation token to be accepted, got %v", err)
+	}
+}
+
+func TestServerPipelines(t *testing.T) {
+	_, client := newTestClient(t)
+	ctx := context.Background()
+
+	project, err := client.CreateProject(ctx, &cfclient.Project{ProjectName: "project"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pipeline := &cfclient.Pipeline{Metadata: cfclient.Metadata{Name: "project/pipeline"}}
+	pipeline.SetVariables(map[string]interface{}{"SECRET": "s3cr3t"}, true)
+	created, err := client.CreatePipeline(ctx, pipeline)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Metadata.ID == "" || created.Metadata.ProjectId != project.ID {
+		t.Fatalf("expected an ID and the project to be set, got %+v", created.Metadata)
+	}
+	if created.Spec.Variables[0].Value != "*****" {
+		t.Fatalf("expected the encrypted variable to be masked, got %q", created.Spec.Variables[0].Value)
+	}
+
+	byName, err := client.GetPipeline(ctx, "project/pipeline")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if byName.Metadata.ID != created.Metadata.ID {
+		t.Fatalf("expected the pipeline to be found by name, got %s", byName.Metadata.ID)
+	}
+
+	created.Spec.Concurrency = 2
+	updated, err := client.UpdatePipeline(ctx, created)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Spec.Concurrency != 2 || updated.Metadata.Revision != created.Metadata.Revision+1 {
+		t.Fatalf("expected the update to be stored with a new revision, got %+v", updated)
+	}
+
+	if _, err := client.UpdatePipeline(ctx, created); !cfclient.IsConflict(err) {
+		t.Fatalf("expected an update of a stale revision to conflict, got %v", err)
+	}
+
+	if err := client.DeleteProject(ctx, project.ID); err == nil {
+		t.Fatal("expected a project containing pipelines not to be deleted")
+	}
+	if err := client.DeletePipeline(ctx, created.Metadata.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetPipeline(ctx, created.Metadata.ID); !cfclient.IsNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
+
+func TestServerCronTriggers(t *testing.T) {
+	_, client := newTestClient(t)
+	ctx := context.Background()
+
+	pipeline, err := client.CreatePipeline(ctx, &cfclient.Pipeline{Metadata: cfclient.Metadata{Name: "cron"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	event, err := client.CreateHermesTriggerEvent(ctx, &cfclient.HermesTriggerEvent{
+		Type:   "cron",
+		Kind:   "codefresh",
+		Values: map[string]string{"expression": "*/5 * * * *", "message": "hello"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.CreateHermesTriggerByEventAndPipeline(ctx, event, pipeline.Metadata.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	trigger, err := client.GetHermesTriggerByEventAndPipeline(ctx, event, pipeline.Metadata.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if trigger.Event != event {
+		t.Fatalf("expected event %s, got %s", event, trigger.Event)
+	}
+
+	if err := client.DeleteHermesTriggerByEventAndPipeline(ctx, event, pipeline.Metadata.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetHermesTriggerByEventAndPipeline(ctx, event, pipeline.Metadata.ID); !cfclient.IsNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
+
+func TestServerTeamsAndServiceUsers(t *testing.T) {
+	server, client := newTestClient(t)
+	ctx := context.Background()
+
+	team, err := client.CreateTeam(ctx, &cfclient.Team{Name: "team", Users: []cfclient.TeamUser{{ID: server.AdminUserID}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	serviceUser, err := client.CreateServiceUser(ctx, &cfclient.ServiceUserCreateUpdate{Name: "robot", TeamIDs: []string{team.ID}, AssignAdminRole: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !serviceUser.HasAdminRole() || len(serviceUser.Teams) != 1 || serviceUser.Teams[0].ID != team.ID {
+		t.Fatalf("expected an admin service user in the team, got %+v", serviceUser)
+	}
+
+	read, err := client.GetTeamByID(ctx, team.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	userNames := []string{}
+	for _, user := range read.Users {
+		userNames = append(userNames, user.UserName)
+	}
+	if !slices.Contains(userNames, server.AdminUserName) || !slices.Contains(userNames, "robot") {
+		t.Fatalf("expected the admin and the service user in the team, got %v", userNames)
+	}
+
+	token, err := client.CreateApiKeyServiceUser(ctx, serviceUser.ID, &cfclient.ApiKey{Name: "key", Scopes: []string{"pipeline"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keyClient := cfclient.NewClient(server.APIURL(), server.GraphQLURL(), token, "")
+	if _, err := keyClient.GetPipelines(ctx); err != nil {
+		t.Fatalf("expected the new API key to be accepted, got %v", err)
+	}
+
+	if err := client.DeleteServiceUser(ctx, serviceUser.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetServiceUserByID(ctx, serviceUser.ID); !cfclient.IsNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
+
+func TestServerStepTypesVersions(t *testing.T) {
+	_, client := newTestClient(t)
+	ctx := context.Background()
+
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		stepTypes := &cfclient.StepTypes{Metadata: map[string]interface{}{"name": "account/step", "version": version}}
+		if _, err := client.CreateStepTypes(ctx, stepTypes); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	versions, err := client.GetStepTypesVersions(ctx, "account/step")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(versions, []string{"1.0.0", "1.1.0"}) {
+		t.Fatalf("unexpected versions %v", versions)
+	}
+
+	if err := client.DeleteStepTypes(ctx, "account/step:1.1.0"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	latest, err := client.GetStepTypes(ctx, "account/step")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if latest.Metadata["version"] != "1.0.0" {
+		t.Fatalf("expected the remaining version to be the latest, got %v", latest.Metadata["version"])
+	}
+}
+
+func TestServerGitops(t *testing.T) {
+	server, client := newTestClient(t)
+	ctx := context.Background()
+
+	if err := client.UpdateActiveGitopsAccountSettings(ctx, "github", "https://api.github.com", "https://github.com/org/repo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	info, err := client.GetActiveGitopsAccountInfo(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.ID != server.AccountID || info.GitProvider != "github" || info.SharedConfigRepo != "https://github.com/org/repo" {
+		t.Fatalf("unexpected account info %+v", info)
+	}
+
+	rule, err := client.CreateAbacRule(ctx, &cfclient.GitopsAbacRule{EntityType: "gitopsApplications", Teams: []string{"team"}, Actions: []string{"SYNC"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rules, err := client.GetAbacRulesList(ctx, "gitopsApplications")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 1 || rules[0].ID != rule.ID {
+		t.Fatalf("expected the created rule to be listed, got %v", rules)
+	}
+	if _, err := client.DeleteAbacRule(ctx, rule.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rules, _ := client.GetAbacRulesList(ctx, "gitopsApplications"); len(rules) != 0 {
+		t.Fatalf("expected the rule to be removed, got %v", rules)
+	}
+}
@@ -3,6 +3,7 @@ package idp
 import (
 	"regexp"
 
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
@@ -502,3 +503,10 @@ var (
 		},
 	}
 )
+
+func init() {
+	// the client secrets can be set without being stored in state
+	for _, name := range []string{GitHub, GitLab, Okta, Google, Auth0, Azure, OneLogin, Keycloak} {
+		schemautil.AddWriteOnlyAttribute(IdpSchema[name].Elem.(*schema.Resource).Schema, name+".0.", "client_secret")
+	}
+}
@@ -0,0 +1,2 @@
+// Package mutexkv provides mutexes identified by a key, used to serialize the changes made by several resources to a shared object.
+package mutexkv
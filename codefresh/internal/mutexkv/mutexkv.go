@@ -0,0 +1,35 @@
+package mutexkv
+
+import "sync"
+
+// MutexKV is a set of mutexes identified by a key, created on first use
+type MutexKV struct {
+	mu      sync.Mutex
+	mutexes map[string]*sync.Mutex
+}
+
+// New returns an empty MutexKV
+func New() *MutexKV {
+	return &MutexKV{mutexes: map[string]*sync.Mutex{}}
+}
+
+// Lock locks the mutex of key
+func (m *MutexKV) Lock(key string) {
+	m.get(key).Lock()
+}
+
+// Unlock unlocks the mutex of key
+func (m *MutexKV) Unlock(key string) {
+	m.get(key).Unlock()
+}
+
+func (m *MutexKV) get(key string) *sync.Mutex {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mutex, ok := m.mutexes[key]
+	if !ok {
+		mutex = &sync.Mutex{}
+		m.mutexes[key] = mutex
+	}
+	return mutex
+}
@@ -0,0 +1,22 @@
+package schemautil
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// DefaultResourceTimeout is the default duration allowed for each operation of a resource.
+const DefaultResourceTimeout = 5 * time.Minute
+
+// DefaultResourceTimeouts returns the timeouts of a resource with the default duration for every operation.
+//
+// Users can override them with a timeouts block in the resource configuration.
+func DefaultResourceTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(DefaultResourceTimeout),
+		Read:   schema.DefaultTimeout(DefaultResourceTimeout),
+		Update: schema.DefaultTimeout(DefaultResourceTimeout),
+		Delete: schema.DefaultTimeout(DefaultResourceTimeout),
+	}
+}
@@ -1,8 +1,10 @@
 package schemautil
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"time"
 
 	"github.com/dlclark/regexp2"
 	"github.com/hashicorp/go-cty/cty"
@@ -74,6 +76,29 @@ func StringIsValidYaml(opts ...ValidationOptionSetter) schema.SchemaValidateDiag
 	}
 }
 
+// StringIsJsonObjectOfStrings returns a SchemaValidateDiagFunc which validates that a string is a JSON object whose values are strings.
+func StringIsJsonObjectOfStrings(opts ...ValidationOptionSetter) schema.SchemaValidateDiagFunc {
+	options := NewValidationOptions().
+		setSeverity(diag.Error).
+		setSummary("Invalid JSON object").
+		setDetailFormat("%s is not a JSON object of strings: %s").
+		apply(opts)
+
+	return func(v any, p cty.Path) diag.Diagnostics {
+		value := v.(string)
+		var diags diag.Diagnostics
+		var object map[string]string
+		if err := json.Unmarshal([]byte(value), &object); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: options.severity,
+				Summary:  options.summary,
+				Detail:   fmt.Sprintf(options.detailFormat, p, err),
+			})
+		}
+		return diags
+	}
+}
+
 // StringMatchesRegExp returns a SchemaValidateDiagFunc which validates that a string matches a regular expression.
 func StringMatchesRegExp(regex string, opts ...ValidationOptionSetter) schema.SchemaValidateDiagFunc {
 	options := NewValidationOptions().
@@ -97,3 +122,25 @@ func StringMatchesRegExp(regex string, opts ...ValidationOptionSetter) schema.Sc
 		return diags
 	}
 }
+
+// StringIsValidDuration returns a SchemaValidateDiagFunc which validates that a string is a valid Go duration (e.g. "30s", "5m").
+func StringIsValidDuration(opts ...ValidationOptionSetter) schema.SchemaValidateDiagFunc {
+	options := NewValidationOptions().
+		setSeverity(diag.Error).
+		setSummary("Invalid duration").
+		setDetailFormat("%q is not a valid duration: %s").
+		apply(opts)
+
+	return func(v any, p cty.Path) diag.Diagnostics {
+		value := v.(string)
+		var diags diag.Diagnostics
+		if _, err := time.ParseDuration(value); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: options.severity,
+				Summary:  options.summary,
+				Detail:   fmt.Sprintf(options.detailFormat, value, err),
+			})
+		}
+		return diags
+	}
+}
@@ -0,0 +1,111 @@
+package schemautil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// AddWriteOnlyAttribute adds to s, the schema of the block at path (e.g. "spec.0."), a write-only alternative to its sensitive attribute name:
+//   - <name>_wo, which is sent to Codefresh but never stored in plan or state. The values of a map attribute are given as a JSON object.
+//   - <name>_wo_version, which must be set with <name>_wo and changed to send a new value, as changes to <name>_wo alone are not planned.
+//
+// A required attribute becomes optional, as exactly one of the attribute and its write-only alternative must be set.
+func AddWriteOnlyAttribute(s map[string]*schema.Schema, path, name string) {
+	attribute := s[name]
+	writeOnlyName := name + "_wo"
+	versionName := writeOnlyName + "_version"
+
+	if attribute.Required {
+		attribute.Required = false
+		attribute.Optional = true
+		attribute.ExactlyOneOf = []string{path + name, path + writeOnlyName}
+	} else {
+		attribute.ConflictsWith = append(attribute.ConflictsWith, path+writeOnlyName)
+	}
+
+	writeOnly := &schema.Schema{
+		Description:      fmt.Sprintf("Write-only alternative to `%s`, sent to Codefresh but never stored in state (requires Terraform 1.11 or later). `%s` must be set with it, and changed to send a new value.", name, versionName),
+		Type:             schema.TypeString,
+		Optional:         true,
+		WriteOnly:        true,
+		Sensitive:        true,
+		RequiredWith:     []string{path + versionName},
+		ValidateDiagFunc: attribute.ValidateDiagFunc,
+	}
+	if attribute.Type == schema.TypeMap {
+		writeOnly.Description = fmt.Sprintf("Write-only alternative to `%s`, as a JSON object of strings (e.g. with `jsonencode()`), sent to Codefresh but never stored in state (requires Terraform 1.11 or later). `%s` must be set with it, and changed to send a new value.", name, versionName)
+		writeOnly.ValidateDiagFunc = StringIsJsonObjectOfStrings()
+	}
+	s[writeOnlyName] = writeOnly
+
+	s[versionName] = &schema.Schema{
+		Description:  fmt.Sprintf("The version of `%s`. Changing it sends the current value of `%s` to Codefresh.", writeOnlyName, writeOnlyName),
+		Type:         schema.TypeInt,
+		Optional:     true,
+		ValidateFunc: validation.IntAtLeast(1),
+		RequiredWith: []string{path + writeOnlyName},
+	}
+}
+
+// UsesWriteOnly returns true if the attribute at path is set with its write-only alternative.
+// It can be used when reading a resource, when the write-only value itself is not available.
+func UsesWriteOnly(d *schema.ResourceData, path string) bool {
+	return d.Get(path+"_wo_version").(int) > 0
+}
+
+// SecretToKeep returns value, the sensitive attribute at path read from Codefresh, if it is to be kept in state, or nil otherwise.
+// It is only kept if the state already holds the attribute: not if it is set with its write-only alternative, nor when the resource
+// is imported, as the configuration may set it with its write-only alternative. An imported secret is then sent again by the next apply.
+func SecretToKeep(d *schema.ResourceData, path string, value interface{}) interface{} {
+	if _, ok := d.GetOk(path); !ok {
+		return nil
+	}
+	return value
+}
+
+// GetStringOrWriteOnly returns the value of the string attribute at path, or the one of its write-only alternative if it is set
+func GetStringOrWriteOnly(d *schema.ResourceData, path string) string {
+	if value := getWriteOnly(d, path+"_wo"); value != "" {
+		return value
+	}
+	return d.Get(path).(string)
+}
+
+// GetMapOrWriteOnly returns the value of the map attribute at path, or the one of its write-only alternative if it is set
+func GetMapOrWriteOnly(d *schema.ResourceData, path string) map[string]interface{} {
+	if value := getWriteOnly(d, path+"_wo"); value != "" {
+		// the value is validated when planned
+		var m map[string]interface{}
+		_ = json.Unmarshal([]byte(value), &m)
+		return m
+	}
+	return d.Get(path).(map[string]interface{})
+}
+
+// getWriteOnly returns the value of the write-only attribute at path in the configuration, or "" if it is not set
+func getWriteOnly(d *schema.ResourceData, path string) string {
+	value, diags := d.GetRawConfigAt(attributePath(path))
+	if diags.HasError() || !value.IsKnown() || value.IsNull() || !value.Type().Equals(cty.String) {
+		return ""
+	}
+	return value.AsString()
+}
+
+// attributePath returns the cty.Path of an attribute path such as "spec.0.data"
+func attributePath(path string) cty.Path {
+	var res cty.Path
+	for _, step := range strings.Split(path, ".") {
+		if index, err := strconv.Atoi(step); err == nil {
+			res = res.IndexInt(index)
+		} else {
+			res = res.GetAttr(step)
+		}
+	}
+	return res
+}
@@ -0,0 +1,211 @@
+package schemautil
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
+	"github.com/iancoleman/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// SummarizePipelineYamlChanges returns the changes between two pipeline YAML strings, one per line:
+// the steps added, removed, modified or reordered, the stages added, removed or reordered, and the other top-level fields modified.
+// Changes that do not alter the meaning of the YAML, such as comments, formatting or the order of the fields, are not reported.
+func SummarizePipelineYamlChanges(oldYaml, newYaml string) ([]string, error) {
+	changes := []string{}
+
+	oldSteps, err := extractOrderedMapping(oldYaml, ".steps")
+	if err != nil {
+		return nil, err
+	}
+	newSteps, err := extractOrderedMapping(newYaml, ".steps")
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, summarizeOrderedMappingChanges("steps", oldSteps, newSteps)...)
+
+	oldStages, err := extractStringList(oldYaml, ".stages")
+	if err != nil {
+		return nil, err
+	}
+	newStages, err := extractStringList(newYaml, ".stages")
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, summarizeListChanges("stages", oldStages, newStages)...)
+
+	var oldFields, newFields map[string]interface{}
+	if err := yaml.Unmarshal([]byte(oldYaml), &oldFields); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal([]byte(newYaml), &newFields); err != nil {
+		return nil, err
+	}
+	modified := []string{}
+	for _, key := range unionKeys(oldFields, newFields) {
+		if key != "steps" && key != "stages" && !reflect.DeepEqual(oldFields[key], newFields[key]) {
+			modified = append(modified, key)
+		}
+	}
+	if len(modified) > 0 {
+		changes = append(changes, fmt.Sprintf("fields modified: %s", strings.Join(modified, ", ")))
+	}
+
+	return changes, nil
+}
+
+// SummarizeMapChanges returns the keys added, removed and modified between two maps, one line per kind of change
+func SummarizeMapChanges(name string, oldMap, newMap map[string]interface{}) []string {
+	var added, removed, modified []string
+	for _, key := range unionKeys(oldMap, newMap) {
+		oldValue, inOld := oldMap[key]
+		newValue, inNew := newMap[key]
+		switch {
+		case !inOld:
+			added = append(added, key)
+		case !inNew:
+			removed = append(removed, key)
+		case !reflect.DeepEqual(oldValue, newValue):
+			modified = append(modified, key)
+		}
+	}
+	return formatChanges(name, added, removed, modified)
+}
+
+// extractOrderedMapping returns the fields of the mapping at expression, in their order
+func extractOrderedMapping(yamlString string, expression string) (*orderedmap.OrderedMap, error) {
+	mapping := orderedmap.New()
+	if strings.TrimSpace(yamlString) == "" {
+		return mapping, nil
+	}
+
+	mappingJson, err := datautil.Yq(expression, yamlString, datautil.YQ_OUTPUT_FORMAT_JSON)
+	if err != nil {
+		return nil, fmt.Errorf("error while extracting '%s' from YAML string: %v", expression, err)
+	} else if mappingJson == "" {
+		return mapping, nil
+	}
+
+	err = json.Unmarshal([]byte(mappingJson), mapping)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is not a mapping: %v", expression, err)
+	}
+	return mapping, nil
+}
+
+// extractStringList returns the items of the list at expression
+func extractStringList(yamlString string, expression string) ([]string, error) {
+	list := []string{}
+	if strings.TrimSpace(yamlString) == "" {
+		return list, nil
+	}
+
+	listJson, err := datautil.Yq(expression, yamlString, datautil.YQ_OUTPUT_FORMAT_JSON)
+	if err != nil {
+		return nil, fmt.Errorf("error while extracting '%s' from YAML string: %v", expression, err)
+	} else if listJson == "" {
+		return list, nil
+	}
+
+	err = json.Unmarshal([]byte(listJson), &list)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is not a list of strings: %v", expression, err)
+	}
+	return list, nil
+}
+
+func summarizeOrderedMappingChanges(name string, oldMapping, newMapping *orderedmap.OrderedMap) []string {
+	var added, removed, modified []string
+	var kept []string
+	for _, key := range newMapping.Keys() {
+		if _, ok := oldMapping.Get(key); !ok {
+			added = append(added, key)
+		}
+	}
+	for _, key := range oldMapping.Keys() {
+		oldValue, _ := oldMapping.Get(key)
+		newValue, ok := newMapping.Get(key)
+		if !ok {
+			removed = append(removed, key)
+			continue
+		}
+		kept = append(kept, key)
+		if !equivalentValues(oldValue, newValue) {
+			modified = append(modified, key)
+		}
+	}
+
+	changes := formatChanges(name, added, removed, modified)
+	if newKept := slices.DeleteFunc(slices.Clone(newMapping.Keys()), func(key string) bool { return slices.Contains(added, key) }); !slices.Equal(kept, newKept) {
+		changes = append(changes, fmt.Sprintf("%s reordered", name))
+	}
+	return changes
+}
+
+func summarizeListChanges(name string, oldList, newList []string) []string {
+	var added, removed, kept []string
+	for _, item := range newList {
+		if !slices.Contains(oldList, item) {
+			added = append(added, item)
+		}
+	}
+	for _, item := range oldList {
+		if !slices.Contains(newList, item) {
+			removed = append(removed, item)
+		} else {
+			kept = append(kept, item)
+		}
+	}
+
+	changes := formatChanges(name, added, removed, nil)
+	if newKept := slices.DeleteFunc(slices.Clone(newList), func(item string) bool { return slices.Contains(added, item) }); !slices.Equal(kept, newKept) {
+		changes = append(changes, fmt.Sprintf("%s reordered", name))
+	}
+	return changes
+}
+
+// equivalentValues compares two values through their normalized YAML, so that the order of their fields does not matter
+func equivalentValues(oldValue, newValue interface{}) bool {
+	oldJson, oldErr := json.Marshal(oldValue)
+	newJson, newErr := json.Marshal(newValue)
+	if oldErr != nil || newErr != nil {
+		return false
+	}
+	oldNormalized, oldErr := NormalizeYamlString(string(oldJson))
+	newNormalized, newErr := NormalizeYamlString(string(newJson))
+	if oldErr != nil || newErr != nil {
+		return string(oldJson) == string(newJson)
+	}
+	return oldNormalized == newNormalized
+}
+
+func formatChanges(name string, added, removed, modified []string) []string {
+	changes := []string{}
+	for _, change := range []struct {
+		kind string
+		keys []string
+	}{{"added", added}, {"removed", removed}, {"modified", modified}} {
+		if len(change.keys) > 0 {
+			changes = append(changes, fmt.Sprintf("%s %s: %s", name, change.kind, strings.Join(change.keys, ", ")))
+		}
+	}
+	return changes
+}
+
+func unionKeys(maps ...map[string]interface{}) []string {
+	keys := []string{}
+	for _, m := range maps {
+		for key := range m {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
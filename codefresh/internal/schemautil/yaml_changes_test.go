@@ -0,0 +1,109 @@
+package schemautil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSummarizePipelineYamlChanges(t *testing.T) {
+	oldYaml := `version: "1.0"
+mode: sequential
+stages:
+  - build
+  - test
+steps:
+  build:
+    stage: build
+    type: build
+    image_name: org/app
+  unit_tests:
+    stage: test
+    image: node:20
+    commands:
+      - npm test
+  lint:
+    stage: test
+    image: node:20
+    commands:
+      - npm run lint
+`
+
+	testCases := map[string]struct {
+		newYaml  string
+		expected []string
+	}{
+		"formatting and field order": {
+			newYaml: `# the pipeline of the app
+version: "1.0"
+mode: sequential
+stages: [build, test]
+steps:
+  build: {type: build, image_name: org/app, stage: build}
+  unit_tests:
+    stage: test
+    image: node:20
+    commands: [npm test]
+  lint:
+    stage: test
+    image: node:20
+    commands: [npm run lint]
+`,
+			expected: []string{},
+		},
+		"steps, stages and fields": {
+			newYaml: `version: "1.0"
+mode: parallel
+stages:
+  - test
+  - build
+  - deploy
+steps:
+  lint:
+    stage: test
+    image: node:20
+    commands:
+      - npm run lint
+  build:
+    stage: build
+    type: build
+    image_name: org/app
+    tag: latest
+  deploy:
+    stage: deploy
+    type: helm
+`,
+			expected: []string{
+				"steps added: deploy",
+				"steps removed: unit_tests",
+				"steps modified: build",
+				"steps reordered",
+				"stages added: deploy",
+				"stages reordered",
+				"fields modified: mode",
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			changes, err := SummarizePipelineYamlChanges(oldYaml, testCase.newYaml)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(changes, testCase.expected) {
+				t.Fatalf("expected changes %q, got %q", testCase.expected, changes)
+			}
+		})
+	}
+}
+
+func TestSummarizeMapChanges(t *testing.T) {
+	changes := SummarizeMapChanges("variables",
+		map[string]interface{}{"KEPT": "1", "CHANGED": "1", "REMOVED": "1"},
+		map[string]interface{}{"KEPT": "1", "CHANGED": "2", "ADDED": "1"},
+	)
+	expected := []string{"variables added: ADDED", "variables removed: REMOVED", "variables modified: CHANGED"}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("expected changes %q, got %q", expected, changes)
+	}
+}
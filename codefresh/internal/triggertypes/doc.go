@@ -0,0 +1,2 @@
+// Package triggertypes is the catalog of the trigger events supported by Codefresh, shared by the trigger resources and data sources.
+package triggertypes
@@ -0,0 +1,63 @@
+package triggertypes
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// EventURIValidator returns a SchemaValidateDiagFunc which validates that a string is the URI of a Hermes event,
+// and warns about a type and kind that are not known.
+func EventURIValidator() schema.SchemaValidateDiagFunc {
+	return func(v any, p cty.Path) diag.Diagnostics {
+		if err := ValidateEventURI(v.(string)); err != nil {
+			return diag.Diagnostics{{
+				Severity:      diag.Error,
+				Summary:       "Invalid trigger event",
+				Detail:        fmt.Sprintf("%s, see the codefresh_trigger_types data source for the supported events.", err),
+				AttributePath: p,
+			}}
+		}
+		if !IsKnownEventURI(v.(string)) {
+			return diag.Diagnostics{{
+				Severity:      diag.Warning,
+				Summary:       "Unknown trigger event type",
+				Detail:        fmt.Sprintf("The type and kind of the event %q are not known, see the codefresh_trigger_types data source for the supported events.", v),
+				AttributePath: p,
+			}}
+		}
+		return nil
+	}
+}
+
+// UnknownTypeWarning returns a warning if eventType and kind are not a known type and kind of Hermes trigger events.
+// Unknown types and kinds are not rejected, as Codefresh may support events the provider does not know about.
+func UnknownTypeWarning(eventType, kind string, p cty.Path) diag.Diagnostics {
+	if _, ok := Find(eventType, kind); ok {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity:      diag.Warning,
+		Summary:       "Unknown trigger event type",
+		Detail:        fmt.Sprintf("The type %q and kind %q are not known, see the codefresh_trigger_types data source for the supported ones.", eventType, kind),
+		AttributePath: p,
+	}}
+}
+
+// GitEventValidator returns a SchemaValidateDiagFunc which warns about a string that is not a known event of git triggers.
+// Unknown events are not rejected, as Codefresh may support events the provider does not know about.
+func GitEventValidator() schema.SchemaValidateDiagFunc {
+	return func(v any, p cty.Path) diag.Diagnostics {
+		if !IsGitEvent(v.(string)) {
+			return diag.Diagnostics{{
+				Severity:      diag.Warning,
+				Summary:       "Unknown git trigger event",
+				Detail:        fmt.Sprintf("%q is not a known git trigger event, see the git_events of the codefresh_trigger_types data source.", v),
+				AttributePath: p,
+			}}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,121 @@
+package triggertypes
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TriggerType is a type and kind of Hermes trigger event.
+//
+// Matches the types of https://github.com/codefresh-io/hermes/blob/6d75b347cb8ff471ce970a766b2285788e5e19fe/pkg/backend/dev_compose_types.json,
+// must be updated accordingly.
+type TriggerType struct {
+	Type        string
+	Kind        string
+	Description string
+	// Values are the values identifying an event, in the order they appear in its URI
+	Values []string
+}
+
+// URITemplate returns the template of the URI of the events of the trigger type.
+// Codefresh may append a suffix, such as the account, to the URI of the events it creates.
+func (t TriggerType) URITemplate() string {
+	parts := []string{t.Type, t.Kind}
+	for _, value := range t.Values {
+		parts = append(parts, "{{"+value+"}}")
+	}
+	return strings.Join(parts, ":")
+}
+
+var hermesTypes = []TriggerType{
+	{Type: "cron", Kind: "codefresh", Description: "Cron timer", Values: []string{"expression", "message"}},
+	{Type: "registry", Kind: "dockerhub", Description: "Docker Hub image push", Values: []string{"namespace", "name", "action"}},
+	{Type: "registry", Kind: "quay", Description: "Quay image push", Values: []string{"namespace", "name", "action"}},
+	{Type: "registry", Kind: "azure", Description: "Azure Container Registry image push", Values: []string{"name", "repository", "action"}},
+	{Type: "registry", Kind: "jfrog", Description: "JFrog Artifactory image push", Values: []string{"repository", "image", "action"}},
+	{Type: "registry", Kind: "ecr", Description: "Amazon ECR image push", Values: []string{"region", "repository", "action"}},
+	{Type: "helm", Kind: "jfrog", Description: "JFrog Artifactory Helm chart push", Values: []string{"repository", "chart", "action"}},
+	{Type: "custom", Kind: "codefresh", Description: "Custom event sent to the webhook", Values: []string{"name"}},
+}
+
+// gitEvents are the events of git triggers, see https://codefresh.io/docs/docs/pipelines/triggers/git-triggers/
+var gitEvents = []string{
+	"push.heads",
+	"push.tags",
+	"pullrequest.opened",
+	"pullrequest.reopened",
+	"pullrequest.edited",
+	"pullrequest.closed",
+	"pullrequest.merged",
+	"pullrequest.unmerged-closed",
+	"pullrequest.assigned",
+	"pullrequest.unassigned",
+	"pullrequest.review-requested",
+	"pullrequest.review-request-removed",
+	"pullrequest.labeled",
+	"pullrequest.unlabeled",
+	"pullrequest.synchronize",
+	"pullrequest.commentAdded",
+	"pullrequest.commentAddedRestricted",
+	"release.published",
+	"release.unpublished",
+	"release.created",
+	"release.deleted",
+	"release.prereleased",
+	"release.released",
+	"release.edited",
+}
+
+// HermesTypes returns the supported types and kinds of Hermes trigger events.
+func HermesTypes() []TriggerType {
+	return slices.Clone(hermesTypes)
+}
+
+// GitEvents returns the supported events of git triggers.
+func GitEvents() []string {
+	return slices.Clone(gitEvents)
+}
+
+// Find returns the trigger type of the given type and kind.
+func Find(eventType, kind string) (TriggerType, bool) {
+	for _, t := range hermesTypes {
+		if t.Type == eventType && t.Kind == kind {
+			return t, true
+		}
+	}
+	return TriggerType{}, false
+}
+
+// ValidateEventURI returns an error if uri is not in the format TYPE:KIND:VALUES, or if its values do not match those of its trigger type
+// when its type and kind are known. Unknown types and kinds are not rejected, as Codefresh may support events the provider does not know about.
+func ValidateEventURI(uri string) error {
+	parts := strings.Split(uri, ":")
+	if len(parts) < 3 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("the event %q must be in the format TYPE:KIND:VALUES", uri)
+	}
+	t, ok := Find(parts[0], parts[1])
+	if !ok {
+		return nil
+	}
+	// values may contain colons, e.g. cron messages, so only the minimum number of parts is checked
+	if len(parts)-2 < len(t.Values) || slices.Contains(parts[2:2+len(t.Values)], "") {
+		return fmt.Errorf("the event %q must match %s", uri, t.URITemplate())
+	}
+	return nil
+}
+
+// IsKnownEventURI returns whether uri starts with a known type and kind.
+func IsKnownEventURI(uri string) bool {
+	parts := strings.SplitN(uri, ":", 3)
+	if len(parts) < 2 {
+		return false
+	}
+	_, ok := Find(parts[0], parts[1])
+	return ok
+}
+
+// IsGitEvent returns whether event is a supported event of git triggers.
+func IsGitEvent(event string) bool {
+	return slices.Contains(gitEvents, event)
+}
@@ -0,0 +1,47 @@
+package triggertypes
+
+import (
+	"testing"
+)
+
+func TestValidateEventURI(t *testing.T) {
+	for uri, valid := range map[string]bool{
+		"registry:dockerhub:codefresh:fortune:push:cb1e73c5215b": true,
+		"registry:quay:codefresh:fortune:push":                   true,
+		"cron:codefresh:0 0 * * * *:hello: world:01234567":       true,
+		"custom:codefresh:release":                               true,
+		"registry:dockerhub:codefresh:fortune":                   false,
+		"registry:dockerhub:codefresh::push":                     false,
+		"registry:gcr:codefresh:fortune:push":                    true,
+		"registry:dockerhub":                                     false,
+		":dockerhub:codefresh":                                   false,
+		"dockerhub":                                              false,
+		"":                                                       false,
+	} {
+		if err := ValidateEventURI(uri); (err == nil) != valid {
+			t.Errorf("unexpected validation of %q: %v", uri, err)
+		}
+	}
+}
+
+func TestIsKnownEventURI(t *testing.T) {
+	for uri, known := range map[string]bool{
+		"registry:dockerhub:codefresh:fortune:push": true,
+		"registry:gcr:codefresh:fortune:push":       false,
+		"dockerhub":                                 false,
+	} {
+		if IsKnownEventURI(uri) != known {
+			t.Errorf("expected IsKnownEventURI(%q) to be %t", uri, known)
+		}
+	}
+}
+
+func TestURITemplate(t *testing.T) {
+	triggerType, ok := Find("registry", "dockerhub")
+	if !ok {
+		t.Fatal("expected Docker Hub push events to be supported")
+	}
+	if template := triggerType.URITemplate(); template != "registry:dockerhub:{{namespace}}:{{name}}:{{action}}" {
+		t.Fatalf("unexpected template %q", template)
+	}
+}
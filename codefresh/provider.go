@@ -1,43 +1,174 @@
 package codefresh
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/cfconfig"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"os"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// tokenCommandTimeout bounds the execution of the token_command
+const tokenCommandTimeout = time.Minute
+
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"api_url": {
-				Type:     schema.TypeString,
-				Optional: true,
-				DefaultFunc: func() (interface{}, error) {
-					if url := os.Getenv(ENV_CODEFRESH_API_URL); url != "" {
-						return url, nil
-					}
-					return DEFAULT_CODEFRESH_API_URL, nil
-				},
-				Description: fmt.Sprintf("The Codefresh API URL. Defaults to `%s`. Can also be set using the `%s` environment variable.", DEFAULT_CODEFRESH_API_URL, ENV_CODEFRESH_API_URL),
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: fmt.Sprintf("The Codefresh API URL, `%s` by default. Can also be set using the `%s` environment variable. When the token is read from the Codefresh CLI config, the URL of its context is used instead: this argument must then not be set, and the environment variable is ignored.", DEFAULT_CODEFRESH_API_URL, ENV_CODEFRESH_API_URL),
 			},
 			"api_url_v2": {
-				Type:     schema.TypeString,
-				Optional: true,
-				DefaultFunc: func() (interface{}, error) {
-					if url := os.Getenv(ENV_CODEFRESH_API2_URL); url != "" {
-						return url, nil
-					}
-					return DEFAULT_CODEFRESH_API2_URL, nil
-				},
-				Description: fmt.Sprintf("The Codefresh gitops API URL. Defaults to `%s`. Can also be set using the `%s` environment variable.", DEFAULT_CODEFRESH_API2_URL, ENV_CODEFRESH_API2_URL),
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: fmt.Sprintf("The Codefresh gitops API URL, `%s` by default. Can also be set using the `%s` environment variable. When the token is read from the Codefresh CLI config, the URL of its context is used instead: this argument must then not be set, and the environment variable is ignored.", DEFAULT_CODEFRESH_API2_URL, ENV_CODEFRESH_API2_URL),
 			},
 			"token": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: fmt.Sprintf("The Codefresh API token. Can also be set using the `%s` environment variable.", ENV_CODEFRESH_API_KEY),
 			},
+			"account_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"account_name"},
+				Description:   "The ID of the account to manage, when the token belongs to a system admin. The provider then acts in this account on behalf of its first admin, using a token obtained with the admin login-as-user flow. Resources requiring system admin rights, such as `codefresh_account` or `codefresh_user`, can't be managed with such a provider.",
+			},
+			"account_name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"account_id"},
+				Description:   "The name of the account to manage, when the token belongs to a system admin. See `account_id`.",
+			},
+			"allowed_account_ids": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				ConflictsWith: []string{"forbidden_account_ids"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The IDs of the accounts the provider is allowed to manage. The provider fails to configure, before any resource is read or changed, if the account of the token is not one of them.",
+			},
+			"forbidden_account_ids": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				ConflictsWith: []string{"allowed_account_ids"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The IDs of the accounts the provider must not manage. The provider fails to configure, before any resource is read or changed, if the account of the token is one of them.",
+			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Refuse every change: resources can be read, planned and imported, and data sources keep working, but any create, update or delete fails. The provider never sends a Codefresh API request that could change data, whatever the permissions of the token. Useful to run `terraform plan` for audits and drift detection.",
+			},
+			"token_command": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MinItems:      1,
+				ConflictsWith: []string{"token"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: fmt.Sprintf("A command, given as the executable followed by its arguments, printing the Codefresh API token on its standard output. It is run once when the provider is configured and must complete within `%s`. Useful to fetch short-lived tokens from a secret manager.", tokenCommandTimeout),
+			},
+			"config_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(ENV_CODEFRESH_CONFIG_PATH, ""),
+				Description: fmt.Sprintf("The path of the Codefresh CLI config file to read the API token and URLs from, when no token is set by the provider arguments or environment variables. The token and URLs are always taken together, either from the config context or from the provider arguments and environment variables. Defaults to `~/%s`, which is only read if it exists. Can also be set using the `%s` environment variable.", cfconfig.DefaultFileName, ENV_CODEFRESH_CONFIG_PATH),
+			},
+			"config_context": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: fmt.Sprintf("The name of the context of the Codefresh CLI config file to use. Defaults to the `current-context` of the file. Can also be set using the `%s` environment variable. Must not be set along with `token`, `token_command` or the `%s` environment variable, whose token would be used instead of the one of the context.", ENV_CODEFRESH_CONFIG_CONTEXT, ENV_CODEFRESH_API_KEY),
+			},
+			"max_requests_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      cfclient.DefaultMaxRequestsPerSecond,
+				ValidateFunc: validation.FloatAtLeast(0),
+				Description:  fmt.Sprintf("The maximum number of requests per second sent to each of the Codefresh API and the Codefresh gitops API, shared by all the operations run in parallel by Terraform. Retries count against this limit. Set to `0` to disable rate limiting. Defaults to `%d`.", cfclient.DefaultMaxRequestsPerSecond),
+			},
+			"ca_cert_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The path of a PEM bundle of certificate authorities to trust, in addition to the system ones, when connecting to the Codefresh APIs. Useful for on-premises installations using an internal CA.",
+			},
+			"ca_cert_pem": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A PEM bundle of certificate authorities to trust, in addition to the system ones, when connecting to the Codefresh APIs.",
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Skip the verification of the certificate of the Codefresh APIs. Do not use in production.",
+			},
+			"proxy_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsURLWithScheme([]string{"http", "https", "socks5"}),
+				Description:  "The URL of the proxy used to connect to the Codefresh APIs. When not set, the `HTTP_PROXY`, `HTTPS_PROXY` and `NO_PROXY` environment variables are used.",
+			},
+			"client_cert_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"client_cert_pem"},
+				RequiredWith:  []string{"client_key_file"},
+				Description:   "The path of the PEM encoded client certificate used for mutual TLS authentication with the Codefresh APIs.",
+			},
+			"client_key_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"client_key_pem"},
+				RequiredWith:  []string{"client_cert_file"},
+				Description:   "The path of the PEM encoded private key of `client_cert_file`.",
+			},
+			"client_cert_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"client_cert_file"},
+				RequiredWith:  []string{"client_key_pem"},
+				Description:   "The PEM encoded client certificate used for mutual TLS authentication with the Codefresh APIs.",
+			},
+			"client_key_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"client_key_file"},
+				RequiredWith:  []string{"client_cert_pem"},
+				Description:   "The PEM encoded private key of `client_cert_pem`.",
+			},
+			"retry_max_attempts": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      cfclient.DefaultRetryMaxAttempts,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  fmt.Sprintf("The maximum number of attempts for a single Codefresh API call failing with a transient error (429 or 5xx). Only idempotent requests are retried, except on 429 responses with a `Retry-After` header. Set to `1` to disable retries. Defaults to `%d`.", cfclient.DefaultRetryMaxAttempts),
+			},
+			"retry_max_backoff": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          cfclient.DefaultRetryMaxBackoff.String(),
+				ValidateDiagFunc: schemautil.StringIsValidDuration(),
+				Description:      fmt.Sprintf("The maximum wait between two attempts of a Codefresh API call, including waits requested by the `Retry-After` header. Defaults to `%s`.", cfclient.DefaultRetryMaxBackoff),
+			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"codefresh_account":                 dataSourceAccount(),
@@ -51,10 +182,12 @@ func Provider() *schema.Provider {
 			"codefresh_registry":                dataSourceRegistry(),
 			"codefresh_pipelines":               dataSourcePipelines(),
 			"codefresh_account_idp":             dataSourceAccountIdp(),
+			"codefresh_builds":                  dataSourceBuilds(),
 			"codefresh_project":                 dataSourceProject(),
 			"codefresh_account_gitops_settings": dataSourceAccountGitopsSettings(),
 			"codefresh_current_account_user":    dataSourceCurrentAccountUser(),
 			"codefresh_service_account":         dataSourceServiceAccount(),
+			"codefresh_trigger_types":           dataSourceTriggerTypes(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"codefresh_account":                  resourceAccount(),
@@ -67,8 +200,12 @@ func Provider() *schema.Provider {
 			"codefresh_permission":               resourcePermission(),
 			"codefresh_pipeline":                 resourcePipeline(),
 			"codefresh_pipeline_cron_trigger":    resourcePipelineCronTrigger(),
+			"codefresh_pipeline_git_trigger":     resourcePipelineGitTrigger(),
+			"codefresh_pipeline_event_trigger":   resourcePipelineEventTrigger(),
+			"codefresh_pipeline_run":             resourcePipelineRun(),
 			"codefresh_project":                  resourceProject(),
 			"codefresh_step_types":               resourceStepTypes(),
+			"codefresh_trigger_event":            resourceTriggerEvent(),
 			"codefresh_user":                     resourceUser(),
 			"codefresh_team":                     resourceTeam(),
 			"codefresh_abac_rules":               resourceGitopsAbacRule(),
@@ -77,18 +214,216 @@ func Provider() *schema.Provider {
 			"codefresh_account_gitops_settings":  resourceAccountGitopsSettings(),
 			"codefresh_service_account":          resourceServiceAccount(),
 		},
-		ConfigureFunc: configureProvider,
+		ConfigureContextFunc: configureProvider,
+	}
+
+	for _, resource := range provider.ResourcesMap {
+		withReadOnlyGuard(resource)
 	}
+
+	return provider
+}
+
+// withReadOnlyGuard makes the create, update and delete operations of resource fail when the provider is read-only,
+// whether or not they would send a request to the Codefresh API
+func withReadOnlyGuard(resource *schema.Resource) {
+	guard := func(operation string, f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		if f == nil {
+			return nil
+		}
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			if client, ok := meta.(*cfclient.Client); ok && client.ReadOnly {
+				return diag.Diagnostics{{
+					Severity: diag.Error,
+					Summary:  fmt.Sprintf("Cannot %s resource in read-only mode", operation),
+					Detail:   "The provider is configured with read_only = true, which refuses every change.",
+				}}
+			}
+			return f(ctx, d, meta)
+		}
+	}
+
+	resource.CreateContext = guard("create", resource.CreateContext)
+	resource.UpdateContext = guard("update", resource.UpdateContext)
+	resource.DeleteContext = guard("delete", resource.DeleteContext)
 }
 
-func configureProvider(d *schema.ResourceData) (interface{}, error) {
+func configureProvider(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 
+	// api_url, api_url_v2 and config_context are only those set in the provider block, their environment variables are read below
 	apiURL := d.Get("api_url").(string)
 	apiURLV2 := d.Get("api_url_v2").(string)
+	configContext := d.Get("config_context").(string)
 	token := d.Get("token").(string)
+	if tokenCommand := datautil.ConvertStringArr(d.Get("token_command").([]interface{})); len(tokenCommand) > 0 {
+		var err error
+		if token, err = runTokenCommand(ctx, tokenCommand); err != nil {
+			return nil, diag.FromErr(err)
+		}
+	}
 	if token == "" {
 		token = os.Getenv(ENV_CODEFRESH_API_KEY)
 	}
 
-	return cfclient.NewClient(apiURL, apiURLV2, token, ""), nil
+	var diags diag.Diagnostics
+
+	// The token and the API URLs are taken from the same source, so that a token is only sent to the URLs configured along with it:
+	// the provider arguments and environment variables when they set a token, the Codefresh CLI config context otherwise
+	fromCLIConfig := false
+	if token != "" {
+		if configContext != "" {
+			return nil, diag.Errorf("config_context cannot be set along with a token set by token, token_command or the %s environment variable, remove one of them", ENV_CODEFRESH_API_KEY)
+		}
+		if envContext := os.Getenv(ENV_CODEFRESH_CONFIG_CONTEXT); envContext != "" {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Codefresh CLI config context ignored",
+				Detail:   fmt.Sprintf("The %s environment variable selects the context %s, but the token set by token, token_command or the %s environment variable is used instead of the token of the context.", ENV_CODEFRESH_CONFIG_CONTEXT, envContext, ENV_CODEFRESH_API_KEY),
+			})
+		}
+	} else {
+		if configContext == "" {
+			configContext = os.Getenv(ENV_CODEFRESH_CONFIG_CONTEXT)
+		}
+		cliContext, err := loadCLIConfigContext(d.Get("config_path").(string), configContext)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		if cliContext != nil {
+			if apiURL != "" || apiURLV2 != "" {
+				return nil, diag.Errorf("api_url and api_url_v2 cannot be set when the token is read from the Codefresh CLI config, set token too or remove them to use the URLs of the config context")
+			}
+			fromCLIConfig = true
+			token = cliContext.Token
+			apiURL = cliContext.APIURL()
+			apiURLV2 = cliContext.APIURLV2()
+		}
+	}
+	if !fromCLIConfig {
+		if apiURL == "" {
+			apiURL = os.Getenv(ENV_CODEFRESH_API_URL)
+		}
+		if apiURLV2 == "" {
+			apiURLV2 = os.Getenv(ENV_CODEFRESH_API2_URL)
+		}
+	}
+	if apiURL == "" {
+		apiURL = DEFAULT_CODEFRESH_API_URL
+	}
+	if apiURLV2 == "" {
+		apiURLV2 = DEFAULT_CODEFRESH_API2_URL
+	}
+
+	client := cfclient.NewClient(apiURL, apiURLV2, token, "")
+
+	// the REST and GraphQL APIs share the same transport, and so the same connection pool
+	transport, err := cfclient.NewTransport(cfclient.TransportOptions{
+		CACertFile:         d.Get("ca_cert_file").(string),
+		CACertPEM:          d.Get("ca_cert_pem").(string),
+		InsecureSkipVerify: d.Get("insecure_skip_verify").(bool),
+		ProxyURL:           d.Get("proxy_url").(string),
+		ClientCertFile:     d.Get("client_cert_file").(string),
+		ClientKeyFile:      d.Get("client_key_file").(string),
+		ClientCertPEM:      d.Get("client_cert_pem").(string),
+		ClientKeyPEM:       d.Get("client_key_pem").(string),
+	})
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	client.Client.Transport = transport
+
+	client.RetryMaxAttempts = d.Get("retry_max_attempts").(int)
+	retryMaxBackoff, err := time.ParseDuration(d.Get("retry_max_backoff").(string))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	client.RetryMaxBackoff = retryMaxBackoff
+	client.MaxRequestsPerSecond = d.Get("max_requests_per_second").(float64)
+
+	accountID := d.Get("account_id").(string)
+	if accountName := d.Get("account_name").(string); accountName != "" {
+		account, err := client.GetAccountByName(ctx, accountName)
+		if err != nil {
+			return nil, diag.FromErr(fmt.Errorf("cannot find account %s to impersonate: %w", accountName, err))
+		}
+		accountID = account.ID
+	}
+	if accountID != "" {
+		client, err = client.ImpersonateAccount(ctx, accountID)
+		if err != nil {
+			return nil, diag.FromErr(fmt.Errorf("cannot impersonate account %s: %w", accountID, err))
+		}
+	}
+
+	client.ReadOnly = d.Get("read_only").(bool)
+
+	allowedAccountIDs := datautil.ConvertStringArr(d.Get("allowed_account_ids").(*schema.Set).List())
+	forbiddenAccountIDs := datautil.ConvertStringArr(d.Get("forbidden_account_ids").(*schema.Set).List())
+	if len(allowedAccountIDs) > 0 || len(forbiddenAccountIDs) > 0 {
+		if err := checkAccountID(ctx, client, allowedAccountIDs, forbiddenAccountIDs); err != nil {
+			return nil, diag.FromErr(err)
+		}
+	}
+
+	return client, diags
+}
+
+// checkAccountID makes sure the provider only manages the allowed accounts
+func checkAccountID(ctx context.Context, client *cfclient.Client, allowedAccountIDs []string, forbiddenAccountIDs []string) error {
+	currentAccount, err := client.GetCurrentAccount(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot get the current account to check it against allowed_account_ids and forbidden_account_ids: %w", err)
+	}
+
+	if len(allowedAccountIDs) > 0 && !slices.Contains(allowedAccountIDs, currentAccount.ID) {
+		return fmt.Errorf("account %s (%s) is not one of the allowed_account_ids %v, check the token used by the provider", currentAccount.Name, currentAccount.ID, allowedAccountIDs)
+	}
+	if slices.Contains(forbiddenAccountIDs, currentAccount.ID) {
+		return fmt.Errorf("account %s (%s) is one of the forbidden_account_ids, check the token used by the provider", currentAccount.Name, currentAccount.ID)
+	}
+	return nil
+}
+
+// loadCLIConfigContext returns the context of the Codefresh CLI config file to use, nil if the default file does not exist
+func loadCLIConfigContext(configPath string, contextName string) (*cfconfig.Context, error) {
+	explicitPath := configPath != ""
+	if !explicitPath {
+		var err error
+		if configPath, err = cfconfig.DefaultPath(); err != nil {
+			if contextName == "" {
+				return nil, nil
+			}
+			return nil, err
+		}
+	}
+
+	config, err := cfconfig.Load(configPath)
+	if err != nil {
+		if os.IsNotExist(err) && !explicitPath && contextName == "" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return config.Context(contextName)
+}
+
+// runTokenCommand runs the token_command and returns the token it printed
+func runTokenCommand(ctx context.Context, command []string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, tokenCommandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("token_command %s failed: %w: %s", command[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("token_command %s did not print a token", command[0])
+	}
+	return token, nil
 }
@@ -1,10 +1,25 @@
 package codefresh
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/cassette"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/fakeapi"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 var testAccProvider *schema.Provider
@@ -17,6 +32,19 @@ func init() {
 	}
 }
 
+// TestMain runs the sweepers when -sweep is given, and points the tests to an in-memory fake of the Codefresh API
+// when CODEFRESH_FAKE_API is set
+func TestMain(m *testing.M) {
+	if os.Getenv(ENV_CODEFRESH_FAKE_API) != "" {
+		// the server lives as long as the test binary, which resource.TestMain exits
+		server := fakeapi.NewServer()
+		os.Setenv(ENV_CODEFRESH_API_URL, server.APIURL())
+		os.Setenv(ENV_CODEFRESH_API2_URL, server.GraphQLURL())
+		os.Setenv(ENV_CODEFRESH_API_KEY, fakeapi.APIKey)
+	}
+	resource.TestMain(m)
+}
+
 func TestProvider(t *testing.T) {
 	if err := Provider().InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
@@ -28,3 +56,238 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatalf("%s must be set for acceptance tests", ENV_CODEFRESH_API_KEY)
 	}
 }
+
+func testConfigureProvider(t *testing.T, raw map[string]interface{}) *cfclient.Client {
+	for _, env := range []string{ENV_CODEFRESH_API_KEY, ENV_CODEFRESH_API_URL, ENV_CODEFRESH_API2_URL, ENV_CODEFRESH_CONFIG_PATH, ENV_CODEFRESH_CONFIG_CONTEXT} {
+		t.Setenv(env, "")
+	}
+	// make sure the ~/.cfconfig of the machine running the tests is not used
+	t.Setenv("HOME", t.TempDir())
+
+	meta, diags := configureProvider(context.Background(), schema.TestResourceDataRaw(t, Provider().Schema, raw))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	return meta.(*cfclient.Client)
+}
+
+// testCassetteClient returns a client replaying the interactions stored in test_data/cassettes/<name>.json.
+// When CODEFRESH_RECORD_CASSETTES is set, the interactions are instead recorded from the API configured by the environment
+// and the cassette is overwritten at the end of the test.
+func testCassetteClient(t *testing.T, name string) *cfclient.Client {
+	path := filepath.Join("..", "test_data", "cassettes", name+".json")
+	mode := cassette.Replay
+	client := cfclient.NewClient(DEFAULT_CODEFRESH_API_URL, DEFAULT_CODEFRESH_API2_URL, "", "")
+	if os.Getenv(ENV_CODEFRESH_RECORD) != "" {
+		testAccPreCheck(t)
+		mode = cassette.Record
+		client.Token = os.Getenv(ENV_CODEFRESH_API_KEY)
+		if apiURL := os.Getenv(ENV_CODEFRESH_API_URL); apiURL != "" {
+			client.Host = apiURL
+		}
+		if apiURLV2 := os.Getenv(ENV_CODEFRESH_API2_URL); apiURLV2 != "" {
+			client.HostV2 = apiURLV2
+		}
+	}
+	client.RetryMaxAttempts = 1
+
+	var recorder *cassette.Transport
+	client.WrapTransport(func(next http.RoundTripper) http.RoundTripper {
+		var err error
+		if recorder, err = cassette.New(path, mode, next); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return recorder
+	})
+	t.Cleanup(func() {
+		if err := recorder.Save(); err != nil {
+			t.Errorf("cannot save cassette: %v", err)
+		}
+	})
+	return client
+}
+
+// testApplyWithWriteOnly plans and applies the creation of r with config, and returns the resulting resource data.
+// rawConfig is the same configuration with the write-only values, which Terraform only sends in the raw configuration.
+func testApplyWithWriteOnly(t *testing.T, r *schema.Resource, config, rawConfig map[string]interface{}, client *cfclient.Client) *schema.ResourceData {
+	t.Helper()
+	ctx := context.Background()
+
+	diff, err := r.Diff(ctx, nil, terraform.NewResourceConfigRaw(config), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rawConfigJson, err := json.Marshal(rawConfig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff.RawConfig, err = ctyjson.Unmarshal(rawConfigJson, r.CoreConfigSchema().ImpliedType()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state, diags := r.Apply(ctx, nil, diff, client)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	return r.Data(state)
+}
+
+func TestConfigureProviderReadsCLIConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "cfconfig")
+	cliConfig := `contexts:
+  prod:
+    type: APIKey
+    url: https://g.codefresh.io
+    token: prod-token
+  on-prem:
+    type: APIKey
+    url: https://codefresh.example.com
+    token: on-prem-token
+current-context: prod
+`
+	if err := os.WriteFile(configPath, []byte(cliConfig), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client := testConfigureProvider(t, map[string]interface{}{"config_path": configPath, "config_context": "on-prem"})
+	if client.Token != "on-prem-token" || client.Host != "https://codefresh.example.com/api" || client.HostV2 != "https://codefresh.example.com/2.0/api/graphql" {
+		t.Fatalf("expected the on-prem context to be used, got %s %s", client.Host, client.HostV2)
+	}
+
+	client = testConfigureProvider(t, map[string]interface{}{"config_path": configPath, "token": "explicit-token", "api_url": "https://explicit.example.com/api"})
+	if client.Token != "explicit-token" || client.Host != "https://explicit.example.com/api" || client.HostV2 != "https://g.codefresh.io/2.0/api/graphql" {
+		t.Fatalf("expected the explicit arguments to take precedence over the current context, got %s %s", client.Host, client.HostV2)
+	}
+
+	// an explicit token is never sent to the URLs of a config context, which can't be configured along with it
+	_, diags := configureProvider(context.Background(), schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{"config_path": configPath, "config_context": "on-prem", "token": "explicit-token"}))
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "config_context cannot be set along with a token") {
+		t.Fatalf("expected a config context set along with a token to be rejected, got %v", diags)
+	}
+	t.Setenv(ENV_CODEFRESH_API_KEY, "env-token")
+	t.Setenv(ENV_CODEFRESH_CONFIG_CONTEXT, "on-prem")
+	meta, diags := configureProvider(context.Background(), schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{"config_path": configPath}))
+	if diags.HasError() || len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("expected a warning about the ignored config context, got %v", diags)
+	}
+	if client := meta.(*cfclient.Client); client.Token != "env-token" || client.Host != DEFAULT_CODEFRESH_API_URL {
+		t.Fatalf("expected the token of the environment to be used with the default URLs, got %s", client.Host)
+	}
+	t.Setenv(ENV_CODEFRESH_API_KEY, "")
+	t.Setenv(ENV_CODEFRESH_CONFIG_CONTEXT, "")
+
+	// the URLs of the environment are ignored when the token is read from a config context
+	t.Setenv(ENV_CODEFRESH_API_URL, "https://env.example.com/api")
+	meta, diags = configureProvider(context.Background(), schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{"config_path": configPath}))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if client := meta.(*cfclient.Client); client.Token != "prod-token" || client.Host != "https://g.codefresh.io/api" {
+		t.Fatalf("expected the URL of the current context to be used, got %s", client.Host)
+	}
+	t.Setenv(ENV_CODEFRESH_API_URL, "")
+
+	// nor is the token of a config context sent to other URLs
+	_, diags = configureProvider(context.Background(), schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{"config_path": configPath, "api_url_v2": "https://explicit.example.com/2.0/api/graphql"}))
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "cannot be set when the token is read from the Codefresh CLI config") {
+		t.Fatalf("expected URLs set along with the token of a config context to be rejected, got %v", diags)
+	}
+}
+
+func TestConfigureProviderDefaults(t *testing.T) {
+	client := testConfigureProvider(t, map[string]interface{}{})
+	if client.Token != "" || client.Host != DEFAULT_CODEFRESH_API_URL || client.HostV2 != DEFAULT_CODEFRESH_API2_URL {
+		t.Fatalf("expected the default URLs, got %s %s", client.Host, client.HostV2)
+	}
+}
+
+func TestConfigureProviderRunsTokenCommand(t *testing.T) {
+	if _, err := exec.LookPath("echo"); err != nil {
+		t.Skip("echo is not available")
+	}
+
+	client := testConfigureProvider(t, map[string]interface{}{"token_command": []interface{}{"echo", "short-lived-token"}})
+	if client.Token != "short-lived-token" {
+		t.Fatalf("expected the token printed by the command, got %q", client.Token)
+	}
+}
+
+func TestConfigureProviderChecksAccountID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user":
+			_, _ = w.Write([]byte(`{"activeAccountName":"production","account":[{"id":"prod-id","name":"production","admins":[]}]}`))
+		case "/accounts/prod-id/users":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cases := map[string]struct {
+		raw       map[string]interface{}
+		expectErr bool
+	}{
+		"allowed":       {map[string]interface{}{"allowed_account_ids": []interface{}{"prod-id"}}, false},
+		"not allowed":   {map[string]interface{}{"allowed_account_ids": []interface{}{"staging-id"}}, true},
+		"forbidden":     {map[string]interface{}{"forbidden_account_ids": []interface{}{"prod-id"}}, true},
+		"not forbidden": {map[string]interface{}{"forbidden_account_ids": []interface{}{"staging-id"}}, false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(ENV_CODEFRESH_API_KEY, "token")
+			t.Setenv("HOME", t.TempDir())
+			c.raw["api_url"] = server.URL
+
+			_, diags := configureProvider(context.Background(), schema.TestResourceDataRaw(t, Provider().Schema, c.raw))
+			if c.expectErr && !diags.HasError() {
+				t.Fatal("expected an error")
+			}
+			if !c.expectErr && diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+		})
+	}
+}
+
+func TestConfigureProviderStopsWhenContextIsDone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	t.Setenv(ENV_CODEFRESH_API_KEY, "token")
+	t.Setenv("HOME", t.TempDir())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, diags := configureProvider(ctx, schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"api_url":             server.URL,
+		"allowed_account_ids": []interface{}{"prod-id"},
+		"retry_max_attempts":  100,
+		"retry_max_backoff":   "10s",
+	}))
+	if !diags.HasError() {
+		t.Fatal("expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the retries to stop with the context, took %s", elapsed)
+	}
+}
+
+func TestReadOnlyGuard(t *testing.T) {
+	resource := Provider().ResourcesMap["codefresh_project"]
+	client := cfclient.NewClient("http://localhost", "http://localhost", "token", "")
+	client.ReadOnly = true
+
+	d := resource.TestResourceData()
+	if diags := resource.CreateContext(context.Background(), d, client); !diags.HasError() {
+		t.Fatal("expected create to fail in read-only mode")
+	}
+	if diags := resource.DeleteContext(context.Background(), d, client); !diags.HasError() {
+		t.Fatal("expected delete to fail in read-only mode")
+	}
+}
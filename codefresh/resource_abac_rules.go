@@ -7,6 +7,8 @@ import (
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
@@ -15,11 +17,12 @@ var validSetValues = []string{"REFRESH", "SYNC", "TERMINATE_SYNC", "VIEW_POD_LOG
 
 func resourceGitopsAbacRule() *schema.Resource {
 	return &schema.Resource{
-		Description: "Gitops ABAC Rules are used to setup access control and allow to define which teams have access to which resources based on tags and attributes.",
-		Create:      resourceGitopsAbacRuleCreate,
-		Read:        resourceGitopsAbacRuleRead,
-		Update:      resourceGitopsAbacRuleUpdate,
-		Delete:      resourceGitopsAbacRuleDelete,
+		Description:   "Gitops ABAC Rules are used to setup access control and allow to define which teams have access to which resources based on tags and attributes.",
+		CreateContext: resourceGitopsAbacRuleCreate,
+		ReadContext:   resourceGitopsAbacRuleRead,
+		UpdateContext: resourceGitopsAbacRuleUpdate,
+		DeleteContext: resourceGitopsAbacRuleDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -124,25 +127,25 @@ func contains(slice []string, element string) bool {
 	return false
 }
 
-func resourceGitopsAbacRuleCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceGitopsAbacRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
 	abacRule := *mapResourceToGitopsAbacRule(d)
 
-	newGitopsAbacRule, err := client.CreateAbacRule(&abacRule)
+	newGitopsAbacRule, err := client.CreateAbacRule(ctx, &abacRule)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if newGitopsAbacRule == nil {
-		return fmt.Errorf("resourceGitopsAbacRuleCreate - failed to create abac rule, empty response")
+		return diag.Errorf("resourceGitopsAbacRuleCreate - failed to create abac rule, empty response")
 	}
 
 	d.SetId(newGitopsAbacRule.ID)
 
-	return resourceGitopsAbacRuleRead(d, meta)
+	return resourceGitopsAbacRuleRead(ctx, d, meta)
 }
 
-func resourceGitopsAbacRuleRead(d *schema.ResourceData, meta interface{}) error {
+func resourceGitopsAbacRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
@@ -152,43 +155,43 @@ func resourceGitopsAbacRuleRead(d *schema.ResourceData, meta interface{}) error
 		return nil
 	}
 
-	abacRule, err := client.GetAbacRuleByID(abacRuleID)
+	abacRule, err := client.GetAbacRuleByID(ctx, abacRuleID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	err = mapGitopsAbacRuleToResource(abacRule, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceGitopsAbacRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceGitopsAbacRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
 	abacRule := *mapResourceToGitopsAbacRule(d)
-	resp, err := client.CreateAbacRule(&abacRule)
+	resp, err := client.CreateAbacRule(ctx, &abacRule)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	deleteErr := resourceGitopsAbacRuleDelete(d, meta)
+	deleteErr := resourceGitopsAbacRuleDelete(ctx, d, meta)
 	if deleteErr != nil {
 		log.Printf("[WARN] failed to delete permission %v: %v", abacRule, deleteErr)
 	}
 	d.SetId(resp.ID)
 
-	return resourceGitopsAbacRuleRead(d, meta)
+	return resourceGitopsAbacRuleRead(ctx, d, meta)
 }
 
-func resourceGitopsAbacRuleDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceGitopsAbacRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
-	_, err := client.DeleteAbacRule(d.Id())
+	_, err := client.DeleteAbacRule(ctx, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
@@ -114,7 +115,7 @@ func testAccCheckCodefreshAbacRulesExists(resource string) resource.TestCheckFun
 		abacRuleID := rs.Primary.ID
 
 		apiClient := testAccProvider.Meta().(*cfclient.Client)
-		_, err := apiClient.GetAbacRuleByID(abacRuleID)
+		_, err := apiClient.GetAbacRuleByID(context.Background(), abacRuleID)
 
 		if err != nil {
 			return fmt.Errorf("error fetching abac rule with ID %s. %s", abacRuleID, err)
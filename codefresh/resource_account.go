@@ -1,7 +1,12 @@
 package codefresh
 
 import (
+	"context"
+	"log"
+
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -10,10 +15,11 @@ func resourceAccount() *schema.Resource {
 		Description: `
 		By creating different accounts for different teams within the same company a customer can achieve complete segregation of assets between the teams. Requires a Codefresh admin token and applies only to Codefresh on-premises installations.
 		`,
-		Create: resourceAccountCreate,
-		Read:   resourceAccountRead,
-		Update: resourceAccountUpdate,
-		Delete: resourceAccountDelete,
+		CreateContext: resourceAccountCreate,
+		ReadContext:   resourceAccountRead,
+		UpdateContext: resourceAccountUpdate,
+		DeleteContext: resourceAccountDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -90,15 +96,15 @@ Features toggles for this account. Default:
 	}
 }
 
-func resourceAccountCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
 	account := *mapResourceToAccount(d)
 
-	resp, err := client.CreateAccount(&account)
+	resp, err := client.CreateAccount(ctx, &account)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(resp.ID)
@@ -106,7 +112,7 @@ func resourceAccountCreate(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func resourceAccountRead(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
@@ -116,39 +122,44 @@ func resourceAccountRead(d *schema.ResourceData, meta interface{}) error {
 		return nil
 	}
 
-	team, err := client.GetAccountByID(accountID)
+	team, err := client.GetAccountByID(ctx, accountID)
 	if err != nil {
-		return err
+		if cfclient.IsNotFound(err) {
+			log.Printf("[WARN] Account %s not found, removing it from state", accountID)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 
 	err = mapAccountToResource(team, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceAccountUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
 	account := *mapResourceToAccount(d)
 
-	_, err := client.UpdateAccount(&account)
+	_, err := client.UpdateAccount(ctx, &account)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceAccountDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
-	err := client.DeleteAccount(d.Id())
+	err := client.DeleteAccount(ctx, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -1,8 +1,12 @@
 package codefresh
 
 import (
+	"context"
+
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -11,10 +15,11 @@ func resourceAccountAdmins() *schema.Resource {
 		Description: `
 		Use this resource to set a list of admins for any account. Requires a Codefresh admin token and applies only to Codefresh on-premises installations.
 		`,
-		Create: resourceAccountAdminsCreate,
-		Read:   resourceAccountAdminsRead,
-		Update: resourceAccountAdminsUpdate,
-		Delete: resourceAccountAdminsDelete,
+		CreateContext: resourceAccountAdminsCreate,
+		ReadContext:   resourceAccountAdminsRead,
+		UpdateContext: resourceAccountAdminsUpdate,
+		DeleteContext: resourceAccountAdminsDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -36,7 +41,7 @@ func resourceAccountAdmins() *schema.Resource {
 	}
 }
 
-func resourceAccountAdminsCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountAdminsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
@@ -45,9 +50,9 @@ func resourceAccountAdminsCreate(d *schema.ResourceData, meta interface{}) error
 	accountId := d.Get("account_id").(string)
 
 	for _, admin := range datautil.ConvertStringArr(admins) {
-		err := client.SetUserAsAccountAdmin(accountId, admin)
+		err := client.SetUserAsAccountAdmin(ctx, accountId, admin)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
@@ -57,7 +62,7 @@ func resourceAccountAdminsCreate(d *schema.ResourceData, meta interface{}) error
 	return nil
 }
 
-func resourceAccountAdminsDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountAdminsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
@@ -66,16 +71,16 @@ func resourceAccountAdminsDelete(d *schema.ResourceData, meta interface{}) error
 	accountId := d.Get("account_id").(string)
 
 	for _, admin := range datautil.ConvertStringArr(admins) {
-		err := client.DeleteUserAsAccountAdmin(accountId, admin)
+		err := client.DeleteUserAsAccountAdmin(ctx, accountId, admin)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	return nil
 }
 
-func resourceAccountAdminsRead(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountAdminsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
@@ -84,46 +89,46 @@ func resourceAccountAdminsRead(d *schema.ResourceData, meta interface{}) error {
 	err := d.Set("account_id", accountId)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	account, err := client.GetAccountByID(accountId)
+	account, err := client.GetAccountByID(ctx, accountId)
 	if err != nil {
 		return nil
 	}
 	err = d.Set("users", account.Admins)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceAccountAdminsUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountAdminsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
 	accountId := d.Get("account_id").(string)
 	desiredAdmins := d.Get("users").(*schema.Set).List()
 
-	account, err := client.GetAccountByID(accountId)
+	account, err := client.GetAccountByID(ctx, accountId)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	adminsToAdd, AdminsToDelete := cfclient.GetAccountAdminsDiff(datautil.ConvertStringArr(desiredAdmins), account.Admins)
 
 	for _, userId := range AdminsToDelete {
-		err := client.DeleteUserAsAccountAdmin(accountId, userId)
+		err := client.DeleteUserAsAccountAdmin(ctx, accountId, userId)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	for _, userId := range adminsToAdd {
-		err := client.SetUserAsAccountAdmin(accountId, userId)
+		err := client.SetUserAsAccountAdmin(ctx, accountId, userId)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
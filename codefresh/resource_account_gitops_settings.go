@@ -1,27 +1,31 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/gitops"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceAccountGitopsSettings() *schema.Resource {
 	return &schema.Resource{
-		Description: "Codefresh account gitops setting - such as git provider, API URL for the git provider and internal shared config repository",
-		Read:        resourceAccountGitopsSettingsRead,
-		Create:      resourceAccountGitopsSettingsUpdate,
-		Update:      resourceAccountGitopsSettingsUpdate,
+		Description:   "Codefresh account gitops setting - such as git provider, API URL for the git provider and internal shared config repository",
+		ReadContext:   resourceAccountGitopsSettingsRead,
+		CreateContext: resourceAccountGitopsSettingsUpdate,
+		UpdateContext: resourceAccountGitopsSettingsUpdate,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 		// Delete not implemenented as gitops settings cannot be removed, only updated
-		Delete: resourceAccountGitopsSettingsDelete,
+		DeleteContext: resourceAccountGitopsSettingsDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
 		Schema: map[string]*schema.Schema{
 			"id": {
 				Type:        schema.TypeString,
@@ -70,21 +74,21 @@ func resourceAccountGitopsSettings() *schema.Resource {
 	}
 }
 
-func resourceAccountGitopsSettingsRead(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountGitopsSettingsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 	var accountGitopsInfo *cfclient.GitopsActiveAccountInfo
 
-	accountGitopsInfo, err := client.GetActiveGitopsAccountInfo()
+	accountGitopsInfo, err := client.GetActiveGitopsAccountInfo(ctx)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return mapAccountGitopsSettingsToResource(accountGitopsInfo, d)
+	return diag.FromErr(mapAccountGitopsSettingsToResource(accountGitopsInfo, d))
 }
 
-func resourceAccountGitopsSettingsUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountGitopsSettingsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
@@ -94,7 +98,7 @@ func resourceAccountGitopsSettingsUpdate(d *schema.ResourceData, meta interface{
 		url, err := gitops.GetDefaultAPIUrlForProvider(d.Get("git_provider").(string))
 
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		gitApiUrl = *url
@@ -103,17 +107,17 @@ func resourceAccountGitopsSettingsUpdate(d *schema.ResourceData, meta interface{
 		gitApiUrl = d.Get("git_provider_api_url").(string)
 	}
 
-	err := client.UpdateActiveGitopsAccountSettings(d.Get("git_provider").(string), gitApiUrl, d.Get("shared_config_repository").(string))
+	err := client.UpdateActiveGitopsAccountSettings(ctx, d.Get("git_provider").(string), gitApiUrl, d.Get("shared_config_repository").(string))
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return resourceAccountGitopsSettingsRead(d, meta)
+	return resourceAccountGitopsSettingsRead(ctx, d, meta)
 }
 
 // Settings cannot be deleted, only updated
-func resourceAccountGitopsSettingsDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountGitopsSettingsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	return nil
 }
 
@@ -1,6 +1,7 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -59,7 +60,7 @@ func testAccCheckGitopsSettings(resource string, gitProvider string, gitProvider
 
 		apiClient := testAccProvider.Meta().(*cfclient.Client)
 
-		accGitopsInfo, err := apiClient.GetActiveGitopsAccountInfo()
+		accGitopsInfo, err := apiClient.GetActiveGitopsAccountInfo(context.Background())
 
 		if err != nil {
 			return fmt.Errorf("failed getting gitops settings with error %s", err)
@@ -2,24 +2,26 @@ package codefresh
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"strconv"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/idp"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceAccountIdp() *schema.Resource {
 	return &schema.Resource{
-		Description: "Account level identity providers",
-		Create:      resourceAccountIDPCreate,
-		Read:        resourceAccountIDPRead,
-		Update:      resourceAccountIDPUpdate,
-		Delete:      resourceAccountIDPDelete,
+		Description:   "Account level identity providers",
+		CreateContext: resourceAccountIDPCreate,
+		ReadContext:   resourceAccountIDPRead,
+		UpdateContext: resourceAccountIDPUpdate,
+		DeleteContext: resourceAccountIDPDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -56,68 +58,69 @@ func resourceAccountIdp() *schema.Resource {
 	}
 }
 
-func resourceAccountIDPCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountIDPCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
-	id, err := client.CreateIDP(mapResourceToAccountIDP(d), false)
+	id, err := client.CreateIDP(ctx, mapResourceToAccountIDP(d), false)
 	if err != nil {
 		log.Printf("[DEBUG] Error while creating idp. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(id)
-	return resourceIDPRead(d, meta)
+	return resourceIDPRead(ctx, d, meta)
 }
 
-func resourceAccountIDPRead(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountIDPRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 	idpID := d.Id()
 
 	var cfClientIDP *cfclient.IDP
 	var err error
 
-	cfClientIDP, err = client.GetAccountIdpByID(idpID)
+	cfClientIDP, err = client.GetAccountIdpByID(ctx, idpID)
 	if err != nil {
-		if err.Error() == fmt.Sprintf("[ERROR] IDP with ID %s isn't found.", d.Id()) {
+		if cfclient.IsNotFound(err) {
+			log.Printf("[WARN] IDP %s not found, removing it from state", idpID)
 			d.SetId("")
 			return nil
 		}
 		log.Printf("[DEBUG] Error while getting IDP. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 
 	}
 
 	err = mapAccountIDPToResource(*cfClientIDP, d)
 	if err != nil {
 		log.Printf("[DEBUG] Error while getting mapping response to IDP object. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceAccountIDPDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountIDPDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
-	err := client.DeleteIDPAccount(d.Id())
+	err := client.DeleteIDPAccount(ctx, d.Id())
 	if err != nil {
 		log.Printf("[DEBUG] Error while deleting account level IDP. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceAccountIDPUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountIDPUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
-	err := client.UpdateIDP(mapResourceToAccountIDP(d), false)
+	err := client.UpdateIDP(ctx, mapResourceToAccountIDP(d), false)
 	if err != nil {
 		log.Printf("[DEBUG] Error while updating idp. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 	}
 
-	return resourceIDPRead(d, meta)
+	return resourceIDPRead(ctx, d, meta)
 }
 
 func mapAccountIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) error {
@@ -164,12 +167,13 @@ func mapAccountIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) e
 			// Codefresh API Returns the client secret as an encrypted string on the server side
 			// hence we need to keep in the state the original secret the user provides along with the encrypted computed secret
 			// for Terraform to properly calculate the diff
-			"client_secret":      d.Get("github.0.client_secret"),
-			"authentication_url": cfClientIDP.AuthURL,
-			"token_url":          cfClientIDP.TokenURL,
-			"user_profile_url":   cfClientIDP.UserProfileURL,
-			"api_host":           cfClientIDP.ApiHost,
-			"api_path_prefix":    cfClientIDP.ApiPathPrefix,
+			"client_secret":            d.Get("github.0.client_secret"),
+			"client_secret_wo_version": d.Get("github.0.client_secret_wo_version"),
+			"authentication_url":       cfClientIDP.AuthURL,
+			"token_url":                cfClientIDP.TokenURL,
+			"user_profile_url":         cfClientIDP.UserProfileURL,
+			"api_host":                 cfClientIDP.ApiHost,
+			"api_path_prefix":          cfClientIDP.ApiPathPrefix,
 		}}
 
 		err = d.Set(idp.GitHub, attributes)
@@ -181,11 +185,12 @@ func mapAccountIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) e
 
 	if cfClientIDP.ClientType == idp.GitLab {
 		attributes := []map[string]interface{}{{
-			"client_id":          cfClientIDP.ClientId,
-			"client_secret":      d.Get("gitlab.0.client_secret"),
-			"authentication_url": cfClientIDP.AuthURL,
-			"user_profile_url":   cfClientIDP.UserProfileURL,
-			"api_url":            cfClientIDP.ApiURL,
+			"client_id":                cfClientIDP.ClientId,
+			"client_secret":            d.Get("gitlab.0.client_secret"),
+			"client_secret_wo_version": d.Get("gitlab.0.client_secret_wo_version"),
+			"authentication_url":       cfClientIDP.AuthURL,
+			"user_profile_url":         cfClientIDP.UserProfileURL,
+			"api_url":                  cfClientIDP.ApiURL,
 		}}
 
 		err = d.Set(idp.GitLab, attributes)
@@ -197,12 +202,13 @@ func mapAccountIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) e
 
 	if cfClientIDP.ClientType == idp.Okta {
 		attributes := []map[string]interface{}{{
-			"client_id":            cfClientIDP.ClientId,
-			"client_secret":        d.Get("okta.0.client_secret"),
-			"client_host":          cfClientIDP.ClientHost,
-			"app_id":               d.Get("okta.0.app_id"),
-			"sync_mirror_accounts": cfClientIDP.SyncMirrorAccounts,
-			"access_token":         d.Get("okta.0.access_token"),
+			"client_id":                cfClientIDP.ClientId,
+			"client_secret":            d.Get("okta.0.client_secret"),
+			"client_secret_wo_version": d.Get("okta.0.client_secret_wo_version"),
+			"client_host":              cfClientIDP.ClientHost,
+			"app_id":                   d.Get("okta.0.app_id"),
+			"sync_mirror_accounts":     cfClientIDP.SyncMirrorAccounts,
+			"access_token":             d.Get("okta.0.access_token"),
 		}}
 
 		err = d.Set("okta", attributes)
@@ -214,12 +220,13 @@ func mapAccountIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) e
 
 	if cfClientIDP.ClientType == idp.Google {
 		attributes := []map[string]interface{}{{
-			"client_id":               cfClientIDP.ClientId,
-			"client_secret":           d.Get("google.0.client_secret"),
-			"admin_email":             d.Get("google.0.admin_email"),
-			"json_keyfile":            d.Get("google.0.json_keyfile"),
-			"allowed_groups_for_sync": cfClientIDP.AllowedGroupsForSync,
-			"sync_field":              cfClientIDP.SyncField,
+			"client_id":                cfClientIDP.ClientId,
+			"client_secret":            d.Get("google.0.client_secret"),
+			"client_secret_wo_version": d.Get("google.0.client_secret_wo_version"),
+			"admin_email":              d.Get("google.0.admin_email"),
+			"json_keyfile":             d.Get("google.0.json_keyfile"),
+			"allowed_groups_for_sync":  cfClientIDP.AllowedGroupsForSync,
+			"sync_field":               cfClientIDP.SyncField,
 		}}
 
 		err = d.Set(idp.Google, attributes)
@@ -231,9 +238,10 @@ func mapAccountIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) e
 
 	if cfClientIDP.ClientType == idp.Auth0 {
 		attributes := []map[string]interface{}{{
-			"client_id":     cfClientIDP.ClientId,
-			"client_secret": d.Get("auth0.0.client_secret"),
-			"domain":        cfClientIDP.ClientHost,
+			"client_id":                cfClientIDP.ClientId,
+			"client_secret":            d.Get("auth0.0.client_secret"),
+			"client_secret_wo_version": d.Get("auth0.0.client_secret_wo_version"),
+			"domain":                   cfClientIDP.ClientHost,
 		}}
 
 		err = d.Set(idp.Auth0, attributes)
@@ -253,6 +261,7 @@ func mapAccountIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) e
 		attributes := []map[string]interface{}{{
 			"app_id":                   cfClientIDP.ClientId,
 			"client_secret":            d.Get("azure.0.client_secret"),
+			"client_secret_wo_version": d.Get("azure.0.client_secret_wo_version"),
 			"object_id":                cfClientIDP.AppId,
 			"autosync_teams_and_users": cfClientIDP.AutoGroupSync,
 			"sync_interval":            syncInterval,
@@ -268,10 +277,11 @@ func mapAccountIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) e
 
 	if cfClientIDP.ClientType == idp.OneLogin {
 		attributes := []map[string]interface{}{{
-			"client_id":     cfClientIDP.ClientId,
-			"client_secret": d.Get("onelogin.0.client_secret"),
-			"domain":        cfClientIDP.ClientHost,
-			"api_client_id": cfClientIDP.ApiClientId,
+			"client_id":                cfClientIDP.ClientId,
+			"client_secret":            d.Get("onelogin.0.client_secret"),
+			"client_secret_wo_version": d.Get("onelogin.0.client_secret_wo_version"),
+			"domain":                   cfClientIDP.ClientHost,
+			"api_client_id":            cfClientIDP.ApiClientId,
 			// When account scoped, Client secret is returned obfuscated after first apply, causing diff to appear everytime.
 			// This behavior would always set the API clint secret from the resource, allowing at least changing the secret when the value in terraform configuration changes.
 			// Though it would not detect drift if the secret is changed from UI.
@@ -288,10 +298,11 @@ func mapAccountIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) e
 
 	if cfClientIDP.ClientType == idp.Keycloak {
 		attributes := []map[string]interface{}{{
-			"client_id":     cfClientIDP.ClientId,
-			"client_secret": d.Get("keycloak.0.client_secret"),
-			"host":          cfClientIDP.Host,
-			"realm":         cfClientIDP.Realm,
+			"client_id":                cfClientIDP.ClientId,
+			"client_secret":            d.Get("keycloak.0.client_secret"),
+			"client_secret_wo_version": d.Get("keycloak.0.client_secret_wo_version"),
+			"host":                     cfClientIDP.Host,
+			"realm":                    cfClientIDP.Realm,
 		}}
 
 		err = d.Set(idp.Keycloak, attributes)
@@ -363,7 +374,7 @@ func mapResourceToAccountIDP(d *schema.ResourceData) *cfclient.IDP {
 	if _, ok := d.GetOk(idp.GitHub); ok {
 		cfClientIDP.ClientType = idp.GitHub
 		cfClientIDP.ClientId = d.Get("github.0.client_id").(string)
-		cfClientIDP.ClientSecret = d.Get("github.0.client_secret").(string)
+		cfClientIDP.ClientSecret = schemautil.GetStringOrWriteOnly(d, "github.0.client_secret")
 		cfClientIDP.AuthURL = d.Get("github.0.authentication_url").(string)
 		cfClientIDP.TokenURL = d.Get("github.0.token_url").(string)
 		cfClientIDP.UserProfileURL = d.Get("github.0.user_profile_url").(string)
@@ -374,7 +385,7 @@ func mapResourceToAccountIDP(d *schema.ResourceData) *cfclient.IDP {
 	if _, ok := d.GetOk(idp.GitLab); ok {
 		cfClientIDP.ClientType = idp.GitLab
 		cfClientIDP.ClientId = d.Get("gitlab.0.client_id").(string)
-		cfClientIDP.ClientSecret = d.Get("gitlab.0.client_secret").(string)
+		cfClientIDP.ClientSecret = schemautil.GetStringOrWriteOnly(d, "gitlab.0.client_secret")
 		cfClientIDP.AuthURL = d.Get("gitlab.0.authentication_url").(string)
 		cfClientIDP.UserProfileURL = d.Get("gitlab.0.user_profile_url").(string)
 		cfClientIDP.ApiURL = d.Get("gitlab.0.api_url").(string)
@@ -383,7 +394,7 @@ func mapResourceToAccountIDP(d *schema.ResourceData) *cfclient.IDP {
 	if _, ok := d.GetOk(idp.Okta); ok {
 		cfClientIDP.ClientType = idp.Okta
 		cfClientIDP.ClientId = d.Get("okta.0.client_id").(string)
-		cfClientIDP.ClientSecret = d.Get("okta.0.client_secret").(string)
+		cfClientIDP.ClientSecret = schemautil.GetStringOrWriteOnly(d, "okta.0.client_secret")
 		cfClientIDP.ClientHost = d.Get("okta.0.client_host").(string)
 		cfClientIDP.AppId = d.Get("okta.0.app_id").(string)
 		cfClientIDP.SyncMirrorAccounts = datautil.ConvertStringArr(d.Get("okta.0.sync_mirror_accounts").([]interface{}))
@@ -393,7 +404,7 @@ func mapResourceToAccountIDP(d *schema.ResourceData) *cfclient.IDP {
 	if _, ok := d.GetOk(idp.Google); ok {
 		cfClientIDP.ClientType = idp.Google
 		cfClientIDP.ClientId = d.Get("google.0.client_id").(string)
-		cfClientIDP.ClientSecret = d.Get("google.0.client_secret").(string)
+		cfClientIDP.ClientSecret = schemautil.GetStringOrWriteOnly(d, "google.0.client_secret")
 		cfClientIDP.KeyFile = d.Get("google.0.json_keyfile").(string)
 		cfClientIDP.Subject = d.Get("google.0.admin_email").(string)
 		cfClientIDP.AllowedGroupsForSync = d.Get("google.0.allowed_groups_for_sync").(string)
@@ -403,14 +414,14 @@ func mapResourceToAccountIDP(d *schema.ResourceData) *cfclient.IDP {
 	if _, ok := d.GetOk(idp.Auth0); ok {
 		cfClientIDP.ClientType = idp.Auth0
 		cfClientIDP.ClientId = d.Get("auth0.0.client_id").(string)
-		cfClientIDP.ClientSecret = d.Get("auth0.0.client_secret").(string)
+		cfClientIDP.ClientSecret = schemautil.GetStringOrWriteOnly(d, "auth0.0.client_secret")
 		cfClientIDP.ClientHost = d.Get("auth0.0.domain").(string)
 	}
 
 	if _, ok := d.GetOk(idp.Azure); ok {
 		cfClientIDP.ClientType = idp.Azure
 		cfClientIDP.ClientId = d.Get("azure.0.app_id").(string)
-		cfClientIDP.ClientSecret = d.Get("azure.0.client_secret").(string)
+		cfClientIDP.ClientSecret = schemautil.GetStringOrWriteOnly(d, "azure.0.client_secret")
 		cfClientIDP.AppId = d.Get("azure.0.object_id").(string)
 		cfClientIDP.Tenant = d.Get("azure.0.tenant").(string)
 		cfClientIDP.AutoGroupSync = d.Get("azure.0.autosync_teams_and_users").(bool)
@@ -420,7 +431,7 @@ func mapResourceToAccountIDP(d *schema.ResourceData) *cfclient.IDP {
 	if _, ok := d.GetOk(idp.OneLogin); ok {
 		cfClientIDP.ClientType = idp.OneLogin
 		cfClientIDP.ClientId = d.Get("onelogin.0.client_id").(string)
-		cfClientIDP.ClientSecret = d.Get("onelogin.0.client_secret").(string)
+		cfClientIDP.ClientSecret = schemautil.GetStringOrWriteOnly(d, "onelogin.0.client_secret")
 		cfClientIDP.ClientHost = d.Get("onelogin.0.domain").(string)
 		cfClientIDP.AppId = d.Get("onelogin.0.app_id").(string)
 		cfClientIDP.ApiClientId = d.Get("onelogin.0.api_client_id").(string)
@@ -430,7 +441,7 @@ func mapResourceToAccountIDP(d *schema.ResourceData) *cfclient.IDP {
 	if _, ok := d.GetOk(idp.Keycloak); ok {
 		cfClientIDP.ClientType = idp.Keycloak
 		cfClientIDP.ClientId = d.Get("keycloak.0.client_id").(string)
-		cfClientIDP.ClientSecret = d.Get("keycloak.0.client_secret").(string)
+		cfClientIDP.ClientSecret = schemautil.GetStringOrWriteOnly(d, "keycloak.0.client_secret")
 		cfClientIDP.Host = d.Get("keycloak.0.host").(string)
 		cfClientIDP.Realm = d.Get("keycloak.0.realm").(string)
 	}
@@ -1,12 +1,14 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -107,7 +109,7 @@ func testAccCheckCodefreshAccountIDPExists(resource string) resource.TestCheckFu
 		idpID := rs.Primary.ID
 
 		apiClient := testAccProvider.Meta().(*cfclient.Client)
-		_, err := apiClient.GetAccountIdpByID(idpID)
+		_, err := apiClient.GetAccountIdpByID(context.Background(), idpID)
 
 		if err != nil {
 			return fmt.Errorf("error fetching project with resource %s. %s", resource, err)
@@ -276,3 +278,80 @@ func testAccountIDPCodefreshConfig(idpType string, uniqueId string) string {
 
 	return idpResource
 }
+
+func TestMapAccountIDPFromCassette(t *testing.T) {
+	client := testCassetteClient(t, "account_idps")
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		config   map[string]interface{}
+		expected map[string]string
+	}{
+		"github": {
+			config: map[string]interface{}{
+				"display_name": "cassette-github",
+				"github": []interface{}{map[string]interface{}{
+					"client_id":     "github-client-id",
+					"client_secret": "github-client-secret",
+					"api_host":      "api.github.com",
+				}},
+			},
+			expected: map[string]string{
+				"client_type":            "github",
+				"github.0.client_id":     "github-client-id",
+				"github.0.client_secret": "github-client-secret",
+				"github.0.api_host":      "api.github.com",
+			},
+		},
+		"okta": {
+			config: map[string]interface{}{
+				"display_name": "cassette-okta",
+				"okta": []interface{}{map[string]interface{}{
+					"client_id":     "okta-client-id",
+					"client_secret": "okta-client-secret",
+					"client_host":   "https://example.okta.com",
+					"app_id":        "okta-app-id",
+				}},
+			},
+			expected: map[string]string{
+				"client_type":          "okta",
+				"okta.0.client_id":     "okta-client-id",
+				"okta.0.client_secret": "okta-client-secret",
+				"okta.0.client_host":   "https://example.okta.com",
+				"okta.0.app_id":        "okta-app-id",
+			},
+		},
+	}
+
+	for _, name := range []string{"github", "okta"} {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceAccountIdp().Schema, c.config)
+			id, err := client.CreateIDP(ctx, mapResourceToAccountIDP(d), false)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer func() {
+				if err := client.DeleteIDPAccount(ctx, id); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}()
+
+			cfClientIDP, err := client.GetAccountIdpByID(ctx, id)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := mapAccountIDPToResource(*cfClientIDP, d); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if d.Id() != id || d.Get("display_name") != c.config["display_name"] {
+				t.Fatalf("unexpected id %s and display name %v", d.Id(), d.Get("display_name"))
+			}
+			for key, value := range c.expected {
+				if actual := d.Get(key); actual != value {
+					t.Errorf("expected %s to be %q, got %q", key, value, actual)
+				}
+			}
+		})
+	}
+}
@@ -2,9 +2,10 @@ package codefresh
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -14,10 +15,11 @@ func resourceAccountUserAssociation() *schema.Resource {
 		Description: `
 		Associates a user with the account which the provider is authenticated against. If the user is not present in the system, an invitation will be sent to the specified email address.
 		`,
-		Create: resourceAccountUserAssociationCreate,
-		Read:   resourceAccountUserAssociationRead,
-		Update: resourceAccountUserAssociationUpdate,
-		Delete: resourceAccountUserAssociationDelete,
+		CreateContext: resourceAccountUserAssociationCreate,
+		ReadContext:   resourceAccountUserAssociationRead,
+		UpdateContext: resourceAccountUserAssociationUpdate,
+		DeleteContext: resourceAccountUserAssociationDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -57,41 +59,41 @@ func resourceAccountUserAssociation() *schema.Resource {
 	}
 }
 
-func resourceAccountUserAssociationCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountUserAssociationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
-	currentAccount, err := client.GetCurrentAccount()
+	currentAccount, err := client.GetCurrentAccount(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	user, err := client.AddNewUserToAccount(currentAccount.ID, "", d.Get("email").(string))
+	user, err := client.AddNewUserToAccount(ctx, currentAccount.ID, "", d.Get("email").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(user.ID)
 
 	if d.Get("admin").(bool) {
-		err = client.SetUserAsAccountAdmin(currentAccount.ID, d.Id())
+		err = client.SetUserAsAccountAdmin(ctx, currentAccount.ID, d.Id())
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	err = d.Set("status", user.Status)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceAccountUserAssociationRead(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountUserAssociationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
-	currentAccount, err := client.GetCurrentAccount()
+	currentAccount, err := client.GetCurrentAccount(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	userID := d.Id()
@@ -106,25 +108,25 @@ func resourceAccountUserAssociationRead(d *schema.ResourceData, meta interface{}
 			err = d.Set("email", user.Email)
 
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 
 			err = d.Set("username", user.UserName)
 
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 
 			err = d.Set("status", user.Status)
 
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 
 			err = d.Set("admin", false) // avoid missing attributes after import
 
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 
 			for _, admin := range currentAccount.Admins {
@@ -132,7 +134,7 @@ func resourceAccountUserAssociationRead(d *schema.ResourceData, meta interface{}
 					err = d.Set("admin", true)
 
 					if err != nil {
-						return err
+						return diag.FromErr(err)
 					}
 				}
 			}
@@ -140,40 +142,40 @@ func resourceAccountUserAssociationRead(d *schema.ResourceData, meta interface{}
 	}
 
 	if d.Id() == "" {
-		return fmt.Errorf("a user with ID %s was not found", userID)
+		return diag.Errorf("a user with ID %s was not found", userID)
 	}
 
 	return nil
 }
 
-func resourceAccountUserAssociationUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountUserAssociationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
-	currentAccount, err := client.GetCurrentAccount()
+	currentAccount, err := client.GetCurrentAccount(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if d.HasChange("email") {
-		user, err := client.UpdateUserDetails(currentAccount.ID, d.Id(), d.Get("username").(string), d.Get("email").(string))
+		user, err := client.UpdateUserDetails(ctx, currentAccount.ID, d.Id(), d.Get("username").(string), d.Get("email").(string))
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		if user.Email != d.Get("email").(string) {
-			return fmt.Errorf("failed to update user email, despite successful API response")
+			return diag.Errorf("failed to update user email, despite successful API response")
 		}
 	}
 
 	if d.HasChange("admin") {
 		if d.Get("admin").(bool) {
-			err = client.SetUserAsAccountAdmin(currentAccount.ID, d.Id())
+			err = client.SetUserAsAccountAdmin(ctx, currentAccount.ID, d.Id())
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		} else {
-			err = client.DeleteUserAsAccountAdmin(currentAccount.ID, d.Id())
+			err = client.DeleteUserAsAccountAdmin(ctx, currentAccount.ID, d.Id())
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 	}
@@ -181,17 +183,17 @@ func resourceAccountUserAssociationUpdate(d *schema.ResourceData, meta interface
 	return nil
 }
 
-func resourceAccountUserAssociationDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceAccountUserAssociationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
-	currentAccount, err := client.GetCurrentAccount()
+	currentAccount, err := client.GetCurrentAccount(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	err = client.DeleteUserFromAccount(currentAccount.ID, d.Id())
+	err = client.DeleteUserFromAccount(ctx, currentAccount.ID, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
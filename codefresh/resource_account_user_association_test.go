@@ -1,6 +1,7 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -18,13 +19,13 @@ func testAccCodefreshAccountUserAssociationGenerateUserEmail() string {
 
 func testAccCodefreshActivateUser(s *terraform.State, email string) error {
 	c := testAccProvider.Meta().(*cfclient.Client)
-	currentAccount, err := c.GetCurrentAccount()
+	currentAccount, err := c.GetCurrentAccount(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to get current account: %s", err)
 	}
 	for _, user := range currentAccount.Users {
 		if user.Email == email {
-			err = c.ActivateUser(user.ID)
+			err = c.ActivateUser(context.Background(), user.ID)
 
 			if err != nil {
 				return fmt.Errorf("failed to activate user: %s", err)
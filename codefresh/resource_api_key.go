@@ -1,12 +1,15 @@
 package codefresh
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"log"
 	"strings"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -17,10 +20,11 @@ func resourceApiKey() *schema.Resource {
 		On the Codefresh SaaS platfrom this resource is only usable for service accounts.
 		Management of API keys for users in other accounts requires admin priveleges and hence can only be done on Codefresh on-premises installations.
 		`,
-		Create: resourceApiKeyCreate,
-		Read:   resourceApiKeyRead,
-		Update: resourceApiKeyUpdate,
-		Delete: resourceApiKeyDelete,
+		CreateContext: resourceApiKeyCreate,
+		ReadContext:   resourceApiKeyRead,
+		UpdateContext: resourceApiKeyUpdate,
+		DeleteContext: resourceApiKeyDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -90,7 +94,7 @@ A list of access scopes for the API key. The possible values:
 	}
 }
 
-func resourceApiKeyCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceApiKeyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 	apiKey := *mapResourceToApiKey(d)
 
@@ -100,22 +104,22 @@ func resourceApiKeyCreate(d *schema.ResourceData, meta interface{}) error {
 	)
 
 	if serviceAccountId := d.Get("service_account_id").(string); serviceAccountId != "" {
-		resp, err = client.CreateApiKeyServiceUser(serviceAccountId, &apiKey)
+		resp, err = client.CreateApiKeyServiceUser(ctx, serviceAccountId, &apiKey)
 	} else {
 		accountID := d.Get("account_id").(string)
 		userID := d.Get("user_id").(string)
 
-		resp, err = client.CreateApiKey(userID, accountID, &apiKey)
+		resp, err = client.CreateApiKey(ctx, userID, accountID, &apiKey)
 	}
 
 	if err != nil {
 		fmt.Println(string(resp))
-		return err
+		return diag.FromErr(err)
 	}
 
 	err = d.Set("token", resp)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Codefresh tokens are in the form xxxxxxxxxxxx.xxxxxxxxx the first half serves as the id
@@ -124,7 +128,7 @@ func resourceApiKeyCreate(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func resourceApiKeyRead(d *schema.ResourceData, meta interface{}) error {
+func resourceApiKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
@@ -137,7 +141,7 @@ func resourceApiKeyRead(d *schema.ResourceData, meta interface{}) error {
 	token := d.Get("token").(string)
 
 	if token == "" {
-		return errors.New("[ERROR] Can't read API Key. Token is empty.")
+		return diag.Errorf("[ERROR] Can't read API Key. Token is empty.")
 	}
 
 	var (
@@ -146,66 +150,71 @@ func resourceApiKeyRead(d *schema.ResourceData, meta interface{}) error {
 	)
 
 	if serviceAccountId := d.Get("service_account_id").(string); serviceAccountId != "" {
-		apiKey, err = client.GetAPIKeyServiceUser(keyID, serviceAccountId)
+		apiKey, err = client.GetAPIKeyServiceUser(ctx, keyID, serviceAccountId)
 	} else {
-		apiKey, err = client.GetAPIKey(keyID)
+		apiKey, err = client.GetAPIKey(ctx, keyID)
 	}
 
 	if err != nil {
-		return err
+		if cfclient.IsNotFound(err) {
+			log.Printf("[WARN] API key %s not found, removing it from state", keyID)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 
 	err = mapApiKeyToResource(apiKey, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceApiKeyUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceApiKeyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
 	apiKey := *mapResourceToApiKey(d)
 
 	token := d.Get("token").(string)
 	if token == "" {
-		return errors.New("[ERROR] Can't read API Key. Token is empty.")
+		return diag.Errorf("[ERROR] Can't read API Key. Token is empty.")
 	}
 
 	var err error
 
 	if serviceAccountId := d.Get("service_account_id").(string); serviceAccountId != "" {
-		err = client.UpdateAPIKeyServiceUser(&apiKey, serviceAccountId)
+		err = client.UpdateAPIKeyServiceUser(ctx, &apiKey, serviceAccountId)
 	} else {
-		err = client.UpdateAPIKey(&apiKey)
+		err = client.UpdateAPIKey(ctx, &apiKey)
 
 	}
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceApiKeyDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceApiKeyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
 	token := d.Get("token").(string)
 	if token == "" {
-		return errors.New("[ERROR] Can't read API Key. Token is empty.")
+		return diag.Errorf("[ERROR] Can't read API Key. Token is empty.")
 	}
 
 	var err error
 	if serviceAccountId := d.Get("service_account_id").(string); serviceAccountId != "" {
-		err = client.DeleteAPIKeyServiceUser(d.Id(), serviceAccountId)
+		err = client.DeleteAPIKeyServiceUser(ctx, d.Id(), serviceAccountId)
 	} else {
-		err = client.DeleteAPIKey(d.Id())
+		err = client.DeleteAPIKey(ctx, d.Id())
 	}
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -65,7 +66,7 @@ func testAccCheckCodefreshServiceUserAPIKeyExists(apiKeyResource string, service
 		apiKeyID := apiKeyState.Primary.ID
 
 		apiClient := testAccProvider.Meta().(*cfclient.Client)
-		_, err := apiClient.GetAPIKeyServiceUser(apiKeyID, serviceUserID)
+		_, err := apiClient.GetAPIKeyServiceUser(context.Background(), apiKeyID, serviceUserID)
 
 		if err != nil {
 			return fmt.Errorf("error fetching service user api key for resource %s. %s", apiKeyID, err)
@@ -91,7 +92,7 @@ func testAccCheckCodefreshServiceUserAndAPIKeyDestroyed(s *terraform.State) erro
 
 		if rs.Type == "codefresh_service_account" {
 			serviceAccountId = rs.Primary.ID
-			_, err := apiClient.GetServiceUserByID(serviceAccountId)
+			_, err := apiClient.GetServiceUserByID(context.Background(), serviceAccountId)
 
 			if err == nil {
 				return fmt.Errorf("Alert service account still exists")
@@ -100,7 +101,7 @@ func testAccCheckCodefreshServiceUserAndAPIKeyDestroyed(s *terraform.State) erro
 
 		if rs.Type == "codefresh_api_key" {
 			apiKeyId = rs.Primary.ID
-			_, err := apiClient.GetAPIKeyServiceUser(apiKeyId, serviceAccountId)
+			_, err := apiClient.GetAPIKeyServiceUser(context.Background(), apiKeyId, serviceAccountId)
 
 			if err == nil {
 				return fmt.Errorf("Alert api key still exists")
@@ -1,6 +1,7 @@
 package codefresh
 
 import (
+	"context"
 	"log"
 
 	storageContext "github.com/codefresh-io/terraform-provider-codefresh/codefresh/context"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
 	"github.com/ghodss/yaml"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -41,12 +43,13 @@ func getConflictingContexts(context string) []string {
 }
 
 func resourceContext() *schema.Resource {
-	return &schema.Resource{
-		Description: "A Context is an authentication/configuration resource used by the Codefresh system and engine.",
-		Create:      resourceContextCreate,
-		Read:        resourceContextRead,
-		Update:      resourceContextUpdate,
-		Delete:      resourceContextDelete,
+	contextResource := &schema.Resource{
+		Description:   "A Context is an authentication/configuration resource used by the Codefresh system and engine.",
+		CreateContext: resourceContextCreate,
+		ReadContext:   resourceContextRead,
+		UpdateContext: resourceContextUpdate,
+		DeleteContext: resourceContextDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -154,22 +157,29 @@ func resourceContext() *schema.Resource {
 			},
 		},
 	}
+	// the data of secret contexts can be set without being stored in state
+	spec := contextResource.Schema["spec"].Elem.(*schema.Resource).Schema
+	for _, contextType := range []string{contextSecret, contextSecretYaml} {
+		normalizedContextType := schemautil.MustNormalizeFieldName(contextType)
+		schemautil.AddWriteOnlyAttribute(spec[normalizedContextType].Elem.(*schema.Resource).Schema, "spec.0."+normalizedContextType+".0.", "data")
+	}
+	return contextResource
 }
 
-func resourceContextCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceContextCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
-	resp, err := client.CreateContext(mapResourceToContext(d))
+	resp, err := client.CreateContext(ctx, mapResourceToContext(d))
 	if err != nil {
 		log.Printf("[DEBUG] Error while creating context. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(resp.Metadata.Name)
-	return resourceContextRead(d, meta)
+	return resourceContextRead(ctx, d, meta)
 }
 
-func resourceContextRead(d *schema.ResourceData, meta interface{}) error {
+func resourceContextRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
 	contextName := d.Id()
@@ -179,46 +189,51 @@ func resourceContextRead(d *schema.ResourceData, meta interface{}) error {
 		return nil
 	}
 
-	context, err := client.GetContext(contextName)
+	context, err := client.GetContext(ctx, contextName)
 
 	if err != nil {
+		if cfclient.IsNotFound(err) {
+			log.Printf("[WARN] Context %s not found, removing it from state", contextName)
+			d.SetId("")
+			return nil
+		}
 		log.Printf("[DEBUG] Error while getting context. Error = %v", contextName)
-		return err
+		return diag.FromErr(err)
 	}
 
 	err = mapContextToResource(*context, d)
 
 	if err != nil {
 		log.Printf("[DEBUG] Error while mapping context to resource. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceContextUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceContextUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
 	context := *mapResourceToContext(d)
 	context.Metadata.Name = d.Id()
 
-	_, err := client.UpdateContext(&context)
+	_, err := client.UpdateContext(ctx, &context)
 	if err != nil {
 		log.Printf("[DEBUG] Error while updating context. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 	}
 
-	return resourceContextRead(d, meta)
+	return resourceContextRead(ctx, d, meta)
 }
 
-func resourceContextDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceContextDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
-	err := client.DeleteContext(d.Id())
+	err := client.DeleteContext(ctx, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -235,7 +250,19 @@ func mapContextToResource(context cfclient.Context, d *schema.ResourceData) erro
 	// Read spec from API if context is not encrypted or forbitDecrypt is not set
 	if !context.IsEncrypred {
 
-		err = d.Set("spec", flattenContextSpec(context.Spec))
+		spec := flattenContextSpec(context.Spec)
+		// secret data is kept out of state when it is set with its write-only alternative, along with the version of the latter
+		if context.Spec.Type == contextSecret || context.Spec.Type == contextSecretYaml {
+			normalizedContextType := schemautil.MustNormalizeFieldName(context.Spec.Type)
+			dataPath := "spec.0." + normalizedContextType + ".0.data"
+			if blocks, ok := spec[0].(map[string]interface{})[normalizedContextType].([]interface{}); ok && len(blocks) > 0 {
+				block := blocks[0].(map[string]interface{})
+				block["data"] = schemautil.SecretToKeep(d, dataPath, block["data"])
+				block["data_wo_version"] = d.Get(dataPath + "_wo_version")
+			}
+		}
+
+		err = d.Set("spec", spec)
 
 		if err != nil {
 			log.Printf("[DEBUG] Failed to flatten Context spec = %v", context.Spec)
@@ -288,6 +315,12 @@ func flattenContextYaml(spec cfclient.ContextSpec) []interface{} {
 	return res
 }
 
+// secretContextDataIsSet returns true if the data of a secret context is set, or its write-only alternative
+func secretContextDataIsSet(d *schema.ResourceData, path string) bool {
+	_, ok := d.GetOk(path)
+	return ok || schemautil.UsesWriteOnly(d, path)
+}
+
 func mapResourceToContext(d *schema.ResourceData) *cfclient.Context {
 
 	var normalizedContextType string
@@ -296,15 +329,15 @@ func mapResourceToContext(d *schema.ResourceData) *cfclient.Context {
 	if data, ok := d.GetOk("spec.0." + schemautil.MustNormalizeFieldName(contextConfig) + ".0.data"); ok {
 		normalizedContextType = contextConfig
 		normalizedContextData = data.(map[string]interface{})
-	} else if data, ok := d.GetOk("spec.0." + schemautil.MustNormalizeFieldName(contextSecret) + ".0.data"); ok {
+	} else if secretPath := "spec.0." + schemautil.MustNormalizeFieldName(contextSecret) + ".0.data"; secretContextDataIsSet(d, secretPath) {
 		normalizedContextType = contextSecret
-		normalizedContextData = data.(map[string]interface{})
+		normalizedContextData = schemautil.GetMapOrWriteOnly(d, secretPath)
 	} else if data, ok := d.GetOk("spec.0." + schemautil.MustNormalizeFieldName(contextYaml) + ".0.data"); ok {
 		normalizedContextType = contextYaml
 		_ = yaml.Unmarshal([]byte(data.(string)), &normalizedContextData)
-	} else if data, ok := d.GetOk("spec.0." + schemautil.MustNormalizeFieldName(contextSecretYaml) + ".0.data"); ok {
+	} else if secretYamlPath := "spec.0." + schemautil.MustNormalizeFieldName(contextSecretYaml) + ".0.data"; secretContextDataIsSet(d, secretYamlPath) {
 		normalizedContextType = contextSecretYaml
-		_ = yaml.Unmarshal([]byte(data.(string)), &normalizedContextData)
+		_ = yaml.Unmarshal([]byte(schemautil.GetStringOrWriteOnly(d, secretYamlPath)), &normalizedContextData)
 	} else if data, ok := d.GetOk("spec.0." + schemautil.MustNormalizeFieldName(contextGoogleStorage) + ".0.data"); ok {
 		normalizedContextType = contextGoogleStorage
 		normalizedContextData = storageContext.ConvertJsonConfigStorageContext(data.([]interface{}))
@@ -1,11 +1,13 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"testing"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/fakeapi"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -145,6 +147,54 @@ func TestAccCodefreshContextSecretYaml(t *testing.T) {
 	})
 }
 
+func TestContextSecretDataCanBeWriteOnly(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+	client := cfclient.NewClient(server.APIURL(), server.GraphQLURL(), fakeapi.APIKey, "")
+	ctx := context.Background()
+
+	contextResource := resourceContext()
+	contextData := testApplyWithWriteOnly(t, contextResource, map[string]interface{}{
+		"name": "secret",
+		"spec": []interface{}{map[string]interface{}{
+			"secret": []interface{}{map[string]interface{}{"data_wo_version": 1}},
+		}},
+	}, map[string]interface{}{
+		"name": "secret",
+		"spec": []interface{}{map[string]interface{}{
+			"secret": []interface{}{map[string]interface{}{"data_wo": `{"password":"s3cr3t"}`, "data_wo_version": 1}},
+		}},
+	}, client)
+
+	secretContext, err := client.GetContext(ctx, "secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if secretContext.Spec.Type != contextSecret || secretContext.Spec.Data["password"] != "s3cr3t" {
+		t.Fatalf("expected the write-only data to be sent to Codefresh, got %v", secretContext.Spec)
+	}
+
+	if diags := contextResource.ReadContext(ctx, contextData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if len(contextData.Get("spec.0.secret.0.data").(map[string]interface{})) != 0 {
+		t.Fatalf("expected the write-only data not to be stored in state, got %v", contextData.State().Attributes)
+	}
+	if contextData.Get("spec.0.secret.0.data_wo_version") != 1 {
+		t.Fatalf("expected data_wo_version to be kept, got %v", contextData.Get("spec.0.secret.0.data_wo_version"))
+	}
+
+	// an imported context has no prior state telling whether its data is set with the write-only alternative
+	importedData := contextResource.Data(nil)
+	importedData.SetId("secret")
+	if diags := contextResource.ReadContext(ctx, importedData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if importedData.Get("name") != "secret" || len(importedData.Get("spec.0.secret.0.data").(map[string]interface{})) != 0 {
+		t.Fatalf("expected the imported data not to be stored in state, got %v", importedData.State().Attributes)
+	}
+}
+
 func testAccCheckCodefreshContextExists(resource string) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
 
@@ -159,7 +209,7 @@ func testAccCheckCodefreshContextExists(resource string) resource.TestCheckFunc
 		contextID := rs.Primary.ID
 
 		apiClient := testAccProvider.Meta().(*cfclient.Client)
-		_, err := apiClient.GetContext(contextID)
+		_, err := apiClient.GetContext(context.Background(), contextID)
 
 		if err != nil {
 			return fmt.Errorf("error fetching context with ID %s. %s", contextID, err)
@@ -177,7 +227,7 @@ func testAccCheckCodefreshContextDestroy(s *terraform.State) error {
 			continue
 		}
 
-		_, err := apiClient.GetContext(rs.Primary.ID)
+		_, err := apiClient.GetContext(context.Background(), rs.Primary.ID)
 
 		if err == nil {
 			return fmt.Errorf("Alert still exists")
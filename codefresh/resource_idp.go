@@ -2,25 +2,26 @@ package codefresh
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"log"
 	"strconv"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/idp"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceIdp() *schema.Resource {
 	return &schema.Resource{
-		Description: "Codefresh global level identity provider. Requires a Codefresh admin token and applies only to Codefresh on-premises installations.",
-		Create:      resourceIDPCreate,
-		Read:        resourceIDPRead,
-		Update:      resourceIDPUpdate,
-		Delete:      resourceIDPDelete,
+		Description:   "Codefresh global level identity provider. Requires a Codefresh admin token and applies only to Codefresh on-premises installations.",
+		CreateContext: resourceIDPCreate,
+		ReadContext:   resourceIDPRead,
+		UpdateContext: resourceIDPUpdate,
+		DeleteContext: resourceIDPDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -45,82 +46,83 @@ func resourceIdp() *schema.Resource {
 	}
 }
 
-func resourceIDPCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceIDPCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
-	id, err := client.CreateIDP(mapResourceToIDP(d), true)
+	id, err := client.CreateIDP(ctx, mapResourceToIDP(d), true)
 	if err != nil {
 		log.Printf("[DEBUG] Error while creating idp. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(id)
-	return resourceIDPRead(d, meta)
+	return resourceIDPRead(ctx, d, meta)
 }
 
-func resourceIDPRead(d *schema.ResourceData, meta interface{}) error {
+func resourceIDPRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 	idpID := d.Id()
 
 	var cfClientIDP *cfclient.IDP
 	var err error
 
-	cfClientIDP, err = client.GetIdpByID(idpID)
+	cfClientIDP, err = client.GetIdpByID(ctx, idpID)
 	if err != nil {
-		if err.Error() == fmt.Sprintf("[ERROR] IDP with ID %s isn't found.", d.Id()) {
+		if cfclient.IsNotFound(err) {
+			log.Printf("[WARN] IDP %s not found, removing it from state", idpID)
 			d.SetId("")
 			return nil
 		}
 		log.Printf("[DEBUG] Error while getting IDP. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 
 	}
 
 	err = mapIDPToResource(*cfClientIDP, d)
 	if err != nil {
 		log.Printf("[DEBUG] Error while getting mapping response to IDP object. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceIDPDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceIDPDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 	idpID := d.Id()
 
 	var cfClientIDP *cfclient.IDP
 	var err error
 
-	cfClientIDP, err = client.GetIdpByID(idpID)
+	cfClientIDP, err = client.GetIdpByID(ctx, idpID)
 	if err != nil {
 		log.Printf("[DEBUG] Error while getting IDP. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 	}
 
 	if len(cfClientIDP.Accounts) < 1 {
-		return errors.New("It is not allowed to delete IDPs without any assigned accounts as they are considered global. Assign at least one account before deleting")
+		return diag.Errorf("It is not allowed to delete IDPs without any assigned accounts as they are considered global. Assign at least one account before deleting")
 	}
 
-	err = client.DeleteIDP(d.Id())
+	err = client.DeleteIDP(ctx, d.Id())
 	if err != nil {
 		log.Printf("[DEBUG] Error while deleting IDP. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceIDPUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceIDPUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
-	err := client.UpdateIDP(mapResourceToIDP(d), true)
+	err := client.UpdateIDP(ctx, mapResourceToIDP(d), true)
 	if err != nil {
 		log.Printf("[DEBUG] Error while updating idp. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 	}
 
-	return resourceIDPRead(d, meta)
+	return resourceIDPRead(ctx, d, meta)
 }
 
 func mapIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) error {
@@ -167,12 +169,13 @@ func mapIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) error {
 			// Codefresh API Returns the client secret as an encrypted string on the server side
 			// hence we need to keep in the state the original secret the user provides along with the encrypted computed secret
 			// for Terraform to properly calculate the diff
-			"client_secret":      d.Get("github.0.client_secret"),
-			"authentication_url": cfClientIDP.AuthURL,
-			"token_url":          cfClientIDP.TokenURL,
-			"user_profile_url":   cfClientIDP.UserProfileURL,
-			"api_host":           cfClientIDP.ApiHost,
-			"api_path_prefix":    cfClientIDP.ApiPathPrefix,
+			"client_secret":            d.Get("github.0.client_secret"),
+			"client_secret_wo_version": d.Get("github.0.client_secret_wo_version"),
+			"authentication_url":       cfClientIDP.AuthURL,
+			"token_url":                cfClientIDP.TokenURL,
+			"user_profile_url":         cfClientIDP.UserProfileURL,
+			"api_host":                 cfClientIDP.ApiHost,
+			"api_path_prefix":          cfClientIDP.ApiPathPrefix,
 		}}
 
 		err = d.Set("github", attributes)
@@ -184,11 +187,12 @@ func mapIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) error {
 
 	if cfClientIDP.ClientType == idp.GitLab {
 		attributes := []map[string]interface{}{{
-			"client_id":          cfClientIDP.ClientId,
-			"client_secret":      d.Get("gitlab.0.client_secret"),
-			"authentication_url": cfClientIDP.AuthURL,
-			"user_profile_url":   cfClientIDP.UserProfileURL,
-			"api_url":            cfClientIDP.ApiURL,
+			"client_id":                cfClientIDP.ClientId,
+			"client_secret":            d.Get("gitlab.0.client_secret"),
+			"client_secret_wo_version": d.Get("gitlab.0.client_secret_wo_version"),
+			"authentication_url":       cfClientIDP.AuthURL,
+			"user_profile_url":         cfClientIDP.UserProfileURL,
+			"api_url":                  cfClientIDP.ApiURL,
 		}}
 
 		err = d.Set("gitlab", attributes)
@@ -200,12 +204,13 @@ func mapIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) error {
 
 	if cfClientIDP.ClientType == idp.Okta {
 		attributes := []map[string]interface{}{{
-			"client_id":            cfClientIDP.ClientId,
-			"client_secret":        d.Get("okta.0.client_secret"),
-			"client_host":          cfClientIDP.ClientHost,
-			"app_id":               d.Get("okta.0.app_id"),
-			"sync_mirror_accounts": cfClientIDP.SyncMirrorAccounts,
-			"access_token":         d.Get("okta.0.access_token"),
+			"client_id":                cfClientIDP.ClientId,
+			"client_secret":            d.Get("okta.0.client_secret"),
+			"client_secret_wo_version": d.Get("okta.0.client_secret_wo_version"),
+			"client_host":              cfClientIDP.ClientHost,
+			"app_id":                   d.Get("okta.0.app_id"),
+			"sync_mirror_accounts":     cfClientIDP.SyncMirrorAccounts,
+			"access_token":             d.Get("okta.0.access_token"),
 		}}
 
 		err = d.Set("okta", attributes)
@@ -217,12 +222,13 @@ func mapIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) error {
 
 	if cfClientIDP.ClientType == idp.Google {
 		attributes := []map[string]interface{}{{
-			"client_id":               cfClientIDP.ClientId,
-			"client_secret":           d.Get("google.0.client_secret"),
-			"admin_email":             d.Get("google.0.admin_email"),
-			"json_keyfile":            d.Get("google.0.json_keyfile"),
-			"allowed_groups_for_sync": cfClientIDP.AllowedGroupsForSync,
-			"sync_field":              cfClientIDP.SyncField,
+			"client_id":                cfClientIDP.ClientId,
+			"client_secret":            d.Get("google.0.client_secret"),
+			"client_secret_wo_version": d.Get("google.0.client_secret_wo_version"),
+			"admin_email":              d.Get("google.0.admin_email"),
+			"json_keyfile":             d.Get("google.0.json_keyfile"),
+			"allowed_groups_for_sync":  cfClientIDP.AllowedGroupsForSync,
+			"sync_field":               cfClientIDP.SyncField,
 		}}
 
 		err = d.Set("google", attributes)
@@ -234,9 +240,10 @@ func mapIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) error {
 
 	if cfClientIDP.ClientType == idp.Auth0 {
 		attributes := []map[string]interface{}{{
-			"client_id":     cfClientIDP.ClientId,
-			"client_secret": d.Get("auth0.0.client_secret"),
-			"domain":        cfClientIDP.ClientHost,
+			"client_id":                cfClientIDP.ClientId,
+			"client_secret":            d.Get("auth0.0.client_secret"),
+			"client_secret_wo_version": d.Get("auth0.0.client_secret_wo_version"),
+			"domain":                   cfClientIDP.ClientHost,
 		}}
 
 		err = d.Set("auth0", attributes)
@@ -256,6 +263,7 @@ func mapIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) error {
 		attributes := []map[string]interface{}{{
 			"app_id":                   cfClientIDP.ClientId,
 			"client_secret":            d.Get("azure.0.client_secret"),
+			"client_secret_wo_version": d.Get("azure.0.client_secret_wo_version"),
 			"object_id":                cfClientIDP.AppId,
 			"autosync_teams_and_users": cfClientIDP.AutoGroupSync,
 			"sync_interval":            syncInterval,
@@ -271,10 +279,11 @@ func mapIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) error {
 
 	if cfClientIDP.ClientType == idp.OneLogin {
 		attributes := []map[string]interface{}{{
-			"client_id":     cfClientIDP.ClientId,
-			"client_secret": d.Get("onelogin.0.client_secret"),
-			"domain":        cfClientIDP.ClientHost,
-			"api_client_id": cfClientIDP.ApiClientId,
+			"client_id":                cfClientIDP.ClientId,
+			"client_secret":            d.Get("onelogin.0.client_secret"),
+			"client_secret_wo_version": d.Get("onelogin.0.client_secret_wo_version"),
+			"domain":                   cfClientIDP.ClientHost,
+			"api_client_id":            cfClientIDP.ApiClientId,
 
 			"api_client_secret": cfClientIDP.ApiClientSecret,
 			"app_id":            cfClientIDP.AppId,
@@ -289,10 +298,11 @@ func mapIDPToResource(cfClientIDP cfclient.IDP, d *schema.ResourceData) error {
 
 	if cfClientIDP.ClientType == idp.Keycloak {
 		attributes := []map[string]interface{}{{
-			"client_id":     cfClientIDP.ClientId,
-			"client_secret": d.Get("keycloak.0.client_secret"),
-			"host":          cfClientIDP.Host,
-			"realm":         cfClientIDP.Realm,
+			"client_id":                cfClientIDP.ClientId,
+			"client_secret":            d.Get("keycloak.0.client_secret"),
+			"client_secret_wo_version": d.Get("keycloak.0.client_secret_wo_version"),
+			"host":                     cfClientIDP.Host,
+			"realm":                    cfClientIDP.Realm,
 		}}
 
 		err = d.Set("keycloak", attributes)
@@ -364,7 +374,7 @@ func mapResourceToIDP(d *schema.ResourceData) *cfclient.IDP {
 	if _, ok := d.GetOk(idp.GitHub); ok {
 		cfClientIDP.ClientType = idp.GitHub
 		cfClientIDP.ClientId = d.Get("github.0.client_id").(string)
-		cfClientIDP.ClientSecret = d.Get("github.0.client_secret").(string)
+		cfClientIDP.ClientSecret = schemautil.GetStringOrWriteOnly(d, "github.0.client_secret")
 		cfClientIDP.AuthURL = d.Get("github.0.authentication_url").(string)
 		cfClientIDP.TokenURL = d.Get("github.0.token_url").(string)
 		cfClientIDP.UserProfileURL = d.Get("github.0.user_profile_url").(string)
@@ -375,7 +385,7 @@ func mapResourceToIDP(d *schema.ResourceData) *cfclient.IDP {
 	if _, ok := d.GetOk(idp.GitLab); ok {
 		cfClientIDP.ClientType = idp.GitLab
 		cfClientIDP.ClientId = d.Get("gitlab.0.client_id").(string)
-		cfClientIDP.ClientSecret = d.Get("gitlab.0.client_secret").(string)
+		cfClientIDP.ClientSecret = schemautil.GetStringOrWriteOnly(d, "gitlab.0.client_secret")
 		cfClientIDP.AuthURL = d.Get("gitlab.0.authentication_url").(string)
 		cfClientIDP.UserProfileURL = d.Get("gitlab.0.user_profile_url").(string)
 		cfClientIDP.ApiURL = d.Get("gitlab.0.api_url").(string)
@@ -384,7 +394,7 @@ func mapResourceToIDP(d *schema.ResourceData) *cfclient.IDP {
 	if _, ok := d.GetOk(idp.Okta); ok {
 		cfClientIDP.ClientType = idp.Okta
 		cfClientIDP.ClientId = d.Get("okta.0.client_id").(string)
-		cfClientIDP.ClientSecret = d.Get("okta.0.client_secret").(string)
+		cfClientIDP.ClientSecret = schemautil.GetStringOrWriteOnly(d, "okta.0.client_secret")
 		cfClientIDP.ClientHost = d.Get("okta.0.client_host").(string)
 		cfClientIDP.AppId = d.Get("okta.0.app_id").(string)
 		cfClientIDP.SyncMirrorAccounts = datautil.ConvertStringArr(d.Get("okta.0.sync_mirror_accounts").([]interface{}))
@@ -394,7 +404,7 @@ func mapResourceToIDP(d *schema.ResourceData) *cfclient.IDP {
 	if _, ok := d.GetOk(idp.Google); ok {
 		cfClientIDP.ClientType = idp.Google
 		cfClientIDP.ClientId = d.Get("google.0.client_id").(string)
-		cfClientIDP.ClientSecret = d.Get("google.0.client_secret").(string)
+		cfClientIDP.ClientSecret = schemautil.GetStringOrWriteOnly(d, "google.0.client_secret")
 		cfClientIDP.KeyFile = d.Get("google.0.json_keyfile").(string)
 		cfClientIDP.Subject = d.Get("google.0.admin_email").(string)
 		cfClientIDP.AllowedGroupsForSync = d.Get("google.0.allowed_groups_for_sync").(string)
@@ -404,14 +414,14 @@ func mapResourceToIDP(d *schema.ResourceData) *cfclient.IDP {
 	if _, ok := d.GetOk(idp.Auth0); ok {
 		cfClientIDP.ClientType = idp.Auth0
 		cfClientIDP.ClientId = d.Get("auth0.0.client_id").(string)
-		cfClientIDP.ClientSecret = d.Get("auth0.0.client_secret").(string)
+		cfClientIDP.ClientSecret = schemautil.GetStringOrWriteOnly(d, "auth0.0.client_secret")
 		cfClientIDP.ClientHost = d.Get("auth0.0.domain").(string)
 	}
 
 	if _, ok := d.GetOk(idp.Azure); ok {
 		cfClientIDP.ClientType = idp.Azure
 		cfClientIDP.ClientId = d.Get("azure.0.app_id").(string)
-		cfClientIDP.ClientSecret = d.Get("azure.0.client_secret").(string)
+		cfClientIDP.ClientSecret = schemautil.GetStringOrWriteOnly(d, "azure.0.client_secret")
 		cfClientIDP.AppId = d.Get("azure.0.object_id").(string)
 		cfClientIDP.Tenant = d.Get("azure.0.tenant").(string)
 		cfClientIDP.AutoGroupSync = d.Get("azure.0.autosync_teams_and_users").(bool)
@@ -421,7 +431,7 @@ func mapResourceToIDP(d *schema.ResourceData) *cfclient.IDP {
 	if _, ok := d.GetOk(idp.OneLogin); ok {
 		cfClientIDP.ClientType = idp.OneLogin
 		cfClientIDP.ClientId = d.Get("onelogin.0.client_id").(string)
-		cfClientIDP.ClientSecret = d.Get("onelogin.0.client_secret").(string)
+		cfClientIDP.ClientSecret = schemautil.GetStringOrWriteOnly(d, "onelogin.0.client_secret")
 		cfClientIDP.ClientHost = d.Get("onelogin.0.domain").(string)
 		cfClientIDP.AppId = d.Get("onelogin.0.app_id").(string)
 		cfClientIDP.ApiClientId = d.Get("onelogin.0.api_client_id").(string)
@@ -431,7 +441,7 @@ func mapResourceToIDP(d *schema.ResourceData) *cfclient.IDP {
 	if _, ok := d.GetOk(idp.Keycloak); ok {
 		cfClientIDP.ClientType = idp.Keycloak
 		cfClientIDP.ClientId = d.Get("keycloak.0.client_id").(string)
-		cfClientIDP.ClientSecret = d.Get("keycloak.0.client_secret").(string)
+		cfClientIDP.ClientSecret = schemautil.GetStringOrWriteOnly(d, "keycloak.0.client_secret")
 		cfClientIDP.Host = d.Get("keycloak.0.host").(string)
 		cfClientIDP.Realm = d.Get("keycloak.0.realm").(string)
 	}
@@ -1,8 +1,13 @@
 package codefresh
 
 import (
+	"context"
+	"log"
+
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -12,10 +17,11 @@ func resourceIDPAccounts() *schema.Resource {
 This resource adds the list of provided account IDs to the IDP.
 Because of the current Codefresh API limitation it's impossible to remove account from IDP, thus deletion is not supported.
 		`,
-		Create: resourceIDPAccountsCreate,
-		Read:   resourceIDPAccountsRead,
-		Update: resourceIDPAccountsUpdate,
-		Delete: resourceIDPAccountsDelete,
+		CreateContext: resourceIDPAccountsCreate,
+		ReadContext:   resourceIDPAccountsRead,
+		UpdateContext: resourceIDPAccountsUpdate,
+		DeleteContext: resourceIDPAccountsDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -37,23 +43,23 @@ Because of the current Codefresh API limitation it's impossible to remove accoun
 	}
 }
 
-func resourceIDPAccountsCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceIDPAccountsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
 	accountIds := datautil.ConvertStringArr(d.Get("account_ids").(*schema.Set).List())
 
 	idpID := d.Get("idp_id").(string)
 
-	idp, err := client.GetIdpByID(idpID)
+	idp, err := client.GetIdpByID(ctx, idpID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	for _, accountID := range accountIds {
-		err = client.AddAccountToIDP(accountID, idp.ID)
+		err = client.AddAccountToIDP(ctx, accountID, idp.ID)
 
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
@@ -62,7 +68,7 @@ func resourceIDPAccountsCreate(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func resourceIDPAccountsRead(d *schema.ResourceData, meta interface{}) error {
+func resourceIDPAccountsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
 	idpID := d.Id()
@@ -71,38 +77,43 @@ func resourceIDPAccountsRead(d *schema.ResourceData, meta interface{}) error {
 		return nil
 	}
 
-	idp, err := client.GetIdpByID(idpID)
+	idp, err := client.GetIdpByID(ctx, idpID)
 	if err != nil {
-		return err
+		if cfclient.IsNotFound(err) {
+			log.Printf("[WARN] IDP %s not found, removing it from state", idpID)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 
 	err = d.Set("idp_id", idp.ID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	err = d.Set("account_ids", idp.Accounts)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceIDPAccountsDelete(_ *schema.ResourceData, _ interface{}) error {
+func resourceIDPAccountsDelete(ctx context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
 	// todo
 	// warning message
 	return nil
 }
 
-func resourceIDPAccountsUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceIDPAccountsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
 	idpID := d.Id()
 
-	idp, err := client.GetIdpByID(idpID)
+	idp, err := client.GetIdpByID(ctx, idpID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	existingAccounts := idp.Accounts
@@ -111,10 +122,10 @@ func resourceIDPAccountsUpdate(d *schema.ResourceData, meta interface{}) error {
 
 	for _, account := range desiredAccounts {
 		if ok := cfclient.FindInSlice(existingAccounts, account); !ok {
-			err := client.AddAccountToIDP(account, idp.ID)
+			err := client.AddAccountToIDP(ctx, account, idp.ID)
 
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 	}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -14,11 +16,12 @@ import (
 
 func resourcePermission() *schema.Resource {
 	return &schema.Resource{
-		Description: "Permissions are used to set up access control and define which teams have access to which clusters and pipelines based on tags.",
-		Create:      resourcePermissionCreate,
-		Read:        resourcePermissionRead,
-		Update:      resourcePermissionUpdate,
-		Delete:      resourcePermissionDelete,
+		Description:   "Permissions are used to set up access control and define which teams have access to which clusters and pipelines based on tags.",
+		CreateContext: resourcePermissionCreate,
+		ReadContext:   resourcePermissionRead,
+		UpdateContext: resourcePermissionUpdate,
+		DeleteContext: resourcePermissionDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -118,25 +121,25 @@ func resourcePermissionCustomDiff(ctx context.Context, diff *schema.ResourceDiff
 	return nil
 }
 
-func resourcePermissionCreate(d *schema.ResourceData, meta interface{}) error {
+func resourcePermissionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
 	permission := *mapResourceToPermission(d)
 
-	newPermission, err := client.CreatePermission(&permission)
+	newPermission, err := client.CreatePermission(ctx, &permission)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if newPermission == nil {
-		return fmt.Errorf("resourcePermissionCreate - failed to create permission, empty response")
+		return diag.Errorf("resourcePermissionCreate - failed to create permission, empty response")
 	}
 
 	d.SetId(newPermission.ID)
 
-	return resourcePermissionRead(d, meta)
+	return resourcePermissionRead(ctx, d, meta)
 }
 
-func resourcePermissionRead(d *schema.ResourceData, meta interface{}) error {
+func resourcePermissionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
@@ -146,55 +149,60 @@ func resourcePermissionRead(d *schema.ResourceData, meta interface{}) error {
 		return nil
 	}
 
-	permission, err := client.GetPermissionByID(permissionID)
+	permission, err := client.GetPermissionByID(ctx, permissionID)
 	if err != nil {
-		return err
+		if cfclient.IsNotFound(err) {
+			log.Printf("[WARN] Permission %s not found, removing it from state", permissionID)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 
 	err = mapPermissionToResource(permission, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourcePermissionUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourcePermissionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 	permission := *mapResourceToPermission(d)
 
 	// In case team, action or relatedResource or resource have changed - a new permission needs to be created (but without recreating the terraform resource as destruction of resources is alarming for end users)
 	if d.HasChanges("team", "action", "related_resource", "resource") {
-		deleteErr := resourcePermissionDelete(d, meta)
+		deleteErr := resourcePermissionDelete(ctx, d, meta)
 
 		if deleteErr != nil {
 			log.Printf("[WARN] failed to delete permission %v: %v", permission, deleteErr)
 		}
 
-		resp, err := client.CreatePermission(&permission)
+		resp, err := client.CreatePermission(ctx, &permission)
 
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		d.SetId(resp.ID)
 		// Only tags can be updated
 	} else if d.HasChange("tags") {
-		err := client.UpdatePermissionTags(&permission)
+		err := client.UpdatePermissionTags(ctx, &permission)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
-	return resourcePermissionRead(d, meta)
+	return resourcePermissionRead(ctx, d, meta)
 }
 
-func resourcePermissionDelete(d *schema.ResourceData, meta interface{}) error {
+func resourcePermissionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
-	err := client.DeletePermission(d.Id())
+	err := client.DeletePermission(ctx, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
@@ -73,7 +74,7 @@ func testAccCheckCodefreshPermissionExists(resource string) resource.TestCheckFu
 		permissionID := rs.Primary.ID
 
 		apiClient := testAccProvider.Meta().(*cfclient.Client)
-		_, err := apiClient.GetPermissionByID(permissionID)
+		_, err := apiClient.GetPermissionByID(context.Background(), permissionID)
 
 		if err != nil {
 			return fmt.Errorf("error fetching permission with ID %s. %s", permissionID, err)
@@ -1,16 +1,20 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/triggertypes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
@@ -22,12 +26,17 @@ func ptrBool(b bool) *bool {
 }
 
 func resourcePipeline() *schema.Resource {
-	return &schema.Resource{
-		Description: "The central component of the Codefresh Platform. Pipelines are workflows that contain individual steps. Each step is responsible for a specific action in the process.",
-		Create:      resourcePipelineCreate,
-		Read:        resourcePipelineRead,
-		Update:      resourcePipelineUpdate,
-		Delete:      resourcePipelineDelete,
+	pipelineResource := &schema.Resource{
+		Description:   "The central component of the Codefresh Platform. Pipelines are workflows that contain individual steps. Each step is responsible for a specific action in the process.",
+		CreateContext: resourcePipelineCreate,
+		ReadContext:   resourcePipelineRead,
+		UpdateContext: resourcePipelineUpdate,
+		DeleteContext: resourcePipelineDelete,
+		CustomizeDiff: customdiff.All(
+			resourcePipelineCheckBlocks,
+			resourcePipelineSummarizeYamlChanges,
+		),
+		Timeouts: schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -62,10 +71,28 @@ Or: <code>original_yaml_string = file("/path/to/my/codefresh.yml")</code>
 				Default:     false,
 			},
 			"revision": {
-				Description: "The pipeline's revision. Should be added to the **lifecycle/ignore_changes** or incremented mannually each update.",
+				Description: "The revision of the pipeline read into state, incremented by Codefresh on each update. Updates are sent with this revision, and Codefresh rejects them if the pipeline is no longer at this revision, so that changes made since outside of Terraform, e.g. in the Codefresh UI, are not overwritten; see `force_overwrite`.",
 				Type:        schema.TypeInt,
 				Computed:    true,
 			},
+			"yaml_change_summary": {
+				Description: "The changes of the last update to the YAML of the pipeline, i.e. `original_yaml_string` with the `spec.stage`, `spec.step` and `spec.hooks` blocks, and to `spec.variables`: the revision they apply to, then the steps, stages and variables added, removed, modified or reordered, and the other YAML fields modified, one per line. It is computed at plan time, so that the plan shows what changes rather than the whole YAML, and is empty when an update does not change the YAML.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"force_overwrite": {
+				Description: "Set to true to overwrite the pipeline even if it was modified since its `revision` was read, e.g. in the Codefresh UI. Otherwise such updates fail with a conflict error. Only the update that sets it to true overwrites the pipeline, the later updates are checked again: set it back to false, then to true, to overwrite the pipeline again (default: `false`).",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"encrypted_variables_fingerprints": encryptedVariablesFingerprintsSchema(),
+			"ignore_unmanaged_triggers": {
+				Description: "When true, the git triggers of the pipeline that are not declared in `spec.trigger`, e.g. those managed with `codefresh_pipeline_git_trigger`, are kept on update and not reported as drift (default: `false`).",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
 			"tags": {
 				Description: "A list of tags to mark a project for easy management and access control.",
 				Type:        schema.TypeSet,
@@ -157,6 +184,34 @@ Or: <code>original_yaml_string = file("/path/to/my/codefresh.yml")</code>
 								},
 							},
 						},
+						"stage": {
+							Description: "The stages of the pipeline, in order, as an alternative to declaring `stages` in `original_yaml_string`.",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem: &schema.Resource{
+								Schema: pipelineStageSchema(),
+							},
+							ConflictsWith: []string{"spec.0.spec_template"},
+						},
+						"step": {
+							Description: "The steps of the pipeline, in order, as an alternative to declaring `steps` in `original_yaml_string`. The steps are added to the YAML of the pipeline, which keeps the other fields of `original_yaml_string`, e.g. `hooks` or `mode`.",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem: &schema.Resource{
+								Schema: pipelineStepSchema(),
+							},
+							ConflictsWith: []string{"spec.0.spec_template"},
+						},
+						"hooks": {
+							Description: "The hooks of the pipeline, as an alternative to declaring `hooks` in `original_yaml_string`. The hooks are added to the YAML of the pipeline, along with its steps, whether they are declared in `original_yaml_string` or with `step` blocks.",
+							Type:        schema.TypeList,
+							Optional:    true,
+							MaxItems:    1,
+							Elem: &schema.Resource{
+								Schema: pipelineHooksSchema(),
+							},
+							ConflictsWith: []string{"spec.0.spec_template"},
+						},
 						"variables": {
 							Description: "The pipeline's variables.",
 							Type:        schema.TypeMap,
@@ -166,7 +221,7 @@ Or: <code>original_yaml_string = file("/path/to/my/codefresh.yml")</code>
 							},
 						},
 						"encrypted_variables": {
-							Description: "Pipeline level encrypted variables. Please note that drift will not be detected for encrypted variables",
+							Description: "Pipeline level encrypted variables. Their drift is detected with `encrypted_variables_fingerprints`.",
 							Type:        schema.TypeMap,
 							Optional:    true,
 							Elem: &schema.Schema{
@@ -179,196 +234,7 @@ Or: <code>original_yaml_string = file("/path/to/my/codefresh.yml")</code>
 							Type:        schema.TypeList,
 							Optional:    true,
 							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"name": {
-										Description: "The name of the trigger.",
-										Type:        schema.TypeString,
-										Optional:    true,
-									},
-									"description": {
-										Description: "The description of the trigger.",
-										Type:        schema.TypeString,
-										Optional:    true,
-									},
-									"type": {
-										Description: "The type of the trigger (default: `git`; see notes above).",
-										Type:        schema.TypeString,
-										Optional:    true,
-										Default:     "git",
-										ValidateDiagFunc: schemautil.StringMatchesRegExp(
-											"git",
-											schemautil.WithSummary("Invalid trigger type"),
-											schemautil.WithDetailFormat("The trigger type %s is invalid. The only supported type is %s."),
-										),
-									},
-									"repo": {
-										Description: "The repository name, (owner/repo)",
-										Type:        schema.TypeString,
-										Optional:    true,
-									},
-									"branch_regex": {
-										Description:      " A regular expression and will only trigger for branches that match this naming pattern (default: `/.*/gi`).",
-										Type:             schema.TypeString,
-										Optional:         true,
-										Default:          "/.*/gi",
-										ValidateDiagFunc: schemautil.StringIsValidRegExp(),
-									},
-									"branch_regex_input": {
-										Description:  "Flag to manage how the `branch_regex` field is interpreted. Possible values: `multiselect-exclude`, `multiselect`, `regex` (default: `regex`).",
-										Type:         schema.TypeString,
-										Optional:     true,
-										Default:      "regex",
-										ValidateFunc: validation.StringInSlice([]string{"multiselect-exclude", "multiselect", "regex"}, false),
-									},
-									"pull_request_target_branch_regex": {
-										Description:      "A regular expression and will only trigger for pull requests to branches that match this naming pattern.",
-										Type:             schema.TypeString,
-										Optional:         true,
-										ValidateDiagFunc: schemautil.StringIsValidRegExp(),
-									},
-									"comment_regex": {
-										Description:      " A regular expression and will only trigger for pull requests where a comment matches this naming pattern (default: `/.*/gi`).",
-										Type:             schema.TypeString,
-										Optional:         true,
-										Default:          "/.*/gi",
-										ValidateDiagFunc: schemautil.StringIsValidRegExp(),
-									},
-									"modified_files_glob": {
-										Description: "Allows to constrain the build and trigger it only if the modified files from the commit match this glob expression (default: `\"\"`).",
-										Type:        schema.TypeString,
-										Optional:    true,
-										Default:     "",
-									},
-									"events": {
-										Description: "A list of GitHub events for which a Pipeline is triggered.",
-										Type:        schema.TypeList,
-										Optional:    true,
-										Elem: &schema.Schema{
-											Type: schema.TypeString,
-										},
-									},
-									"provider": {
-										Description: "The git provider tied to the trigger.",
-										Type:        schema.TypeString,
-										Optional:    true,
-										Default:     "github",
-									},
-									"disabled": {
-										Description: "Flag to disable the trigger.",
-										Type:        schema.TypeBool,
-										Optional:    true,
-										Default:     false,
-									},
-									"options": {
-										Description: "The trigger's options.",
-										Type:        schema.TypeList,
-										Optional:    true,
-										Elem: &schema.Resource{
-											Schema: map[string]*schema.Schema{
-												"no_cache": {
-													Description: "If true, docker layer cache is disabled",
-													Type:        schema.TypeBool,
-													Optional:    true,
-													Default:     false,
-												},
-												"no_cf_cache": {
-													Description: "If true, extra Codefresh caching is disabled.",
-													Type:        schema.TypeBool,
-													Optional:    true,
-													Default:     false,
-												},
-												"reset_volume": {
-													Description: "If true, all files on volume will be deleted before each execution.",
-													Type:        schema.TypeBool,
-													Optional:    true,
-													Default:     false,
-												},
-												"enable_notifications": {
-													Description: "If false the pipeline will not send notifications to Slack and status updates back to the Git provider.",
-													Type:        schema.TypeBool,
-													Optional:    true,
-													Default:     false,
-												},
-											},
-										},
-									},
-									"pull_request_allow_fork_events": {
-										Description: "If this trigger is also applicable to git forks.",
-										Type:        schema.TypeBool,
-										Optional:    true,
-										Default:     false,
-									},
-									"commit_status_title": {
-										Description: "The commit status title pushed to the git provider.",
-										Type:        schema.TypeString,
-										Optional:    true,
-									},
-									"context": {
-										Description: "The Codefresh git context.",
-										Type:        schema.TypeString,
-										Optional:    true,
-										Default:     "github",
-									},
-									"contexts": {
-										Description: "A list of strings representing the contexts ([shared_configuration](https://codefresh.io/docs/docs/configure-ci-cd-pipeline/shared-configuration/)) to be loaded when the trigger is executed.",
-										Type:        schema.TypeList,
-										Optional:    true,
-										Elem: &schema.Schema{
-											Type: schema.TypeString,
-										},
-									},
-									"runtime_environment": {
-										Description: "The runtime environment for the trigger.",
-										Type:        schema.TypeList,
-										Optional:    true,
-										Elem: &schema.Resource{
-											Schema: map[string]*schema.Schema{
-												"name": {
-													Description: "The name of the runtime environment.",
-													Type:        schema.TypeString,
-													Optional:    true,
-												},
-												"memory": {
-													Description: "The memory allocated to the runtime environment.",
-													Type:        schema.TypeString,
-													Optional:    true,
-												},
-												"cpu": {
-													Description: "The CPU allocated to the runtime environment.",
-													Type:        schema.TypeString,
-													Optional:    true,
-												},
-												"dind_storage": {
-													Description: "The storage allocated to the runtime environment.",
-													Type:        schema.TypeString,
-													Optional:    true,
-												},
-												"required_available_storage": {
-													Description: "Minimum disk space required for build filesystem ( unit Gi is required).",
-													Type:        schema.TypeString,
-													Optional:    true,
-												},
-											},
-										},
-									},
-									"variables": {
-										Description: "Trigger variables.",
-										Type:        schema.TypeMap,
-										Optional:    true,
-										Elem: &schema.Schema{
-											Type: schema.TypeString,
-										},
-									},
-									"encrypted_variables": {
-										Description: "Trigger level encrypted variables. Please note that drift will not be detected for encrypted variables",
-										Type:        schema.TypeMap,
-										Optional:    true,
-										Elem: &schema.Schema{
-											Type:      schema.TypeString,
-											Sensitive: true,
-										},
-									},
-								},
+								Schema: gitTriggerSchema(),
 							},
 						},
 						"cron_trigger": {
@@ -500,7 +366,7 @@ Or: <code>original_yaml_string = file("/path/to/my/codefresh.yml")</code>
 										},
 									},
 									"encrypted_variables": {
-										Description: "Trigger level encrypted variables. Please note that drift will not be detected for encrypted variables",
+										Description: "Deprecated: these values are stored in state, use the write-only `spec.encrypted_variables_wo` instead. Trigger level encrypted variables. Their drift is detected with `encrypted_variables_fingerprints`.",
 										Type:        schema.TypeMap,
 										Optional:    true,
 										Elem: &schema.Schema{
@@ -712,28 +578,233 @@ Pipeline concurrency policy: Builds on 'Pending Approval' state should be:
 			},
 		},
 	}
+	// encrypted variables can be set without being stored in state, except in the inline triggers, which have no write-only alternative
+	spec := pipelineResource.Schema["spec"].Elem.(*schema.Resource).Schema
+	schemautil.AddWriteOnlyAttribute(spec, "spec.0.", "encrypted_variables")
+	spec["trigger"].Elem.(*schema.Resource).Schema["encrypted_variables"].Description = "Deprecated: these values are stored in state, use the write-only `encrypted_variables_wo` of the [codefresh_pipeline_git_trigger](https://registry.terraform.io/providers/codefresh-io/codefresh/latest/docs/resources/pipeline_git_trigger) resource instead. Trigger level encrypted variables. Their drift is detected with `encrypted_variables_fingerprints`."
+	return pipelineResource
+}
+
+// gitTriggerSchema returns the attributes of a git trigger, shared by the spec of codefresh_pipeline and codefresh_pipeline_git_trigger
+func gitTriggerSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Description: "The name of the trigger.",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"description": {
+			Description: "The description of the trigger.",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"type": {
+			Description: "The type of the trigger (default: `git`; see notes above).",
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "git",
+			ValidateDiagFunc: schemautil.StringMatchesRegExp(
+				"git",
+				schemautil.WithSummary("Invalid trigger type"),
+				schemautil.WithDetailFormat("The trigger type %s is invalid. The only supported type is %s."),
+			),
+		},
+		"repo": {
+			Description: "The repository name, (owner/repo)",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"branch_regex": {
+			Description:      " A regular expression and will only trigger for branches that match this naming pattern (default: `/.*/gi`).",
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "/.*/gi",
+			ValidateDiagFunc: schemautil.StringIsValidRegExp(),
+		},
+		"branch_regex_input": {
+			Description:  "Flag to manage how the `branch_regex` field is interpreted. Possible values: `multiselect-exclude`, `multiselect`, `regex` (default: `regex`).",
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "regex",
+			ValidateFunc: validation.StringInSlice([]string{"multiselect-exclude", "multiselect", "regex"}, false),
+		},
+		"pull_request_target_branch_regex": {
+			Description:      "A regular expression and will only trigger for pull requests to branches that match this naming pattern.",
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateDiagFunc: schemautil.StringIsValidRegExp(),
+		},
+		"comment_regex": {
+			Description:      " A regular expression and will only trigger for pull requests where a comment matches this naming pattern (default: `/.*/gi`).",
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "/.*/gi",
+			ValidateDiagFunc: schemautil.StringIsValidRegExp(),
+		},
+		"modified_files_glob": {
+			Description: "Allows to constrain the build and trigger it only if the modified files from the commit match this glob expression (default: `\"\"`).",
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+		},
+		"events": {
+			Description: "A list of GitHub events for which a Pipeline is triggered, see the `git_events` of the `codefresh_trigger_types` data source.",
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem: &schema.Schema{
+				Type:             schema.TypeString,
+				ValidateDiagFunc: triggertypes.GitEventValidator(),
+			},
+		},
+		"provider": {
+			Description: "The git provider tied to the trigger.",
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "github",
+		},
+		"disabled": {
+			Description: "Flag to disable the trigger.",
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+		},
+		"options": {
+			Description: "The trigger's options.",
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"no_cache": {
+						Description: "If true, docker layer cache is disabled",
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+					},
+					"no_cf_cache": {
+						Description: "If true, extra Codefresh caching is disabled.",
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+					},
+					"reset_volume": {
+						Description: "If true, all files on volume will be deleted before each execution.",
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+					},
+					"enable_notifications": {
+						Description: "If false the pipeline will not send notifications to Slack and status updates back to the Git provider.",
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+					},
+				},
+			},
+		},
+		"pull_request_allow_fork_events": {
+			Description: "If this trigger is also applicable to git forks.",
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+		},
+		"commit_status_title": {
+			Description: "The commit status title pushed to the git provider.",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"context": {
+			Description: "The Codefresh git context.",
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "github",
+		},
+		"contexts": {
+			Description: "A list of strings representing the contexts ([shared_configuration](https://codefresh.io/docs/docs/configure-ci-cd-pipeline/shared-configuration/)) to be loaded when the trigger is executed.",
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"runtime_environment": {
+			Description: "The runtime environment for the trigger.",
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Description: "The name of the runtime environment.",
+						Type:        schema.TypeString,
+						Optional:    true,
+					},
+					"memory": {
+						Description: "The memory allocated to the runtime environment.",
+						Type:        schema.TypeString,
+						Optional:    true,
+					},
+					"cpu": {
+						Description: "The CPU allocated to the runtime environment.",
+						Type:        schema.TypeString,
+						Optional:    true,
+					},
+					"dind_storage": {
+						Description: "The storage allocated to the runtime environment.",
+						Type:        schema.TypeString,
+						Optional:    true,
+					},
+					"required_available_storage": {
+						Description: "Minimum disk space required for build filesystem ( unit Gi is required).",
+						Type:        schema.TypeString,
+						Optional:    true,
+					},
+				},
+			},
+		},
+		"variables": {
+			Description: "Trigger variables.",
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"encrypted_variables": {
+			Description: "Trigger level encrypted variables. Their drift is detected with `encrypted_variables_fingerprints`.",
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Elem: &schema.Schema{
+				Type:      schema.TypeString,
+				Sensitive: true,
+			},
+		},
+	}
 }
 
-func resourcePipelineCreate(d *schema.ResourceData, meta interface{}) error {
+func resourcePipelineCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
 	pipeline, err := mapResourceToPipeline(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	resp, err := client.CreatePipeline(pipeline)
+	resp, err := client.CreatePipeline(ctx, pipeline)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(resp.Metadata.ID)
 
-	return resourcePipelineRead(d, meta)
+	err = setPipelineEncryptedVariablesFingerprints(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePipelineRead(ctx, d, meta)
 }
 
-func resourcePipelineRead(d *schema.ResourceData, meta interface{}) error {
+func resourcePipelineRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
@@ -744,45 +815,127 @@ func resourcePipelineRead(d *schema.ResourceData, meta interface{}) error {
 		return nil
 	}
 
-	pipeline, err := client.GetPipeline(pipelineID)
+	pipeline, err := client.GetPipelineDecrypted(ctx, pipelineID)
 	if err != nil {
-		return err
+		if cfclient.IsNotFound(err) {
+			log.Printf("[WARN] Pipeline %s not found, removing it from state", pipelineID)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	if d.Get("ignore_unmanaged_triggers").(bool) {
+		pipeline.Spec.Triggers = managedTriggers(pipeline.Spec.Triggers, d)
 	}
 
 	err = mapPipelineToResource(*pipeline, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourcePipelineUpdate(d *schema.ResourceData, meta interface{}) error {
+// pipelineRevisionConflict returns the error of an update planned on a revision of the pipeline that is no longer the current one
+func pipelineRevisionConflict(name string, expected, current int) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("Pipeline %s was modified outside of Terraform", name),
+		Detail: fmt.Sprintf("The update was planned on revision %d of the pipeline, but its current revision is %d. "+
+			"Run terraform plan again to review the changes made since, or set force_overwrite = true to overwrite them.", expected, current),
+	}}
+}
+
+// managedTriggers returns the triggers declared in the spec.trigger attribute of d
+func managedTriggers(triggers []cfclient.Trigger, d *schema.ResourceData) []cfclient.Trigger {
+	declared := map[string]bool{}
+	for idx := range d.Get("spec.0.trigger").([]interface{}) {
+		declared[d.Get(fmt.Sprintf("spec.0.trigger.%v.name", idx)).(string)] = true
+	}
+	var res []cfclient.Trigger
+	for _, trigger := range triggers {
+		if declared[trigger.Name] {
+			res = append(res, trigger)
+		}
+	}
+	return res
+}
+
+// unmanagedTriggers returns the triggers that have no declared trigger with the same name
+func unmanagedTriggers(triggers []cfclient.Trigger, declared []cfclient.Trigger) []cfclient.Trigger {
+	var res []cfclient.Trigger
+	for _, trigger := range triggers {
+		if !slices.ContainsFunc(declared, func(declaredTrigger cfclient.Trigger) bool { return declaredTrigger.Name == trigger.Name }) {
+			res = append(res, trigger)
+		}
+	}
+	return res
+}
+
+func resourcePipelineUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
 	pipeline, err := mapResourceToPipeline(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	pipeline.Metadata.ID = d.Id()
 
-	_, err = client.UpdatePipeline(pipeline)
+	pipelineMutexKV.Lock(d.Id())
+	defer pipelineMutexKV.Unlock(d.Id())
+
+	// The update is only applied by Codefresh to the revision read into state, so that the changes made since are not overwritten.
+	// force_overwrite only skips this check for the update that sets it, later updates are checked again.
+	forceOverwrite := d.Get("force_overwrite").(bool) && d.HasChange("force_overwrite")
+	if forceOverwrite {
+		pipeline.Metadata.Revision = 0
+	}
+
+	if d.Get("ignore_unmanaged_triggers").(bool) {
+		// the unmanaged triggers are sent back with the decrypted value of their encrypted variables
+		current, err := client.GetPipelineDecrypted(ctx, d.Id())
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		unmanaged := unmanagedTriggers(current.Spec.Triggers, pipeline.Spec.Triggers)
+		for _, trigger := range unmanaged {
+			if trigger.HasMaskedVariables() {
+				return diag.Errorf("cannot keep the unmanaged trigger %s of pipeline %s: the account forbids decrypting variables, and its encrypted variables would be overwritten with their masked value", trigger.Name, pipeline.Metadata.Name)
+			}
+		}
+		pipeline.Spec.Triggers = append(pipeline.Spec.Triggers, unmanaged...)
+	}
+
+	_, err = client.UpdatePipeline(ctx, pipeline)
 	if err != nil {
-		return err
+		if cfclient.IsConflict(err) && !forceOverwrite {
+			current, getErr := client.GetPipeline(ctx, d.Id())
+			if getErr != nil {
+				return diag.FromErr(err)
+			}
+			return pipelineRevisionConflict(pipeline.Metadata.Name, pipeline.Metadata.Revision, current.Metadata.Revision)
+		}
+		return diag.FromErr(err)
 	}
 
-	return resourcePipelineRead(d, meta)
+	err = setPipelineEncryptedVariablesFingerprints(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePipelineRead(ctx, d, meta)
 }
 
-func resourcePipelineDelete(d *schema.ResourceData, meta interface{}) error {
+func resourcePipelineDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
-	err := client.DeletePipeline(d.Id())
+	err := client.DeletePipeline(ctx, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -812,55 +965,56 @@ func mapPipelineToResource(pipeline cfclient.Pipeline, d *schema.ResourceData) e
 
 	flattenedSpec := flattenSpec(pipeline.Spec)
 
-	// Set encrypted variables from resource data, as otherwise they cause constant diff as the value is always returned as *****
-	encryptedVariables, ok := flattenedSpec[0]["encrypted_variables"].(map[string]string)
-
-	if ok {
-		if len(encryptedVariables) > 0 {
-			err := setEncryptedVariablesValuesFromResource(d, encryptedVariables, "spec.0.encrypted_variables")
+	// Set encrypted variables from resource data, as otherwise they cause constant diff as the value is always returned as *****,
+	// unless their decrypted value shows that they were changed outside of Terraform
+	fingerprints := newEncryptedVariablesFingerprints(d)
+	if schemautil.UsesWriteOnly(d, "spec.0.encrypted_variables") {
+		flattenedSpec[0]["encrypted_variables"] = map[string]string{}
+		flattenedSpec[0]["encrypted_variables_wo_version"] = d.Get("spec.0.encrypted_variables_wo_version")
+	} else if encryptedVariables, ok := flattenedSpec[0]["encrypted_variables"].(map[string]string); ok {
+		fingerprints.read(d, "", "spec.0.encrypted_variables", encryptedVariables)
+	}
 
-			if err != nil {
-				return err
+	for _, kind := range []string{"trigger", "cron_trigger"} {
+		triggers, _ := flattenedSpec[0][kind].([]map[string]interface{})
+		for triggerIndex, triggerSpec := range triggers {
+			if triggerEncryptedVariables, ok := triggerSpec["encrypted_variables"].(map[string]string); ok {
+				fingerprints.read(d, fmt.Sprintf("%s/%s/", kind, triggerSpec["name"]), fmt.Sprintf("spec.0.%s.%d.encrypted_variables", kind, triggerIndex), triggerEncryptedVariables)
 			}
 		}
 	}
 
-	// Set trigger encrypted variables from resource data
-	triggers, getTriggersOK := flattenedSpec[0]["trigger"]
-
-	if getTriggersOK {
-		for triggerIndex, triggerSpec := range triggers.([]map[string]interface{}) {
+	err = fingerprints.set(d)
+	if err != nil {
+		return err
+	}
 
-			triggerEncryptedVariables, ok := triggerSpec["encrypted_variables"].(map[string]string)
+	originalYamlString := pipeline.Metadata.OriginalYamlString
 
-			if ok {
-				if len(triggerEncryptedVariables) > 0 {
-					err := setEncryptedVariablesValuesFromResource(d, triggerEncryptedVariables, fmt.Sprintf("spec.0.trigger.%d.encrypted_variables", triggerIndex))
+	// Stages, steps and hooks declared with blocks are read back from the spec, and original_yaml_string keeps
+	// its configured value as long as the YAML of the pipeline is the one generated from it and the blocks
+	if blocks := pipelineYamlBlocksFrom(d); !blocks.isEmpty() {
+		generatedYamlString, err := buildPipelineYaml(d.Get("original_yaml_string").(string), blocks)
+		if err == nil && generatedYamlString == originalYamlString {
+			originalYamlString = d.Get("original_yaml_string").(string)
+		}
 
-					if err != nil {
-						return err
-					}
-				}
+		if len(blocks.stages) > 0 {
+			flattenedSpec[0]["stage"], err = flattenPipelineStages(pipeline.Spec.Stages)
+			if err != nil {
+				return err
 			}
 		}
-	}
-
-	// Set cron trigger encrypted variables from resource data
-	cronTriggers, getCronTriggersOK := flattenedSpec[0]["cron_trigger"]
-
-	if getCronTriggersOK {
-		for triggerIndex, triggerSpec := range cronTriggers.([]map[string]interface{}) {
-
-			triggerEncryptedVariables, ok := triggerSpec["encrypted_variables"].(map[string]string)
-
-			if ok {
-				if len(triggerEncryptedVariables) > 0 {
-					err := setEncryptedVariablesValuesFromResource(d, triggerEncryptedVariables, fmt.Sprintf("spec.0.cron_trigger.%d.encrypted_variables", triggerIndex))
-
-					if err != nil {
-						return err
-					}
-				}
+		if len(blocks.steps) > 0 {
+			flattenedSpec[0]["step"], err = flattenPipelineSteps(pipeline.Spec.Steps)
+			if err != nil {
+				return err
+			}
+		}
+		if len(blocks.hooks) > 0 {
+			flattenedSpec[0]["hooks"], err = flattenPipelineHooks(pipeline.Spec.Hooks)
+			if err != nil {
+				return err
 			}
 		}
 	}
@@ -876,7 +1030,7 @@ func mapPipelineToResource(pipeline cfclient.Pipeline, d *schema.ResourceData) e
 		return err
 	}
 
-	err = d.Set("original_yaml_string", pipeline.Metadata.OriginalYamlString)
+	err = d.Set("original_yaml_string", originalYamlString)
 	if err != nil {
 		return err
 	}
@@ -1028,35 +1182,39 @@ func flattenTriggerOptions(options cfclient.TriggerOptions) []map[string]interfa
 func flattenTriggers(triggers []cfclient.Trigger) []map[string]interface{} {
 	var res = make([]map[string]interface{}, len(triggers))
 	for i, trigger := range triggers {
-		m := make(map[string]interface{})
-		m["name"] = trigger.Name
-		m["description"] = trigger.Description
-		m["context"] = trigger.Context
-		m["contexts"] = trigger.Contexts
-		m["repo"] = trigger.Repo
-		m["branch_regex"] = trigger.BranchRegex
-		m["branch_regex_input"] = trigger.BranchRegexInput
-		m["pull_request_target_branch_regex"] = trigger.PullRequestTargetBranchRegex
-		m["comment_regex"] = trigger.CommentRegex
-		m["modified_files_glob"] = trigger.ModifiedFilesGlob
-		m["disabled"] = trigger.Disabled
-		if trigger.Options != nil {
-			m["options"] = flattenTriggerOptions(*trigger.Options)
-		}
-		m["pull_request_allow_fork_events"] = trigger.PullRequestAllowForkEvents
-		m["commit_status_title"] = trigger.CommitStatusTitle
-		m["provider"] = trigger.Provider
-		m["type"] = trigger.Type
-		m["events"] = trigger.Events
-		m["variables"], m["encrypted_variables"] = datautil.ConvertVariables(trigger.Variables)
-		if trigger.RuntimeEnvironment != nil {
-			m["runtime_environment"] = flattenSpecRuntimeEnvironment(*trigger.RuntimeEnvironment)
-		}
-		res[i] = m
+		res[i] = flattenGitTrigger(trigger)
 	}
 	return res
 }
 
+func flattenGitTrigger(trigger cfclient.Trigger) map[string]interface{} {
+	m := make(map[string]interface{})
+	m["name"] = trigger.Name
+	m["description"] = trigger.Description
+	m["context"] = trigger.Context
+	m["contexts"] = trigger.Contexts
+	m["repo"] = trigger.Repo
+	m["branch_regex"] = trigger.BranchRegex
+	m["branch_regex_input"] = trigger.BranchRegexInput
+	m["pull_request_target_branch_regex"] = trigger.PullRequestTargetBranchRegex
+	m["comment_regex"] = trigger.CommentRegex
+	m["modified_files_glob"] = trigger.ModifiedFilesGlob
+	m["disabled"] = trigger.Disabled
+	if trigger.Options != nil {
+		m["options"] = flattenTriggerOptions(*trigger.Options)
+	}
+	m["pull_request_allow_fork_events"] = trigger.PullRequestAllowForkEvents
+	m["commit_status_title"] = trigger.CommitStatusTitle
+	m["provider"] = trigger.Provider
+	m["type"] = trigger.Type
+	m["events"] = trigger.Events
+	m["variables"], m["encrypted_variables"] = datautil.ConvertVariables(trigger.Variables)
+	if trigger.RuntimeEnvironment != nil {
+		m["runtime_environment"] = flattenSpecRuntimeEnvironment(*trigger.RuntimeEnvironment)
+	}
+	return m
+}
+
 func flattenCronTriggers(cronTriggers []cfclient.CronTrigger) []map[string]interface{} {
 	var res = make([]map[string]interface{}, len(cronTriggers))
 	for i, trigger := range cronTriggers {
@@ -1108,6 +1266,10 @@ func mapResourceToPipeline(d *schema.ResourceData) (*cfclient.Pipeline, error) {
 		"\n",
 		"\n",
 		-1)
+	originalYamlString, err := buildPipelineYaml(originalYamlString, pipelineYamlBlocksFrom(d))
+	if err != nil {
+		return nil, err
+	}
 	pipeline := &cfclient.Pipeline{
 		Metadata: cfclient.Metadata{
 			Name:      d.Get("name").(string),
@@ -1178,58 +1340,13 @@ func mapResourceToPipeline(d *schema.ResourceData) (*cfclient.Pipeline, error) {
 		pipeline.SetVariables(variables.(map[string]interface{}), false)
 	}
 
-	if encryptedVariables, ok := d.GetOk("spec.0.encrypted_variables"); ok {
-		pipeline.SetVariables(encryptedVariables.(map[string]interface{}), true)
+	if encryptedVariables := schemautil.GetMapOrWriteOnly(d, "spec.0.encrypted_variables"); len(encryptedVariables) > 0 {
+		pipeline.SetVariables(encryptedVariables, true)
 	}
 
 	if triggers, ok := d.GetOk("spec.0.trigger"); ok {
 		for idx := range triggers.([]interface{}) {
-			events := d.Get(fmt.Sprintf("spec.0.trigger.%v.events", idx)).([]interface{})
-			contexts := d.Get(fmt.Sprintf("spec.0.trigger.%v.contexts", idx)).([]interface{})
-			codefreshTrigger := cfclient.Trigger{
-				Name:                         d.Get(fmt.Sprintf("spec.0.trigger.%v.name", idx)).(string),
-				Description:                  d.Get(fmt.Sprintf("spec.0.trigger.%v.description", idx)).(string),
-				Type:                         d.Get(fmt.Sprintf("spec.0.trigger.%v.type", idx)).(string),
-				Repo:                         d.Get(fmt.Sprintf("spec.0.trigger.%v.repo", idx)).(string),
-				BranchRegex:                  d.Get(fmt.Sprintf("spec.0.trigger.%v.branch_regex", idx)).(string),
-				BranchRegexInput:             d.Get(fmt.Sprintf("spec.0.trigger.%v.branch_regex_input", idx)).(string),
-				PullRequestTargetBranchRegex: d.Get(fmt.Sprintf("spec.0.trigger.%v.pull_request_target_branch_regex", idx)).(string),
-				CommentRegex:                 d.Get(fmt.Sprintf("spec.0.trigger.%v.comment_regex", idx)).(string),
-				ModifiedFilesGlob:            d.Get(fmt.Sprintf("spec.0.trigger.%v.modified_files_glob", idx)).(string),
-				Provider:                     d.Get(fmt.Sprintf("spec.0.trigger.%v.provider", idx)).(string),
-				Disabled:                     d.Get(fmt.Sprintf("spec.0.trigger.%v.disabled", idx)).(bool),
-				PullRequestAllowForkEvents:   d.Get(fmt.Sprintf("spec.0.trigger.%v.pull_request_allow_fork_events", idx)).(bool),
-				CommitStatusTitle:            d.Get(fmt.Sprintf("spec.0.trigger.%v.commit_status_title", idx)).(string),
-				Context:                      d.Get(fmt.Sprintf("spec.0.trigger.%v.context", idx)).(string),
-				Contexts:                     datautil.ConvertStringArr(contexts),
-				Events:                       datautil.ConvertStringArr(events),
-			}
-			variables := d.Get(fmt.Sprintf("spec.0.trigger.%v.variables", idx)).(map[string]interface{})
-			codefreshTrigger.SetVariables(variables, false)
-
-			encryptedVariables := d.Get(fmt.Sprintf("spec.0.trigger.%v.encrypted_variables", idx)).(map[string]interface{})
-			codefreshTrigger.SetVariables(encryptedVariables, true)
-
-			if _, ok := d.GetOk(fmt.Sprintf("spec.0.trigger.%v.options", idx)); ok {
-				options := cfclient.TriggerOptions{
-					NoCache:             d.Get(fmt.Sprintf("spec.0.trigger.%v.options.0.no_cache", idx)).(bool),
-					NoCfCache:           d.Get(fmt.Sprintf("spec.0.trigger.%v.options.0.no_cf_cache", idx)).(bool),
-					ResetVolume:         d.Get(fmt.Sprintf("spec.0.trigger.%v.options.0.reset_volume", idx)).(bool),
-					EnableNotifications: d.Get(fmt.Sprintf("spec.0.trigger.%v.options.0.enable_notifications", idx)).(bool),
-				}
-				codefreshTrigger.Options = &options
-			}
-			if _, ok := d.GetOk(fmt.Sprintf("spec.0.trigger.%v.runtime_environment", idx)); ok {
-				triggerRuntime := cfclient.RuntimeEnvironment{
-					Name:                     d.Get(fmt.Sprintf("spec.0.trigger.%v.runtime_environment.0.name", idx)).(string),
-					Memory:                   d.Get(fmt.Sprintf("spec.0.trigger.%v.runtime_environment.0.memory", idx)).(string),
-					CPU:                      d.Get(fmt.Sprintf("spec.0.trigger.%v.runtime_environment.0.cpu", idx)).(string),
-					DindStorage:              d.Get(fmt.Sprintf("spec.0.trigger.%v.runtime_environment.0.dind_storage", idx)).(string),
-					RequiredAvailableStorage: d.Get(fmt.Sprintf("spec.0.trigger.%v.runtime_environment.0.required_available_storage", idx)).(string),
-				}
-				codefreshTrigger.RuntimeEnvironment = &triggerRuntime
-			}
-			pipeline.Spec.Triggers = append(pipeline.Spec.Triggers, codefreshTrigger)
+			pipeline.Spec.Triggers = append(pipeline.Spec.Triggers, expandGitTrigger(d, fmt.Sprintf("spec.0.trigger.%v.", idx)))
 		}
 	}
 
@@ -1326,6 +1443,61 @@ func mapResourceToPipeline(d *schema.ResourceData) (*cfclient.Pipeline, error) {
 	return pipeline, nil
 }
 
+// expandGitTrigger maps the git trigger attributes found under prefix, e.g. "spec.0.trigger.0.", to a trigger
+func expandGitTrigger(d *schema.ResourceData, prefix string) cfclient.Trigger {
+	// provider is a reserved attribute name at the top level of a resource, where it is named git_provider instead
+	providerAttribute := prefix + "provider"
+	if prefix == "" {
+		providerAttribute = "git_provider"
+	}
+	events := d.Get(prefix + "events").([]interface{})
+	contexts := d.Get(prefix + "contexts").([]interface{})
+	codefreshTrigger := cfclient.Trigger{
+		Name:                         d.Get(prefix + "name").(string),
+		Description:                  d.Get(prefix + "description").(string),
+		Type:                         d.Get(prefix + "type").(string),
+		Repo:                         d.Get(prefix + "repo").(string),
+		BranchRegex:                  d.Get(prefix + "branch_regex").(string),
+		BranchRegexInput:             d.Get(prefix + "branch_regex_input").(string),
+		PullRequestTargetBranchRegex: d.Get(prefix + "pull_request_target_branch_regex").(string),
+		CommentRegex:                 d.Get(prefix + "comment_regex").(string),
+		ModifiedFilesGlob:            d.Get(prefix + "modified_files_glob").(string),
+		Provider:                     d.Get(providerAttribute).(string),
+		Disabled:                     d.Get(prefix + "disabled").(bool),
+		PullRequestAllowForkEvents:   d.Get(prefix + "pull_request_allow_fork_events").(bool),
+		CommitStatusTitle:            d.Get(prefix + "commit_status_title").(string),
+		Context:                      d.Get(prefix + "context").(string),
+		Contexts:                     datautil.ConvertStringArr(contexts),
+		Events:                       datautil.ConvertStringArr(events),
+	}
+	variables := d.Get(prefix + "variables").(map[string]interface{})
+	codefreshTrigger.SetVariables(variables, false)
+
+	encryptedVariables := schemautil.GetMapOrWriteOnly(d, prefix+"encrypted_variables")
+	codefreshTrigger.SetVariables(encryptedVariables, true)
+
+	if _, ok := d.GetOk(prefix + "options"); ok {
+		options := cfclient.TriggerOptions{
+			NoCache:             d.Get(prefix + "options.0.no_cache").(bool),
+			NoCfCache:           d.Get(prefix + "options.0.no_cf_cache").(bool),
+			ResetVolume:         d.Get(prefix + "options.0.reset_volume").(bool),
+			EnableNotifications: d.Get(prefix + "options.0.enable_notifications").(bool),
+		}
+		codefreshTrigger.Options = &options
+	}
+	if _, ok := d.GetOk(prefix + "runtime_environment"); ok {
+		triggerRuntime := cfclient.RuntimeEnvironment{
+			Name:                     d.Get(prefix + "runtime_environment.0.name").(string),
+			Memory:                   d.Get(prefix + "runtime_environment.0.memory").(string),
+			CPU:                      d.Get(prefix + "runtime_environment.0.cpu").(string),
+			DindStorage:              d.Get(prefix + "runtime_environment.0.dind_storage").(string),
+			RequiredAvailableStorage: d.Get(prefix + "runtime_environment.0.required_available_storage").(string),
+		}
+		codefreshTrigger.RuntimeEnvironment = &triggerRuntime
+	}
+	return codefreshTrigger
+}
+
 // This function is used to extract the spec attributes from the original_yaml_string attribute.
 // Typically, unmarshalling the YAML string is problematic because the order of the attributes is not preserved.
 // Namely, we care a lot about the order of the steps and stages attributes.
@@ -1399,14 +1571,15 @@ func convertOnCreateBranchAttributeToPipelineFormat(src string) string {
 	})
 }
 
-func setEncryptedVariablesValuesFromResource(d *schema.ResourceData, flattenedVariables map[string]string, schemaPath string) error {
-
-	if len(flattenedVariables) > 0 {
-		// Iterate over variables and set the value from resource data
-		for k := range flattenedVariables {
-			flattenedVariables[k] = d.Get(fmt.Sprintf("%s.%s", schemaPath, k)).(string)
+// setPipelineEncryptedVariablesFingerprints records the fingerprints of the encrypted variables of the pipeline and of its triggers that were applied
+func setPipelineEncryptedVariablesFingerprints(d *schema.ResourceData) error {
+	fingerprints := newEncryptedVariablesFingerprints(d)
+	fingerprints.apply("", d.Get("spec.0.encrypted_variables").(map[string]interface{}))
+	for _, kind := range []string{"trigger", "cron_trigger"} {
+		for idx := range d.Get(fmt.Sprintf("spec.0.%s", kind)).([]interface{}) {
+			prefix := fmt.Sprintf("%s/%s/", kind, d.Get(fmt.Sprintf("spec.0.%s.%d.name", kind, idx)))
+			fingerprints.apply(prefix, d.Get(fmt.Sprintf("spec.0.%s.%d.encrypted_variables", kind, idx)).(map[string]interface{}))
 		}
 	}
-
-	return nil
+	return fingerprints.set(d)
 }
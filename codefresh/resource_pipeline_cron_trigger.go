@@ -3,6 +3,7 @@ package codefresh
 import (
 	"context"
 	"fmt"
+	"log"
 	"regexp"
 	"strings"
 
@@ -18,12 +19,13 @@ func resourcePipelineCronTrigger() *schema.Resource {
 	return &schema.Resource{
 		DeprecationMessage: "This resource is deprecated and will be removed in a future version of the Codefresh Terraform provider. Please use the cron_triggers attribute of the codefresh_pipeline resource instead.",
 		Description:        "This resource is used to create cron-based triggers for pipeilnes.",
-		Create:             resourcePipelineCronTriggerCreate,
-		Read:               resourcePipelineCronTriggerRead,
-		Update:             resourcePipelineCronTriggerUpdate,
-		Delete:             resourcePipelineCronTriggerDelete,
+		CreateContext:      resourcePipelineCronTriggerCreate,
+		ReadContext:        resourcePipelineCronTriggerRead,
+		UpdateContext:      resourcePipelineCronTriggerUpdate,
+		DeleteContext:      resourcePipelineCronTriggerDelete,
+		Timeouts:           schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				idParts := strings.Split(d.Id(), ",")
 
 				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
@@ -82,10 +84,10 @@ func resourcePipelineCronTrigger() *schema.Resource {
 	}
 }
 
-func resourcePipelineCronTriggerCreate(d *schema.ResourceData, meta interface{}) error {
+func resourcePipelineCronTriggerCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
-	eventString, err := client.CreateHermesTriggerEvent(&cfclient.HermesTriggerEvent{
+	eventString, err := client.CreateHermesTriggerEvent(ctx, &cfclient.HermesTriggerEvent{
 		Type:   "cron",
 		Kind:   "codefresh",
 		Secret: "!generate",
@@ -95,14 +97,14 @@ func resourcePipelineCronTriggerCreate(d *schema.ResourceData, meta interface{})
 		},
 	})
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	hermesTrigger := *mapResourceToPipelineCronTrigger(d)
 
-	err = client.CreateHermesTriggerByEventAndPipeline(eventString, hermesTrigger.PipelineID)
+	err = client.CreateHermesTriggerByEventAndPipeline(ctx, eventString, hermesTrigger.PipelineID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(eventString)
@@ -110,39 +112,44 @@ func resourcePipelineCronTriggerCreate(d *schema.ResourceData, meta interface{})
 	return nil
 }
 
-func resourcePipelineCronTriggerRead(d *schema.ResourceData, meta interface{}) error {
+func resourcePipelineCronTriggerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
 	event := d.Id()
 	pipeline := d.Get("pipeline_id").(string)
 
-	hermesTrigger, err := client.GetHermesTriggerByEventAndPipeline(event, pipeline)
+	hermesTrigger, err := client.GetHermesTriggerByEventAndPipeline(ctx, event, pipeline)
 	if err != nil {
-		return err
+		if cfclient.IsNotFound(err) {
+			log.Printf("[WARN] Cron trigger %s not found, removing it from state", event)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 
 	err = mapPipelineCronTriggerToResource(hermesTrigger, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourcePipelineCronTriggerUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourcePipelineCronTriggerUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	// see notes in resourcePipelineCronTrigger()
-	return fmt.Errorf("cron triggers cannot be updated")
+	return diag.Errorf("cron triggers cannot be updated")
 }
 
-func resourcePipelineCronTriggerDelete(d *schema.ResourceData, meta interface{}) error {
+func resourcePipelineCronTriggerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
 	hermesTrigger := *mapResourceToPipelineCronTrigger(d)
 
-	err := client.DeleteHermesTriggerByEventAndPipeline(hermesTrigger.Event, hermesTrigger.PipelineID)
+	err := client.DeleteHermesTriggerByEventAndPipeline(ctx, hermesTrigger.Event, hermesTrigger.PipelineID)
 	if err != nil {
-		return fmt.Errorf("failed to delete cron trigger: %v", err)
+		return diag.Errorf("failed to delete cron trigger: %v", err)
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"testing"
@@ -256,7 +257,7 @@ func testAccCheckCodefreshPipelineCronTriggerDestroy(s *terraform.State) error {
 			continue
 		}
 
-		_, err := apiClient.GetHermesTriggerByEventAndPipeline(rs.Primary.ID, rs.Primary.Attributes["pipeline_id"])
+		_, err := apiClient.GetHermesTriggerByEventAndPipeline(context.Background(), rs.Primary.ID, rs.Primary.Attributes["pipeline_id"])
 
 		if err == nil {
 			return fmt.Errorf("pipeline Cron Trigger still exists")
@@ -285,7 +286,7 @@ func testAccCheckCodefreshPipelineCronTriggerExists(resource string, pipelineCro
 		}
 
 		apiClient := testAccProvider.Meta().(*cfclient.Client)
-		retrievedHermesTrigger, err := apiClient.GetHermesTriggerByEventAndPipeline(rs.Primary.ID, rs.Primary.Attributes["pipeline_id"])
+		retrievedHermesTrigger, err := apiClient.GetHermesTriggerByEventAndPipeline(context.Background(), rs.Primary.ID, rs.Primary.Attributes["pipeline_id"])
 
 		if err != nil {
 			return fmt.Errorf("error fetching pipeline cron trigger with resource %s. %s", resource, err)
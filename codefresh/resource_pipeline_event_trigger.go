@@ -0,0 +1,153 @@
+package codefresh
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/triggertypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourcePipelineEventTrigger() *schema.Resource {
+	return &schema.Resource{
+		Description:   "This resource triggers a pipeline on a Hermes event, such as a registry push, a Helm chart push or a custom event, usually managed with the `codefresh_trigger_event` resource.",
+		CreateContext: resourcePipelineEventTriggerCreate,
+		ReadContext:   resourcePipelineEventTriggerRead,
+		DeleteContext: resourcePipelineEventTriggerDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				// event URIs contain colons but no commas
+				idParts := strings.Split(d.Id(), ",")
+
+				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected EVENT,PIPELINE_ID", d.Id())
+				}
+
+				event := idParts[0]
+				pipelineID := idParts[1]
+				d.SetId(event)
+
+				err := d.Set("pipeline_id", pipelineID)
+				if err != nil {
+					return nil, err
+				}
+
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		// The Codefresh API does not support updating triggers
+		Schema: map[string]*schema.Schema{
+			"pipeline_id": {
+				Description: "The ID of the pipeline to trigger.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"event": {
+				Description:      "The URI of the event triggering the pipeline, for example the `uri` of a `codefresh_trigger_event` resource. See the `codefresh_trigger_types` data source for the supported events.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: triggertypes.EventURIValidator(),
+			},
+			"type": {
+				Description: "The type of the event.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"kind": {
+				Description: "The kind of the event.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"account": {
+				Description: "The ID of the account the event belongs to.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"secret": {
+				Description: "The secret the external system must send along with the event.",
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+func resourcePipelineEventTriggerCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*cfclient.Client)
+
+	event := d.Get("event").(string)
+	pipelineID := d.Get("pipeline_id").(string)
+
+	err := client.CreateHermesTriggerByEventAndPipeline(ctx, event, pipelineID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(event)
+
+	return resourcePipelineEventTriggerRead(ctx, d, meta)
+}
+
+func resourcePipelineEventTriggerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*cfclient.Client)
+
+	event := d.Id()
+	pipelineID := d.Get("pipeline_id").(string)
+
+	hermesTrigger, err := client.GetHermesTriggerByEventAndPipeline(ctx, event, pipelineID)
+	if err != nil {
+		if cfclient.IsNotFound(err) {
+			log.Printf("[WARN] Event trigger %s of pipeline %s not found, removing it from state", event, pipelineID)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	err = mapPipelineEventTriggerToResource(hermesTrigger, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourcePipelineEventTriggerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*cfclient.Client)
+
+	err := client.DeleteHermesTriggerByEventAndPipeline(ctx, d.Id(), d.Get("pipeline_id").(string))
+	if err != nil && !cfclient.IsNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func mapPipelineEventTriggerToResource(hermesTrigger *cfclient.HermesTrigger, d *schema.ResourceData) error {
+	attributes := map[string]interface{}{
+		"pipeline_id": hermesTrigger.PipelineID,
+		"event":       hermesTrigger.Event,
+		"type":        hermesTrigger.EventData.Type,
+		"kind":        hermesTrigger.EventData.Kind,
+		"account":     hermesTrigger.EventData.Account,
+		"secret":      hermesTrigger.EventData.Secret,
+	}
+
+	for key, value := range attributes {
+		err := d.Set(key, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package codefresh
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccCodefreshPipelineEventTrigger_basic(t *testing.T) {
+	pipelineName := pipelineNamePrefix + acctest.RandString(10)
+	resourceName := "codefresh_pipeline_event_trigger.test"
+	var pipelineEventTrigger cfclient.HermesTrigger
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCodefreshPipelineEventTriggerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCodefreshPipelineEventTriggerConfig(pipelineName, "react-sample-app"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCodefreshPipelineCronTriggerExists(resourceName, &pipelineEventTrigger),
+					resource.TestCheckResourceAttrPair(resourceName, "event", "codefresh_trigger_event.test", "uri"),
+					resource.TestCheckResourceAttrPair(resourceName, "secret", "codefresh_trigger_event.test", "secret"),
+					resource.TestCheckResourceAttr(resourceName, "type", "registry"),
+					resource.TestCheckResourceAttr(resourceName, "kind", "dockerhub"),
+					resource.TestCheckResourceAttrSet("codefresh_trigger_event.test", "endpoint"),
+				),
+			},
+			{
+				// a new event replaces the trigger
+				Config: testAccCodefreshPipelineEventTriggerConfig(pipelineName, "react-sample-api"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCodefreshPipelineCronTriggerExists(resourceName, &pipelineEventTrigger),
+					resource.TestCheckResourceAttr("codefresh_trigger_event.test", "values.name", "react-sample-api"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateIdFunc: testAccCodefreshPipelineCronTriggerImportStateIDFunc(resourceName),
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:            "codefresh_trigger_event.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"values"},
+			},
+		},
+	})
+}
+
+func testAccCodefreshPipelineEventTriggerConfig(pipelineName, imageName string) string {
+	return testAccCodefreshPipelineBasicConfig(pipelineName, "codefresh-contrib/react-sample-app", "./codefresh.yml", "master", "git") + fmt.Sprintf(`
+resource "codefresh_trigger_event" "test" {
+  type = "registry"
+  kind = "dockerhub"
+
+  values = {
+    namespace = "codefresh"
+    name      = "%s"
+    action    = "push"
+  }
+}
+
+resource "codefresh_pipeline_event_trigger" "test" {
+  pipeline_id = codefresh_pipeline.test.id
+  event       = codefresh_trigger_event.test.uri
+}
+`, imageName)
+}
+
+func testAccCheckCodefreshPipelineEventTriggerDestroy(s *terraform.State) error {
+	apiClient := testAccProvider.Meta().(*cfclient.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		switch rs.Type {
+		case "codefresh_pipeline_event_trigger":
+			_, err := apiClient.GetHermesTriggerByEventAndPipeline(context.Background(), rs.Primary.ID, rs.Primary.Attributes["pipeline_id"])
+			if err == nil {
+				return fmt.Errorf("pipeline event trigger %s still exists", rs.Primary.ID)
+			}
+		case "codefresh_trigger_event":
+			_, err := apiClient.GetHermesTriggerEvent(context.Background(), rs.Primary.ID)
+			if err == nil {
+				return fmt.Errorf("trigger event %s still exists", rs.Primary.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func TestPipelineEventTriggerExposesTheEventSecret(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+	client := cfclient.NewClient(server.APIURL(), server.GraphQLURL(), fakeapi.APIKey, "")
+	ctx := context.Background()
+
+	pipeline, err := client.CreatePipeline(ctx, &cfclient.Pipeline{Metadata: cfclient.Metadata{Name: "project/app"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	eventResource := resourceTriggerEvent()
+	eventData := schema.TestResourceDataRaw(t, eventResource.Schema, map[string]interface{}{
+		"type":   "registry",
+		"kind":   "quay",
+		"values": map[string]interface{}{"namespace": "codefresh", "name": "app", "action": "push"},
+	})
+	if diags := eventResource.CreateContext(ctx, eventData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	secret := eventData.Get("secret").(string)
+	if !strings.HasPrefix(eventData.Id(), "registry:quay:") || eventData.Get("uri") != eventData.Id() || secret == "" || secret == "!generate" {
+		t.Fatalf("unexpected trigger event state %v", eventData.State())
+	}
+	if endpoint := eventData.Get("endpoint").(string); !strings.Contains(endpoint, secret) {
+		t.Fatalf("expected the endpoint %q to contain the secret", endpoint)
+	}
+
+	triggerResource := resourcePipelineEventTrigger()
+	triggerData := schema.TestResourceDataRaw(t, triggerResource.Schema, map[string]interface{}{
+		"pipeline_id": pipeline.GetID(),
+		"event":       eventData.Id(),
+	})
+	if diags := triggerResource.CreateContext(ctx, triggerData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if triggerData.Id() != eventData.Id() || triggerData.Get("secret") != secret || triggerData.Get("kind") != "quay" {
+		t.Fatalf("unexpected pipeline event trigger state %v", triggerData.State())
+	}
+
+	if diags := triggerResource.DeleteContext(ctx, triggerData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if diags := triggerResource.ReadContext(ctx, triggerData, client); diags.HasError() || triggerData.Id() != "" {
+		t.Fatalf("expected the deleted trigger to be removed from state, got %v %v", triggerData.State(), diags)
+	}
+
+	if diags := eventResource.DeleteContext(ctx, eventData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if diags := eventResource.ReadContext(ctx, eventData, client); diags.HasError() || eventData.Id() != "" {
+		t.Fatalf("expected the deleted event to be removed from state, got %v %v", eventData.State(), diags)
+	}
+}
@@ -0,0 +1,203 @@
+package codefresh
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/mutexkv"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// pipelineMutexKV serializes, per pipeline ID, the read-modify-write cycles of the resources that manage a part of a pipeline
+var pipelineMutexKV = mutexkv.New()
+
+func resourcePipelineGitTrigger() *schema.Resource {
+	triggerSchema := gitTriggerSchema()
+	triggerSchema["pipeline_id"] = &schema.Schema{
+		Description: "The ID of the pipeline the trigger belongs to.",
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+	}
+	// provider is a reserved attribute name at the top level of a resource
+	triggerSchema["git_provider"] = triggerSchema["provider"]
+	delete(triggerSchema, "provider")
+	triggerSchema["encrypted_variables_fingerprints"] = encryptedVariablesFingerprintsSchema()
+	// encrypted variables can be set without being stored in state
+	schemautil.AddWriteOnlyAttribute(triggerSchema, "", "encrypted_variables")
+	triggerSchema["name"] = &schema.Schema{
+		Description: "The name of the trigger, unique within the pipeline.",
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+	}
+
+	return &schema.Resource{
+		Description:   "This resource manages a single git trigger of a pipeline, so that triggers can be owned separately from the pipeline definition. The `codefresh_pipeline` resource must set `ignore_unmanaged_triggers` and must not declare a trigger with the same name.",
+		CreateContext: resourcePipelineGitTriggerCreate,
+		ReadContext:   resourcePipelineGitTriggerRead,
+		UpdateContext: resourcePipelineGitTriggerUpdate,
+		DeleteContext: resourcePipelineGitTriggerDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				separator := strings.LastIndex(d.Id(), ",")
+
+				if separator <= 0 || separator == len(d.Id())-1 {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected TRIGGER_NAME,PIPELINE_ID", d.Id())
+				}
+
+				name := d.Id()[:separator]
+				pipelineID := d.Id()[separator+1:]
+				d.SetId(name)
+
+				err := d.Set("name", name)
+				if err != nil {
+					return nil, err
+				}
+
+				err = d.Set("pipeline_id", pipelineID)
+				if err != nil {
+					return nil, err
+				}
+
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Schema: triggerSchema,
+	}
+}
+
+func resourcePipelineGitTriggerCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*cfclient.Client)
+
+	pipelineID := d.Get("pipeline_id").(string)
+	trigger := expandGitTrigger(d, "")
+
+	pipelineMutexKV.Lock(pipelineID)
+	defer pipelineMutexKV.Unlock(pipelineID)
+
+	_, err := client.GetPipelineTrigger(ctx, pipelineID, trigger.Name)
+	if err == nil {
+		return diag.Errorf("pipeline %s already has a trigger named %s, import it instead", pipelineID, trigger.Name)
+	}
+	if !cfclient.IsNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	err = client.SetPipelineTrigger(ctx, pipelineID, &trigger)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(trigger.Name)
+
+	err = setGitTriggerEncryptedVariablesFingerprints(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePipelineGitTriggerRead(ctx, d, meta)
+}
+
+func resourcePipelineGitTriggerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*cfclient.Client)
+
+	name := d.Id()
+	pipelineID := d.Get("pipeline_id").(string)
+
+	trigger, err := client.GetPipelineTrigger(ctx, pipelineID, name)
+	if err != nil {
+		if cfclient.IsNotFound(err) {
+			log.Printf("[WARN] Trigger %s of pipeline %s not found, removing it from state", name, pipelineID)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	err = mapPipelineGitTriggerToResource(*trigger, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourcePipelineGitTriggerUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*cfclient.Client)
+
+	pipelineID := d.Get("pipeline_id").(string)
+	trigger := expandGitTrigger(d, "")
+
+	pipelineMutexKV.Lock(pipelineID)
+	defer pipelineMutexKV.Unlock(pipelineID)
+
+	err := client.SetPipelineTrigger(ctx, pipelineID, &trigger)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = setGitTriggerEncryptedVariablesFingerprints(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePipelineGitTriggerRead(ctx, d, meta)
+}
+
+func resourcePipelineGitTriggerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*cfclient.Client)
+
+	pipelineID := d.Get("pipeline_id").(string)
+
+	pipelineMutexKV.Lock(pipelineID)
+	defer pipelineMutexKV.Unlock(pipelineID)
+
+	err := client.DeletePipelineTrigger(ctx, pipelineID, d.Id())
+	if err != nil && !cfclient.IsNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func mapPipelineGitTriggerToResource(trigger cfclient.Trigger, d *schema.ResourceData) error {
+	flattenedTrigger := flattenGitTrigger(trigger)
+	flattenedTrigger["git_provider"] = flattenedTrigger["provider"]
+	delete(flattenedTrigger, "provider")
+
+	// Set encrypted variables from resource data, as otherwise they cause constant diff as the value is always returned as *****,
+	// unless their decrypted value shows that they were changed outside of Terraform
+	fingerprints := newEncryptedVariablesFingerprints(d)
+	if schemautil.UsesWriteOnly(d, "encrypted_variables") {
+		flattenedTrigger["encrypted_variables"] = map[string]string{}
+	} else if encryptedVariables, ok := flattenedTrigger["encrypted_variables"].(map[string]string); ok {
+		fingerprints.read(d, "", "encrypted_variables", encryptedVariables)
+	}
+	err := fingerprints.set(d)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range flattenedTrigger {
+		err := d.Set(key, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setGitTriggerEncryptedVariablesFingerprints records the fingerprints of the encrypted variables of the trigger that were applied
+func setGitTriggerEncryptedVariablesFingerprints(d *schema.ResourceData) error {
+	fingerprints := newEncryptedVariablesFingerprints(d)
+	fingerprints.apply("", d.Get("encrypted_variables").(map[string]interface{}))
+	return fingerprints.set(d)
+}
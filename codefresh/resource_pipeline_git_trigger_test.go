@@ -0,0 +1,247 @@
+package codefresh
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccCodefreshPipelineGitTrigger_basic(t *testing.T) {
+	pipelineName := pipelineNamePrefix + acctest.RandString(10)
+	resourceName := "codefresh_pipeline_git_trigger.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCodefreshPipelineDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCodefreshPipelineGitTriggerConfig(pipelineName, "/master/gi"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "app-team"),
+					resource.TestCheckResourceAttr(resourceName, "branch_regex", "/master/gi"),
+					resource.TestCheckResourceAttr("codefresh_pipeline.test", "spec.0.trigger.#", "1"),
+					resource.TestCheckResourceAttr("codefresh_pipeline.test", "spec.0.trigger.0.name", "platform-team"),
+					testAccCheckCodefreshPipelineTriggerNames("codefresh_pipeline.test", "platform-team", "app-team"),
+				),
+			},
+			{
+				Config: testAccCodefreshPipelineGitTriggerConfig(pipelineName, "/release/gi"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "branch_regex", "/release/gi"),
+					resource.TestCheckResourceAttr("codefresh_pipeline.test", "spec.0.trigger.#", "1"),
+					testAccCheckCodefreshPipelineTriggerNames("codefresh_pipeline.test", "platform-team", "app-team"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateIdFunc: testAccCodefreshPipelineCronTriggerImportStateIDFunc(resourceName),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCodefreshPipelineGitTriggerConfig(pipelineName, branchRegex string) string {
+	return fmt.Sprintf(`
+resource "codefresh_pipeline" "test" {
+  name                      = "%s"
+  ignore_unmanaged_triggers = true
+
+  spec {
+    spec_template {
+      repo     = "codefresh-contrib/react-sample-app"
+      path     = "./codefresh.yml"
+      revision = "master"
+      context  = "git"
+    }
+
+    trigger {
+      name    = "platform-team"
+      repo    = "codefresh-contrib/react-sample-app"
+      events  = ["push.heads"]
+      context = "git"
+    }
+  }
+}
+
+resource "codefresh_pipeline_git_trigger" "test" {
+  pipeline_id  = codefresh_pipeline.test.id
+  name         = "app-team"
+  repo         = "codefresh-contrib/react-sample-app"
+  events       = ["push.heads"]
+  branch_regex = "%s"
+  context      = "git"
+}
+`, pipelineName, branchRegex)
+}
+
+func testAccCheckCodefreshPipelineTriggerNames(resourceName string, names ...string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+
+		apiClient := testAccProvider.Meta().(*cfclient.Client)
+		pipeline, err := apiClient.GetPipeline(context.Background(), rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(pipeline.Spec.Triggers) != len(names) {
+			return fmt.Errorf("expected triggers %v, got %v", names, pipeline.Spec.Triggers)
+		}
+		for i, trigger := range pipeline.Spec.Triggers {
+			if trigger.Name != names[i] {
+				return fmt.Errorf("expected triggers %v, got %v", names, pipeline.Spec.Triggers)
+			}
+		}
+		return nil
+	}
+}
+
+func TestPipelineGitTriggerSharesThePipeline(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+	client := cfclient.NewClient(server.APIURL(), server.GraphQLURL(), fakeapi.APIKey, "")
+	ctx := context.Background()
+
+	pipelineResource := resourcePipeline()
+	pipelineData := schema.TestResourceDataRaw(t, pipelineResource.Schema, map[string]interface{}{
+		"name":                      "project/app",
+		"ignore_unmanaged_triggers": true,
+		"spec": []interface{}{map[string]interface{}{
+			"encrypted_variables": map[string]interface{}{"PIPELINE_TOKEN": "p1p3l1n3"},
+			"trigger": []interface{}{map[string]interface{}{
+				"name":                "platform-team",
+				"repo":                "org/app",
+				"encrypted_variables": map[string]interface{}{"PLATFORM_TOKEN": "pl4tf0rm"},
+			}},
+		}},
+	})
+	if diags := pipelineResource.CreateContext(ctx, pipelineData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	// the encrypted variables of the pipeline and of its triggers are never overwritten with their masked value
+	assertEncryptedVariables := func(expected map[string]string) {
+		t.Helper()
+		pipeline, err := client.GetPipelineDecrypted(ctx, pipelineData.Id())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		actual := map[string]string{}
+		for _, variable := range pipeline.Spec.Variables {
+			actual[variable.Key] = variable.Value
+		}
+		for _, trigger := range pipeline.Spec.Triggers {
+			for _, variable := range trigger.Variables {
+				actual[variable.Key] = variable.Value
+			}
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("expected the encrypted variables %v, got %v", expected, actual)
+		}
+	}
+
+	triggerResource := resourcePipelineGitTrigger()
+	triggerData := schema.TestResourceDataRaw(t, triggerResource.Schema, map[string]interface{}{
+		"pipeline_id":         pipelineData.Id(),
+		"name":                "app-team",
+		"repo":                "org/app",
+		"git_provider":        "gitlab",
+		"encrypted_variables": map[string]interface{}{"TOKEN": "s3cr3t"},
+	})
+	if diags := triggerResource.CreateContext(ctx, triggerData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if triggerData.Id() != "app-team" || triggerData.Get("git_provider") != "gitlab" || triggerData.Get("encrypted_variables.TOKEN") != "s3cr3t" {
+		t.Fatalf("unexpected trigger state %v", triggerData.State())
+	}
+	if diags := triggerResource.CreateContext(ctx, triggerData, client); !diags.HasError() {
+		t.Fatal("expected creating a trigger with an existing name to fail")
+	}
+	assertEncryptedVariables(map[string]string{"PIPELINE_TOKEN": "p1p3l1n3", "PLATFORM_TOKEN": "pl4tf0rm", "TOKEN": "s3cr3t"})
+
+	// the pipeline ignores the trigger it does not declare, and keeps it on update
+	if diags := pipelineResource.ReadContext(ctx, pipelineData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if triggers := pipelineData.Get("spec.0.trigger").([]interface{}); len(triggers) != 1 {
+		t.Fatalf("expected only the declared trigger in the pipeline state, got %v", triggers)
+	}
+	if err := pipelineData.Set("tags", []interface{}{"updated"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diags := pipelineResource.UpdateContext(ctx, pipelineData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	pipeline, err := client.GetPipeline(ctx, pipelineData.Id())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pipeline.Spec.Triggers) != 2 || pipeline.Spec.Triggers[1].Name != "app-team" || pipeline.Spec.Triggers[1].Provider != "gitlab" {
+		t.Fatalf("expected the standalone trigger to be kept, got %v", pipeline.Spec.Triggers)
+	}
+	assertEncryptedVariables(map[string]string{"PIPELINE_TOKEN": "p1p3l1n3", "PLATFORM_TOKEN": "pl4tf0rm", "TOKEN": "s3cr3t"})
+
+	if diags := triggerResource.DeleteContext(ctx, triggerData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if _, err := client.GetPipelineTrigger(ctx, pipelineData.Id(), "app-team"); !cfclient.IsNotFound(err) {
+		t.Fatalf("expected the trigger to be deleted, got %v", err)
+	}
+	if _, err := client.GetPipelineTrigger(ctx, pipelineData.Id(), "platform-team"); err != nil {
+		t.Fatalf("expected the declared trigger to be kept, got %v", err)
+	}
+	assertEncryptedVariables(map[string]string{"PIPELINE_TOKEN": "p1p3l1n3", "PLATFORM_TOKEN": "pl4tf0rm"})
+}
+
+func TestPipelineGitTriggerIsNotSetWhenEncryptedVariablesCannotBeDecrypted(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+	client := cfclient.NewClient(server.APIURL(), server.GraphQLURL(), fakeapi.APIKey, "")
+	ctx := context.Background()
+
+	pipelineResource := resourcePipeline()
+	pipelineData := schema.TestResourceDataRaw(t, pipelineResource.Schema, map[string]interface{}{
+		"name": "project/app",
+		"spec": []interface{}{map[string]interface{}{
+			"encrypted_variables": map[string]interface{}{"PIPELINE_TOKEN": "p1p3l1n3"},
+		}},
+	})
+	if diags := pipelineResource.CreateContext(ctx, pipelineData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if _, err := client.RequestAPI(ctx, &cfclient.RequestOptions{
+		Path:   "/features/" + server.AccountID,
+		Method: "POST",
+		Body:   []byte(`{"feature": "forbidDecrypt"}`),
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the feature flags of the account are cached by the client
+	client = cfclient.NewClient(server.APIURL(), server.GraphQLURL(), fakeapi.APIKey, "")
+
+	triggerResource := resourcePipelineGitTrigger()
+	triggerData := schema.TestResourceDataRaw(t, triggerResource.Schema, map[string]interface{}{
+		"pipeline_id": pipelineData.Id(),
+		"name":        "app-team",
+		"repo":        "org/app",
+	})
+	diags := triggerResource.CreateContext(ctx, triggerData, client)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "would be overwritten with their masked value") {
+		t.Fatalf("expected the trigger not to be set, got %v", diags)
+	}
+}
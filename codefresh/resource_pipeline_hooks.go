@@ -0,0 +1,366 @@
+package codefresh
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// pipelineHookNames are the pipeline hooks, in the order they are written to the pipeline YAML
+var pipelineHookNames = []string{"on_elected", "on_success", "on_fail", "on_finish"}
+
+// pipelineHookChanges are the keys of the metadata and annotations of a hook
+var pipelineHookChanges = []string{"set", "unset"}
+
+func pipelineHooksSchema() map[string]*schema.Schema {
+	descriptions := map[string]string{
+		"on_elected": "The hook run when the build is elected to run, before its first step.",
+		"on_success": "The hook run when the build succeeds.",
+		"on_fail":    "The hook run when the build fails.",
+		"on_finish":  "The hook run when the build finishes, whatever its result.",
+	}
+
+	hooks := make(map[string]*schema.Schema, len(pipelineHookNames))
+	for _, name := range pipelineHookNames {
+		hooks[name] = &schema.Schema{
+			Description: descriptions[name],
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: pipelineHookSchema(),
+			},
+		}
+	}
+	return hooks
+}
+
+func pipelineHookSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"exec": {
+			Description: "The step run by the hook.",
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"image": {
+						Description: "The image the hook runs in.",
+						Type:        schema.TypeString,
+						Required:    true,
+					},
+					"commands": {
+						Description: "The commands the hook runs.",
+						Type:        schema.TypeList,
+						Optional:    true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+					"environment": {
+						Description: "The environment variables of the hook, as `KEY=VALUE`.",
+						Type:        schema.TypeList,
+						Optional:    true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+					"extra": {
+						Description:      "A YAML mapping with the other fields of the step run by the hook, e.g. `shell` or `working_directory`. It must not repeat the fields set by the other attributes, and holds the fields whose value does not fit their attribute.",
+						Type:             schema.TypeString,
+						Optional:         true,
+						ValidateDiagFunc: schemautil.StringIsValidYaml(),
+						DiffSuppressFunc: schemautil.SuppressEquivalentYamlDiffs(),
+					},
+				},
+			},
+		},
+		"metadata": {
+			Description:      "A YAML mapping with the `set` and `unset` lists of image metadata changes of the hook.",
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateDiagFunc: validatePipelineHookChanges(),
+			DiffSuppressFunc: schemautil.SuppressEquivalentYamlDiffs(),
+		},
+		"annotations": {
+			Description:      "A YAML mapping with the `set` and `unset` lists of annotation changes of the hook, each with the `annotations` of an entity.",
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateDiagFunc: validatePipelineHookChanges(),
+			DiffSuppressFunc: schemautil.SuppressEquivalentYamlDiffs(),
+		},
+		"extra": {
+			Description:      "A YAML mapping with the other fields of the hook, e.g. the `steps` and `mode` of a hook running several steps. It must not repeat the fields set by the other attributes, and holds the fields whose value does not fit their attribute.",
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateDiagFunc: schemautil.StringIsValidYaml(),
+			DiffSuppressFunc: schemautil.SuppressEquivalentYamlDiffs(),
+		},
+	}
+}
+
+// validatePipelineHookChanges checks that metadata or annotations are a YAML mapping of set and unset lists
+func validatePipelineHookChanges() schema.SchemaValidateDiagFunc {
+	return func(v any, p cty.Path) diag.Diagnostics {
+		if _, err := parsePipelineHookChanges(v.(string)); err != nil {
+			return diag.Diagnostics{{
+				Severity:      diag.Error,
+				Summary:       "Invalid hook changes",
+				Detail:        err.Error(),
+				AttributePath: p,
+			}}
+		}
+		return nil
+	}
+}
+
+func parsePipelineHookChanges(changes string) (*yaml.Node, error) {
+	node, err := parseYamlValue(changes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid YAML: %v", err)
+	}
+	if node == nil {
+		return nil, nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a YAML mapping with %v lists", pipelineHookChanges)
+	}
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		if !slices.Contains(pipelineHookChanges, key) {
+			return nil, fmt.Errorf("unexpected %s, expected one of %v", key, pipelineHookChanges)
+		}
+		if value.Kind != yaml.SequenceNode {
+			return nil, fmt.Errorf("%s must be a list", key)
+		}
+	}
+	return node, nil
+}
+
+// checkPipelineHooks checks that each declared hook does something, and that its extra fields do not repeat its attributes
+func checkPipelineHooks(d *schema.ResourceDiff) error {
+	for _, name := range pipelineHookNames {
+		prefix := fmt.Sprintf("spec.0.hooks.0.%s", name)
+		if len(d.Get(prefix).([]interface{})) == 0 || !d.NewValueKnown(prefix+".0.metadata") || !d.NewValueKnown(prefix+".0.annotations") || !d.NewValueKnown(prefix+".0.extra") {
+			continue
+		}
+		if len(d.Get(prefix+".0.exec").([]interface{})) == 0 && d.Get(prefix+".0.metadata") == "" && d.Get(prefix+".0.annotations") == "" && d.Get(prefix+".0.extra") == "" {
+			return fmt.Errorf("hook %s must declare exec, metadata, annotations or extra", name)
+		}
+
+		setAttributes := map[string]bool{
+			"exec":        len(d.Get(prefix+".0.exec").([]interface{})) > 0,
+			"metadata":    d.Get(prefix+".0.metadata") != "",
+			"annotations": d.Get(prefix+".0.annotations") != "",
+		}
+		if err := checkPipelineHookExtra(d.Get(prefix+".0.extra").(string), setAttributes, "hook "+name); err != nil {
+			return err
+		}
+		if setAttributes["exec"] && d.NewValueKnown(prefix+".0.exec.0.commands") && d.NewValueKnown(prefix+".0.exec.0.environment") && d.NewValueKnown(prefix+".0.exec.0.extra") {
+			setExecAttributes := map[string]bool{
+				"image":       true,
+				"commands":    len(d.Get(prefix+".0.exec.0.commands").([]interface{})) > 0,
+				"environment": len(d.Get(prefix+".0.exec.0.environment").([]interface{})) > 0,
+			}
+			if err := checkPipelineHookExtra(d.Get(prefix+".0.exec.0.extra").(string), setExecAttributes, "exec of hook "+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkPipelineHookExtra checks that extra is a YAML mapping which does not repeat the attributes that are set
+func checkPipelineHookExtra(extra string, setAttributes map[string]bool, of string) error {
+	extraNode, err := parseYamlExtra(extra)
+	if err != nil {
+		return fmt.Errorf("invalid extra of %s: %v", of, err)
+	}
+	for attribute, set := range setAttributes {
+		if set && mappingValue(extraNode, attribute) != nil {
+			return fmt.Errorf("%s of %s is set both by its attribute and in extra", attribute, of)
+		}
+	}
+	return nil
+}
+
+// appendPipelineHookExtra appends the fields of extra to the mapping node
+func appendPipelineHookExtra(node *yaml.Node, extra string, of string) error {
+	if strings.TrimSpace(extra) == "" {
+		return nil
+	}
+	extraNode, err := parseYamlExtra(extra)
+	if err != nil {
+		return fmt.Errorf("invalid extra of %s: %v", of, err)
+	}
+	for i := 0; i < len(extraNode.Content); i += 2 {
+		if key := extraNode.Content[i].Value; mappingValue(node, key) != nil {
+			return fmt.Errorf("invalid extra of %s: %s is already set by an attribute", of, key)
+		}
+	}
+	node.Content = append(node.Content, extraNode.Content...)
+	return nil
+}
+
+// flattenPipelineHookExtra returns the fields of extraNode as the YAML of an extra attribute, or an empty string if there are none
+func flattenPipelineHookExtra(extraNode *yaml.Node) (string, error) {
+	if len(extraNode.Content) == 0 {
+		return "", nil
+	}
+	resetYamlStyle(extraNode)
+	return encodeYamlNode(extraNode)
+}
+
+// expandPipelineHooks returns the hooks as a YAML mapping
+func expandPipelineHooks(hooks []interface{}) (*yaml.Node, error) {
+	hooksNode := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	declared, _ := hooks[0].(map[string]interface{})
+
+	for _, name := range pipelineHookNames {
+		hookList, _ := declared[name].([]interface{})
+		if len(hookList) == 0 {
+			continue
+		}
+		hook, _ := hookList[0].(map[string]interface{})
+
+		hookNode := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		if execList, _ := hook["exec"].([]interface{}); len(execList) > 0 && execList[0] != nil {
+			exec := execList[0].(map[string]interface{})
+			execNode := mappingNode("image", stringNode(exec["image"].(string)))
+			for _, key := range []string{"commands", "environment"} {
+				if items, _ := exec[key].([]interface{}); len(items) > 0 {
+					execNode.Content = append(execNode.Content, stringNode(key), stringListNode(items))
+				}
+			}
+			extra, _ := exec["extra"].(string)
+			if err := appendPipelineHookExtra(execNode, extra, "exec of hook "+name); err != nil {
+				return nil, err
+			}
+			hookNode.Content = append(hookNode.Content, stringNode("exec"), execNode)
+		}
+
+		for _, key := range []string{"metadata", "annotations"} {
+			changes, _ := hook[key].(string)
+			changesNode, err := parsePipelineHookChanges(changes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s of hook %s: %v", key, name, err)
+			}
+			if changesNode != nil {
+				hookNode.Content = append(hookNode.Content, stringNode(key), changesNode)
+			}
+		}
+
+		extra, _ := hook["extra"].(string)
+		if err := appendPipelineHookExtra(hookNode, extra, "hook "+name); err != nil {
+			return nil, err
+		}
+
+		hooksNode.Content = append(hooksNode.Content, stringNode(name), hookNode)
+	}
+
+	return hooksNode, nil
+}
+
+// flattenPipelineHooks returns the hooks block of the hooks returned by the API.
+// The fields that do not fit an attribute, because of their key or their value, are kept in extra so that no field is lost.
+func flattenPipelineHooks(hooks *cfclient.Hooks) ([]map[string]interface{}, error) {
+	res := make([]map[string]interface{}, 0)
+	if hooks == nil {
+		return res, nil
+	}
+
+	node, err := parseYamlValue(hooks.Hooks)
+	if err != nil || node == nil {
+		return res, err
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("unexpected hooks %s", hooks.Hooks)
+	}
+
+	flattened := map[string]interface{}{}
+	for i := 0; i < len(node.Content); i += 2 {
+		name, hookNode := node.Content[i].Value, node.Content[i+1]
+		if !slices.Contains(pipelineHookNames, name) {
+			return nil, fmt.Errorf("hook %s cannot be represented by the hooks block, declare the hooks in original_yaml_string instead", name)
+		}
+		if hookNode.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("unexpected hook %s", name)
+		}
+
+		hook := map[string]interface{}{}
+		extraNode := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for j := 0; j < len(hookNode.Content); j += 2 {
+			key, value := hookNode.Content[j], hookNode.Content[j+1]
+			switch key.Value {
+			case "exec":
+				if exec, ok := flattenPipelineHookExec(value); ok {
+					hook["exec"] = []map[string]interface{}{exec}
+					continue
+				}
+			case "metadata", "annotations":
+				resetYamlStyle(value)
+				changes, err := encodeYamlNode(value)
+				if err != nil {
+					return nil, err
+				}
+				if _, err := parsePipelineHookChanges(changes); err == nil {
+					hook[key.Value] = changes
+					continue
+				}
+			}
+			extraNode.Content = append(extraNode.Content, key, value)
+		}
+
+		if hook["extra"], err = flattenPipelineHookExtra(extraNode); err != nil {
+			return nil, err
+		}
+
+		flattened[name] = []map[string]interface{}{hook}
+	}
+
+	if len(flattened) == 0 {
+		return res, nil
+	}
+	return []map[string]interface{}{flattened}, nil
+}
+
+// flattenPipelineHookExec returns the exec block of a hook, and false when the YAML value does not fit it
+func flattenPipelineHookExec(execNode *yaml.Node) (map[string]interface{}, bool) {
+	if execNode.Kind != yaml.MappingNode {
+		return nil, false
+	}
+	if image := mappingValue(execNode, "image"); image == nil || !isStringNode(image) {
+		return nil, false
+	}
+
+	exec := map[string]interface{}{}
+	extraNode := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for i := 0; i < len(execNode.Content); i += 2 {
+		key, value := execNode.Content[i], execNode.Content[i+1]
+		switch key.Value {
+		case "image":
+			exec["image"] = value.Value
+			continue
+		case "commands", "environment":
+			if items, ok := flattenStringList(value); ok {
+				exec[key.Value] = items
+				continue
+			}
+		}
+		extraNode.Content = append(extraNode.Content, key, value)
+	}
+
+	extra, err := flattenPipelineHookExtra(extraNode)
+	if err != nil {
+		return nil, false
+	}
+	exec["extra"] = extra
+	return exec, true
+}
@@ -0,0 +1,257 @@
+package codefresh
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/fakeapi"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gopkg.in/yaml.v3"
+)
+
+func TestAccCodefreshPipeline_Hooks(t *testing.T) {
+	name := pipelineNamePrefix + acctest.RandString(10)
+	resourceName := "codefresh_pipeline.test"
+	var pipeline cfclient.Pipeline
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCodefreshPipelineDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCodefreshPipelineHooksConfig(name, "echo elected"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCodefreshPipelineExists(resourceName, &pipeline),
+					resource.TestCheckResourceAttr(resourceName, "spec.0.hooks.0.on_elected.0.exec.0.commands.0", "echo elected"),
+					resource.TestCheckResourceAttr(resourceName, "spec.0.hooks.0.on_fail.0.exec.0.image", "alpine:3.20"),
+				),
+			},
+			{
+				Config: testAccCodefreshPipelineHooksConfig(name, "echo started"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "spec.0.hooks.0.on_elected.0.exec.0.commands.0", "echo started"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCodefreshPipelineHooksConfig(rName, command string) string {
+	return fmt.Sprintf(`
+resource "codefresh_pipeline" "test" {
+  lifecycle {
+    ignore_changes = [
+      revision
+    ]
+  }
+
+  name                 = "%s"
+  original_yaml_string = "version: \"1.0\"\nsteps:\n  test:\n    image: alpine\n    commands:\n      - echo test\n"
+
+  spec {
+    hooks {
+      on_elected {
+        exec {
+          image    = "alpine:3.20"
+          commands = [%q]
+        }
+      }
+
+      on_fail {
+        exec {
+          image       = "alpine:3.20"
+          commands    = ["echo failed"]
+          environment = ["NOTIFY=true"]
+        }
+      }
+    }
+  }
+}
+`, rName, command)
+}
+
+func TestPipelineHooksAreMergedWithYamlSteps(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePipeline().Schema, map[string]interface{}{
+		"name":                 "project/app",
+		"original_yaml_string": "version: \"1.0\"\nsteps:\n  test:\n    image: alpine\n    commands:\n      - echo test\n",
+		"spec": []interface{}{map[string]interface{}{
+			"hooks": []interface{}{map[string]interface{}{
+				"on_elected": []interface{}{map[string]interface{}{
+					"exec": []interface{}{map[string]interface{}{"image": "alpine", "commands": []interface{}{"echo elected"}}},
+				}},
+				"on_finish": []interface{}{map[string]interface{}{
+					"annotations": "set:\n  - entity_type: build\n    annotations:\n      - coverage: 80\n",
+				}},
+			}},
+		}},
+	})
+
+	pipeline, err := mapResourceToPipeline(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedHooks := `{"on_elected":{"exec":{"image":"alpine","commands":["echo elected"]}},"on_finish":{"annotations":{"set":[{"entity_type":"build","annotations":[{"coverage":80}]}]}}}`
+	if pipeline.Spec.Hooks == nil || pipeline.Spec.Hooks.Hooks != expectedHooks {
+		t.Fatalf("expected hooks %s, got %v", expectedHooks, pipeline.Spec.Hooks)
+	}
+	if pipeline.Spec.Steps == nil || pipeline.Spec.Steps.Steps != `{"test":{"image":"alpine","commands":["echo test"]}}` {
+		t.Fatalf("expected the steps of the original YAML, got %v", pipeline.Spec.Steps)
+	}
+
+	hooks, err := flattenPipelineHooks(pipeline.Spec.Hooks)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	onElected := hooks[0]["on_elected"].([]map[string]interface{})[0]["exec"].([]map[string]interface{})[0]
+	if onElected["image"] != "alpine" || onElected["commands"].([]string)[0] != "echo elected" {
+		t.Fatalf("unexpected on_elected hook %v", onElected)
+	}
+	if annotations := hooks[0]["on_finish"].([]map[string]interface{})[0]["annotations"].(string); !strings.Contains(annotations, "coverage: 80") {
+		t.Fatalf("unexpected on_finish annotations %q", annotations)
+	}
+	if _, ok := hooks[0]["on_fail"]; ok {
+		t.Fatalf("expected only the declared hooks, got %v", hooks)
+	}
+}
+
+func TestPipelineHooksKeepTheFieldsWithoutAttribute(t *testing.T) {
+	apiHooks := &cfclient.Hooks{Hooks: `{"on_elected":{"exec":{"image":"alpine","shell":"bash","commands":["echo elected"]}},"on_fail":{"mode":"parallel","steps":{"notify":{"image":"alpine","commands":["echo failed"]}}},"on_finish":{"exec":{"image":"alpine","commands":[1]}}}`}
+
+	hooks, err := flattenPipelineHooks(apiHooks)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	onElected := hooks[0]["on_elected"].([]map[string]interface{})[0]["exec"].([]map[string]interface{})[0]
+	if onElected["image"] != "alpine" || onElected["extra"] != "shell: bash\n" {
+		t.Fatalf("expected the shell of on_elected in extra, got %v", onElected)
+	}
+	if onFail := hooks[0]["on_fail"].([]map[string]interface{})[0]; onFail["extra"] != "mode: parallel\nsteps:\n  notify:\n    image: alpine\n    commands:\n      - echo failed\n" {
+		t.Fatalf("expected the steps of on_fail in extra, got %q", onFail["extra"])
+	}
+	onFinish := hooks[0]["on_finish"].([]map[string]interface{})[0]["exec"].([]map[string]interface{})[0]
+	if _, ok := onFinish["commands"]; ok || onFinish["extra"] != "commands:\n  - 1\n" {
+		t.Fatalf("expected the non-string commands of on_finish in extra, got %v", onFinish)
+	}
+
+	// the flattened hooks give back the same hooks
+	expanded, err := expandPipelineHooks([]interface{}{map[string]interface{}{
+		"on_elected": []interface{}{map[string]interface{}{"exec": []interface{}{map[string]interface{}{"image": "alpine", "commands": []interface{}{"echo elected"}, "extra": onElected["extra"]}}}},
+		"on_fail":    []interface{}{map[string]interface{}{"extra": hooks[0]["on_fail"].([]map[string]interface{})[0]["extra"]}},
+		"on_finish":  []interface{}{map[string]interface{}{"exec": []interface{}{map[string]interface{}{"image": "alpine", "extra": onFinish["extra"]}}}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var expandedHooks, expectedHooks interface{}
+	if err := yaml.Unmarshal([]byte(apiHooks.Hooks), &expectedHooks); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := expanded.Decode(&expandedHooks); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(expandedHooks, expectedHooks) {
+		t.Fatalf("expected hooks %v, got %v", expectedHooks, expandedHooks)
+	}
+
+	if _, err := flattenPipelineHooks(&cfclient.Hooks{Hooks: `{"on_terminated":{"exec":{"image":"alpine"}}}`}); err == nil || !strings.Contains(err.Error(), "cannot be represented") {
+		t.Fatalf("expected an unknown hook to be reported, got %v", err)
+	}
+}
+
+func TestPipelineHooksAreReadBackFromTheSpec(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+	client := cfclient.NewClient(server.APIURL(), server.GraphQLURL(), fakeapi.APIKey, "")
+	ctx := context.Background()
+
+	pipelineResource := resourcePipeline()
+	pipelineData := schema.TestResourceDataRaw(t, pipelineResource.Schema, map[string]interface{}{
+		"name": "project/app",
+		"spec": []interface{}{map[string]interface{}{
+			"step": []interface{}{map[string]interface{}{"name": "test", "image": "alpine", "commands": []interface{}{"echo test"}}},
+			"hooks": []interface{}{map[string]interface{}{
+				"on_success": []interface{}{map[string]interface{}{
+					"metadata": "set:\n  - ${{build.imageId}}:\n      - tested: true\n",
+				}},
+			}},
+		}},
+	})
+	if diags := pipelineResource.CreateContext(ctx, pipelineData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if pipelineData.Get("original_yaml_string") != "" {
+		t.Fatalf("expected original_yaml_string to keep its configured value, got %q", pipelineData.Get("original_yaml_string"))
+	}
+	if metadata := pipelineData.Get("spec.0.hooks.0.on_success.0.metadata").(string); !strings.Contains(metadata, "tested: true") {
+		t.Fatalf("unexpected on_success metadata %q", metadata)
+	}
+}
+
+func TestPipelineHooksAreValidatedAtPlanTime(t *testing.T) {
+	metadata := pipelineHookSchema()["metadata"]
+	if diags := metadata.ValidateDiagFunc("set:\n  - image: []\nunset: []\n", cty.Path{}); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if diags := metadata.ValidateDiagFunc("add:\n  - image: []\n", cty.Path{}); !diags.HasError() {
+		t.Fatal("expected unsupported metadata changes to be rejected")
+	}
+	if diags := metadata.ValidateDiagFunc("set: image\n", cty.Path{}); !diags.HasError() {
+		t.Fatal("expected metadata changes that are not lists to be rejected")
+	}
+
+	testCases := map[string]struct {
+		originalYamlString string
+		hook               map[string]interface{}
+		expectedError      string
+	}{
+		"empty hook": {
+			hook:          map[string]interface{}{"exec": []interface{}{}},
+			expectedError: "hook on_fail must declare exec, metadata, annotations or extra",
+		},
+		"hook field repeated in extra": {
+			hook: map[string]interface{}{
+				"exec":  []interface{}{map[string]interface{}{"image": "alpine"}},
+				"extra": "exec:\n  image: alpine\n",
+			},
+			expectedError: "exec of hook on_fail is set both by its attribute and in extra",
+		},
+		"exec field repeated in extra": {
+			hook: map[string]interface{}{
+				"exec": []interface{}{map[string]interface{}{"image": "alpine", "commands": []interface{}{"echo failed"}, "extra": "commands:\n  - echo failed\n"}},
+			},
+			expectedError: "commands of exec of hook on_fail is set both by its attribute and in extra",
+		},
+		"hooks also in original YAML": {
+			originalYamlString: "version: \"1.0\"\nhooks:\n  on_fail:\n    exec:\n      image: alpine\n",
+			hook:               map[string]interface{}{"exec": []interface{}{map[string]interface{}{"image": "alpine"}}},
+			expectedError:      "hooks cannot be declared both in original_yaml_string and with the hooks block",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{
+				"name":                 "project/app",
+				"original_yaml_string": testCase.originalYamlString,
+				"spec": []interface{}{map[string]interface{}{
+					"hooks": []interface{}{map[string]interface{}{"on_fail": []interface{}{testCase.hook}}},
+				}},
+			})
+			_, err := resourcePipeline().Diff(context.Background(), nil, config, nil)
+			if err == nil || !strings.Contains(err.Error(), testCase.expectedError) {
+				t.Fatalf("expected error %q, got %v", testCase.expectedError, err)
+			}
+		})
+	}
+}
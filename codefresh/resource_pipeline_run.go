@@ -0,0 +1,241 @@
+package codefresh
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// pipelineRunPollInterval is the wait between two reads of the status of a build
+var pipelineRunPollInterval = 10 * time.Second
+
+// defaultPipelineRunTimeout is the default duration allowed for a build to finish
+const defaultPipelineRunTimeout = 30 * time.Minute
+
+func resourcePipelineRun() *schema.Resource {
+	triggerSchema := gitTriggerSchema()
+
+	return &schema.Resource{
+		Description:   "This resource runs a pipeline and waits for the build to finish, failing if the build does not succeed. It is meant for bootstrapping, for example to run a seed or migration pipeline. The pipeline is run again only when an argument changes: a build deleted by the retention policy of Codefresh keeps its last known status in the state and is not run again. A build waiting for a manual approval stops the wait with a warning and the `pending-approval` status, and is not run again either. Destroying the resource does not affect the build.",
+		CreateContext: resourcePipelineRunCreate,
+		ReadContext:   resourcePipelineRunRead,
+		DeleteContext: resourcePipelineRunDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultPipelineRunTimeout),
+			Read:   schema.DefaultTimeout(schemautil.DefaultResourceTimeout),
+			Delete: schema.DefaultTimeout(schemautil.DefaultResourceTimeout),
+		},
+		Schema: map[string]*schema.Schema{
+			"pipeline_id": {
+				Description: "The ID of the pipeline to run.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"branch": {
+				Description: "The branch to run the pipeline for.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"variables": {
+				Description: "The variables of the build, overriding those of the pipeline.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"options": {
+				Description: "The options of the build.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: forceNewSchema(triggerSchema["options"].Elem.(*schema.Resource).Schema),
+				},
+			},
+			"runtime_environment": {
+				Description: "The runtime environment of the build, overriding the one of the pipeline.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: forceNewSchema(triggerSchema["runtime_environment"].Elem.(*schema.Resource).Schema),
+				},
+			},
+			"triggers": {
+				Description: "Arbitrary values that, when changed, run the pipeline again.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"build_id": {
+				Description: "The ID of the build.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"status": {
+				Description: "The status of the build, for example `success`, or `pending-approval` if it waits for a manual approval.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"build_url": {
+				Description: "The URL of the build in the Codefresh UI.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// forceNewSchema returns a copy of the attributes marked ForceNew, for resources that cannot be updated
+func forceNewSchema(attributes map[string]*schema.Schema) map[string]*schema.Schema {
+	copied := make(map[string]*schema.Schema, len(attributes))
+	for key, attribute := range attributes {
+		attributeCopy := *attribute
+		attributeCopy.ForceNew = true
+		copied[key] = &attributeCopy
+	}
+	return copied
+}
+
+func resourcePipelineRunCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*cfclient.Client)
+
+	pipelineID := d.Get("pipeline_id").(string)
+	buildID, err := client.RunPipeline(ctx, pipelineID, mapResourceToBuildRequest(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The ID is set before waiting, so that a failed build is tainted and run again by the next apply
+	d.SetId(buildID)
+
+	stateConf := &retry.StateChangeConf{
+		Pending:      []string{"running"},
+		Target:       []string{"finished"},
+		Timeout:      d.Timeout(schema.TimeoutCreate),
+		PollInterval: pipelineRunPollInterval,
+		Refresh: func() (interface{}, string, error) {
+			build, err := client.GetBuild(ctx, buildID)
+			if err != nil {
+				return nil, "", err
+			}
+			log.Printf("[DEBUG] Build %s of pipeline %s is %s", buildID, pipelineID, build.Status)
+			// a build waiting for a manual approval could wait for days, so it is not waited for
+			if build.IsFinished() || build.Status == cfclient.BuildStatusPendingApproval {
+				return build, "finished", nil
+			}
+			return build, "running", nil
+		},
+	}
+
+	result, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return diag.Errorf("failed waiting for build %s (%s): %v", buildID, client.BuildURL(buildID), err)
+	}
+
+	err = mapBuildToResource(result.(*cfclient.PipelineBuild), client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	status := d.Get("status").(string)
+	if status == cfclient.BuildStatusPendingApproval {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Build waiting for approval",
+			Detail:   fmt.Sprintf("Build %s of pipeline %s is waiting for a manual approval, see %s. It is not run again by the next apply.", buildID, pipelineID, client.BuildURL(buildID)),
+		}}
+	}
+	if status != cfclient.BuildStatusSuccess {
+		return diag.Errorf("build %s of pipeline %s finished with status %s, see %s", buildID, pipelineID, status, client.BuildURL(buildID))
+	}
+
+	return nil
+}
+
+func resourcePipelineRunRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*cfclient.Client)
+
+	build, err := client.GetBuild(ctx, d.Id())
+	if err != nil {
+		// Builds are deleted by the retention policy of Codefresh, which must not run the pipeline again
+		if cfclient.IsNotFound(err) {
+			log.Printf("[WARN] Build %s not found, keeping its last known status %s in state", d.Id(), d.Get("status"))
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	err = mapBuildToResource(build, client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourcePipelineRunDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Builds cannot be deleted, they are only removed from the state
+	return nil
+}
+
+func mapBuildToResource(build *cfclient.PipelineBuild, client *cfclient.Client, d *schema.ResourceData) error {
+	attributes := map[string]interface{}{
+		"build_id":  build.ID,
+		"status":    build.Status,
+		"build_url": client.BuildURL(build.ID),
+	}
+
+	for key, value := range attributes {
+		err := d.Set(key, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func mapResourceToBuildRequest(d *schema.ResourceData) *cfclient.BuildRequest {
+	request := &cfclient.BuildRequest{
+		Branch:    d.Get("branch").(string),
+		Variables: datautil.ConvertStringMap(d.Get("variables").(map[string]interface{})),
+	}
+
+	if _, ok := d.GetOk("options"); ok {
+		request.Options = &cfclient.TriggerOptions{
+			NoCache:             d.Get("options.0.no_cache").(bool),
+			NoCfCache:           d.Get("options.0.no_cf_cache").(bool),
+			ResetVolume:         d.Get("options.0.reset_volume").(bool),
+			EnableNotifications: d.Get("options.0.enable_notifications").(bool),
+		}
+	}
+	if _, ok := d.GetOk("runtime_environment"); ok {
+		request.RuntimeEnvironment = &cfclient.RuntimeEnvironment{
+			Name:                     d.Get("runtime_environment.0.name").(string),
+			Memory:                   d.Get("runtime_environment.0.memory").(string),
+			CPU:                      d.Get("runtime_environment.0.cpu").(string),
+			DindStorage:              d.Get("runtime_environment.0.dind_storage").(string),
+			RequiredAvailableStorage: d.Get("runtime_environment.0.required_available_storage").(string),
+		}
+	}
+
+	return request
+}
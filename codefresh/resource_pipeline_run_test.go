@@ -0,0 +1,137 @@
+package codefresh
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccCodefreshPipelineRun_basic(t *testing.T) {
+	pipelineName := pipelineNamePrefix + acctest.RandString(10)
+	resourceName := "codefresh_pipeline_run.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCodefreshPipelineDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCodefreshPipelineRunConfig(pipelineName, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "status", "success"),
+					resource.TestCheckResourceAttrPair(resourceName, "build_id", resourceName, "id"),
+					resource.TestCheckResourceAttrSet(resourceName, "build_url"),
+				),
+			},
+			{
+				// a new seed runs the pipeline again
+				Config: testAccCodefreshPipelineRunConfig(pipelineName, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "status", "success"),
+					resource.TestCheckResourceAttr(resourceName, "triggers.seed", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCodefreshPipelineRunConfig(pipelineName, seed string) string {
+	return testAccCodefreshPipelineBasicConfig(pipelineName, "codefresh-contrib/react-sample-app", "./codefresh.yml", "master", "git") + fmt.Sprintf(`
+resource "codefresh_pipeline_run" "test" {
+  pipeline_id = codefresh_pipeline.test.id
+  branch      = "master"
+
+  variables = {
+    SEED = %q
+  }
+
+  triggers = {
+    seed = %q
+  }
+}
+`, seed, seed)
+}
+
+func TestPipelineRunWaitsForTheBuild(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+	client := cfclient.NewClient(server.APIURL(), server.GraphQLURL(), fakeapi.APIKey, "")
+	ctx := context.Background()
+	defer func(interval time.Duration) { pipelineRunPollInterval = interval }(pipelineRunPollInterval)
+	pipelineRunPollInterval = time.Millisecond
+
+	pipeline, err := client.CreatePipeline(ctx, &cfclient.Pipeline{Metadata: cfclient.Metadata{Name: "project/seed"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	runResource := resourcePipelineRun()
+	runData := schema.TestResourceDataRaw(t, runResource.Schema, map[string]interface{}{
+		"pipeline_id":         pipeline.GetID(),
+		"branch":              "main",
+		"variables":           map[string]interface{}{"SEED": "1"},
+		"options":             []interface{}{map[string]interface{}{"no_cache": true}},
+		"runtime_environment": []interface{}{map[string]interface{}{"name": "system/default", "memory": "2Gi"}},
+	})
+	if diags := runResource.CreateContext(ctx, runData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if runData.Get("status") != "success" || runData.Get("build_id") != runData.Id() || !strings.HasSuffix(runData.Get("build_url").(string), "/build/"+runData.Id()) {
+		t.Fatalf("unexpected pipeline run state %v", runData.State())
+	}
+
+	builds := server.Builds()
+	if len(builds) != 1 {
+		t.Fatalf("expected one build, got %v", builds)
+	}
+	expected := map[string]interface{}{
+		"branch":             "main",
+		"variables":          map[string]interface{}{"SEED": "1"},
+		"options":            map[string]interface{}{"noCache": true},
+		"runtimeEnvironment": map[string]interface{}{"name": "system/default", "memory": "2Gi"},
+	}
+	for key, value := range expected {
+		if !reflect.DeepEqual(builds[0][key], value) {
+			t.Errorf("expected the build %s to be %v, got %v", key, value, builds[0][key])
+		}
+	}
+
+	// a build deleted by the retention policy is kept in state, so that it is not run again
+	server.PurgeBuilds()
+	if diags := runResource.ReadContext(ctx, runData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if runData.Id() == "" || runData.Get("status") != "success" {
+		t.Fatalf("expected the purged build to keep its state, got %v", runData.State())
+	}
+
+	server.BuildResult = "pending-approval"
+	pendingData := schema.TestResourceDataRaw(t, runResource.Schema, map[string]interface{}{"pipeline_id": pipeline.GetID()})
+	diags := runResource.CreateContext(ctx, pendingData, client)
+	if diags.HasError() || len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("expected a warning about the build waiting for approval, got %v", diags)
+	}
+	if pendingData.Get("status") != "pending-approval" {
+		t.Fatalf("unexpected pipeline run state %v", pendingData.State())
+	}
+
+	server.BuildResult = "error"
+	failedData := schema.TestResourceDataRaw(t, runResource.Schema, map[string]interface{}{"pipeline_id": pipeline.GetID()})
+	diags = runResource.CreateContext(ctx, failedData, client)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "finished with status error") {
+		t.Fatalf("expected the failed build to be reported, got %v", diags)
+	}
+	if failedData.Id() == "" {
+		t.Fatal("expected the failed build to be kept in state, to be tainted")
+	}
+}
@@ -0,0 +1,680 @@
+package codefresh
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// pipelineStepBuiltinTypes are the step types provided by Codefresh, any other type is a typed step,
+// e.g. from the marketplace or a codefresh_step_types resource
+var pipelineStepBuiltinTypes = []string{"freestyle", "build", "push", "git-clone", "composition", "launch-composition", "pending-approval", "parallel"}
+
+const (
+	validPipelineStepNameRegex = `^[A-Za-z0-9_-]+$`
+	validPipelineStepTypeRegex = `^[a-z0-9][a-z0-9._-]*(/[a-z0-9._-]+)?(:[A-Za-z0-9._-]+)?$`
+)
+
+// pipelineStepAttribute is an attribute of a step block and the key of its value in the pipeline YAML
+type pipelineStepAttribute struct {
+	attribute string
+	key       string
+	// kind is the YAML kind of the value, a string, a list of strings or a map of strings
+	kind yaml.Kind
+	// types are the step types the attribute applies to, all when empty
+	types []string
+	// typedOnly restricts the attribute to typed steps
+	typedOnly bool
+}
+
+// pipelineStepAttributes are in the order the keys are written to the pipeline YAML
+var pipelineStepAttributes = []pipelineStepAttribute{
+	{attribute: "type", key: "type", kind: yaml.ScalarNode},
+	{attribute: "title", key: "title", kind: yaml.ScalarNode},
+	{attribute: "description", key: "description", kind: yaml.ScalarNode},
+	{attribute: "stage", key: "stage", kind: yaml.ScalarNode},
+	{attribute: "image", key: "image", kind: yaml.ScalarNode, types: []string{"freestyle"}},
+	{attribute: "image_name", key: "image_name", kind: yaml.ScalarNode, types: []string{"build", "push"}},
+	{attribute: "dockerfile", key: "dockerfile", kind: yaml.ScalarNode, types: []string{"build"}},
+	{attribute: "candidate", key: "candidate", kind: yaml.ScalarNode, types: []string{"push"}},
+	{attribute: "registry", key: "registry", kind: yaml.ScalarNode, types: []string{"build", "push"}},
+	{attribute: "tag", key: "tag", kind: yaml.ScalarNode, types: []string{"build", "push"}},
+	{attribute: "repo", key: "repo", kind: yaml.ScalarNode, types: []string{"git-clone"}},
+	{attribute: "revision", key: "revision", kind: yaml.ScalarNode, types: []string{"git-clone"}},
+	{attribute: "git", key: "git", kind: yaml.ScalarNode, types: []string{"git-clone"}},
+	{attribute: "working_directory", key: "working_directory", kind: yaml.ScalarNode},
+	{attribute: "commands", key: "commands", kind: yaml.SequenceNode, types: []string{"freestyle"}},
+	{attribute: "environment", key: "environment", kind: yaml.SequenceNode},
+	{attribute: "arguments", key: "arguments", kind: yaml.MappingNode, typedOnly: true},
+}
+
+// pipelineStepRequiredAttributes are the attributes each built-in step type cannot do without
+var pipelineStepRequiredAttributes = map[string]string{
+	"freestyle": "image",
+	"build":     "image_name",
+	"push":      "candidate",
+	"git-clone": "repo",
+}
+
+func pipelineStageSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Description: "The name of the stage.",
+			Type:        schema.TypeString,
+			Required:    true,
+		},
+	}
+}
+
+func pipelineStepSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Description: "The name of the step, unique in the pipeline.",
+			Type:        schema.TypeString,
+			Required:    true,
+			ValidateDiagFunc: schemautil.StringMatchesRegExp(
+				validPipelineStepNameRegex,
+				schemautil.WithSummary("Invalid step name"),
+				schemautil.WithDetailFormat("The step name %q is invalid (must match %q)."),
+			),
+		},
+		"type": {
+			Description: "The type of the step: `freestyle` (the default when omitted), `build`, `push`, `git-clone`, `composition`, `launch-composition`, `pending-approval`, `parallel`, or the name of a typed step, e.g. `helm` or `codefresh/kubernetes-deploy:1.0.0`.",
+			Type:        schema.TypeString,
+			Optional:    true,
+			ValidateDiagFunc: schemautil.StringMatchesRegExp(
+				validPipelineStepTypeRegex,
+				schemautil.WithSummary("Invalid step type"),
+				schemautil.WithDetailFormat("The step type %q is invalid (must match %q)."),
+			),
+		},
+		"title": {
+			Description: "The title of the step, shown in the build logs.",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"description": {
+			Description: "The description of the step.",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"stage": {
+			Description: "The stage the step belongs to, one of the `stage` blocks when any is declared.",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"image": {
+			Description: "The image the step runs in (`freestyle` steps, required).",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"image_name": {
+			Description: "The name of the image to build (`build` steps, required) or to push the candidate as (`push` steps).",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"dockerfile": {
+			Description: "The path of the Dockerfile (`build` steps).",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"candidate": {
+			Description: "The image to push (`push` steps, required).",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"registry": {
+			Description: "The registry to push to (`build` and `push` steps).",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"tag": {
+			Description: "The tag of the image (`build` and `push` steps).",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"repo": {
+			Description: "The repository to clone (`git-clone` steps, required).",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"revision": {
+			Description: "The revision to check out (`git-clone` steps).",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"git": {
+			Description: "The name of the git integration (`git-clone` steps).",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"working_directory": {
+			Description: "The directory the step runs in.",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"commands": {
+			Description: "The commands the step runs (`freestyle` steps).",
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"environment": {
+			Description: "The environment variables of the step, as `KEY=VALUE`.",
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"arguments": {
+			Description: "The arguments of the step (typed steps).",
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"extra": {
+			Description:      "A YAML mapping with the other fields of the step, e.g. `when`, `retry`, `fail_fast`, the `composition` of a composition step or the `steps` of a parallel step. It must not repeat the fields set by the other attributes, and holds the fields whose value does not fit their attribute, e.g. non-string `arguments`.",
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateDiagFunc: schemautil.StringIsValidYaml(),
+			DiffSuppressFunc: schemautil.SuppressEquivalentYamlDiffs(),
+		},
+	}
+}
+
+// pipelineYamlBlocks are the parts of the pipeline YAML declared with blocks rather than in original_yaml_string
+type pipelineYamlBlocks struct {
+	stages []interface{}
+	steps  []interface{}
+	hooks  []interface{}
+}
+
+// pipelineYamlBlocksFrom returns the blocks of the resource data or diff of a pipeline
+func pipelineYamlBlocksFrom(d interface{ Get(string) interface{} }) pipelineYamlBlocks {
+	return pipelineYamlBlocks{
+		stages: d.Get("spec.0.stage").([]interface{}),
+		steps:  d.Get("spec.0.step").([]interface{}),
+		hooks:  d.Get("spec.0.hooks").([]interface{}),
+	}
+}
+
+func (blocks pipelineYamlBlocks) isEmpty() bool {
+	return len(blocks.stages) == 0 && len(blocks.steps) == 0 && len(blocks.hooks) == 0
+}
+
+// declaredKeys returns the keys of the pipeline YAML declared with blocks, and the blocks declaring them
+func (blocks pipelineYamlBlocks) declaredKeys() [][2]string {
+	keys := [][2]string{}
+	if len(blocks.stages) > 0 {
+		keys = append(keys, [2]string{"stages", "stage blocks"})
+	}
+	if len(blocks.steps) > 0 {
+		keys = append(keys, [2]string{"steps", "step blocks"})
+	}
+	if len(blocks.hooks) > 0 {
+		keys = append(keys, [2]string{"hooks", "the hooks block"})
+	}
+	return keys
+}
+
+// buildPipelineYaml adds the stages, steps and hooks declared with blocks to the original YAML of the pipeline.
+// The original YAML is returned unchanged when no block is declared.
+func buildPipelineYaml(originalYamlString string, blocks pipelineYamlBlocks) (string, error) {
+	if blocks.isEmpty() {
+		return originalYamlString, nil
+	}
+
+	var document yaml.Node
+	if strings.TrimSpace(originalYamlString) != "" {
+		err := yaml.Unmarshal([]byte(originalYamlString), &document)
+		if err != nil {
+			return "", fmt.Errorf("error while parsing original YAML string: %v", err)
+		}
+	}
+	if document.Kind == 0 {
+		document = yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{mappingNode("version", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "1.0", Style: yaml.DoubleQuotedStyle})},
+		}
+	}
+	root := document.Content[0]
+	if err := checkPipelineYamlBlocks(root, blocks); err != nil {
+		return "", err
+	}
+
+	if len(blocks.stages) > 0 {
+		root.Content = append(root.Content, stringNode("stages"), expandPipelineStages(blocks.stages))
+	}
+
+	if len(blocks.steps) > 0 {
+		stepsNode, err := expandPipelineSteps(blocks.steps)
+		if err != nil {
+			return "", err
+		}
+		root.Content = append(root.Content, stringNode("steps"), stepsNode)
+	}
+
+	if len(blocks.hooks) > 0 {
+		hooksNode, err := expandPipelineHooks(blocks.hooks)
+		if err != nil {
+			return "", err
+		}
+		root.Content = append(root.Content, stringNode("hooks"), hooksNode)
+	}
+
+	return encodeYamlNode(&document)
+}
+
+// checkPipelineYamlBlocks checks that the original YAML, if any, does not declare what the blocks declare
+func checkPipelineYamlBlocks(root *yaml.Node, blocks pipelineYamlBlocks) error {
+	if root == nil {
+		return nil
+	}
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("original YAML string must be a mapping to declare steps, stages or hooks with blocks")
+	}
+	for _, key := range blocks.declaredKeys() {
+		if mappingValue(root, key[0]) != nil {
+			return fmt.Errorf("%s cannot be declared both in original_yaml_string and with %s", key[0], key[1])
+		}
+	}
+	return nil
+}
+
+func expandPipelineStages(stages []interface{}) *yaml.Node {
+	stagesNode := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, stage := range stages {
+		stagesNode.Content = append(stagesNode.Content, stringNode(stage.(map[string]interface{})["name"].(string)))
+	}
+	return stagesNode
+}
+
+// expandPipelineSteps returns the steps as a YAML mapping, in the order of the blocks
+func expandPipelineSteps(steps []interface{}) (*yaml.Node, error) {
+	stepsNode := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+
+	for _, s := range steps {
+		step := s.(map[string]interface{})
+		name := step["name"].(string)
+		if mappingValue(stepsNode, name) != nil {
+			return nil, fmt.Errorf("step %s is declared more than once", name)
+		}
+
+		stepNode := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, attribute := range pipelineStepAttributes {
+			if value := expandPipelineStepAttribute(attribute, step[attribute.attribute]); value != nil {
+				stepNode.Content = append(stepNode.Content, stringNode(attribute.key), value)
+			}
+		}
+
+		if extra, _ := step["extra"].(string); strings.TrimSpace(extra) != "" {
+			extraNode, err := parseYamlExtra(extra)
+			if err != nil {
+				return nil, fmt.Errorf("invalid extra of step %s: %v", name, err)
+			}
+			for i := 0; i < len(extraNode.Content); i += 2 {
+				if key := extraNode.Content[i].Value; mappingValue(stepNode, key) != nil {
+					return nil, fmt.Errorf("invalid extra of step %s: %s is already set by an attribute", name, key)
+				}
+			}
+			stepNode.Content = append(stepNode.Content, extraNode.Content...)
+		}
+
+		stepsNode.Content = append(stepsNode.Content, stringNode(name), stepNode)
+	}
+
+	return stepsNode, nil
+}
+
+// expandPipelineStepAttribute returns the YAML value of an attribute, or nil when it is empty
+func expandPipelineStepAttribute(attribute pipelineStepAttribute, value interface{}) *yaml.Node {
+	switch attribute.kind {
+	case yaml.ScalarNode:
+		if s, _ := value.(string); s != "" {
+			return stringNode(s)
+		}
+	case yaml.SequenceNode:
+		if items, _ := value.([]interface{}); len(items) > 0 {
+			return stringListNode(items)
+		}
+	case yaml.MappingNode:
+		if entries, _ := value.(map[string]interface{}); len(entries) > 0 {
+			// Terraform maps are unordered, the keys are sorted for a stable YAML
+			keys := make([]string, 0, len(entries))
+			for key := range entries {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for _, key := range keys {
+				s, _ := entries[key].(string)
+				node.Content = append(node.Content, stringNode(key), stringNode(s))
+			}
+			return node
+		}
+	}
+	return nil
+}
+
+// flattenPipelineStages returns the stage blocks of the stages returned by the API
+func flattenPipelineStages(stages *cfclient.Stages) ([]map[string]interface{}, error) {
+	res := make([]map[string]interface{}, 0)
+	if stages == nil {
+		return res, nil
+	}
+
+	node, err := parseYamlValue(stages.Stages)
+	if err != nil || node == nil {
+		return res, err
+	}
+	if node.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("unexpected stages %s", stages.Stages)
+	}
+	for _, stage := range node.Content {
+		if !isStringNode(stage) {
+			return nil, fmt.Errorf("unexpected stage %s", stage.Value)
+		}
+		res = append(res, map[string]interface{}{"name": stage.Value})
+	}
+	return res, nil
+}
+
+// flattenPipelineSteps returns the step blocks of the steps returned by the API, in their order.
+// The fields that do not fit an attribute, because of their key or their value, are kept in extra so that no field is lost.
+func flattenPipelineSteps(steps *cfclient.Steps) ([]map[string]interface{}, error) {
+	res := make([]map[string]interface{}, 0)
+	if steps == nil {
+		return res, nil
+	}
+
+	node, err := parseYamlValue(steps.Steps)
+	if err != nil || node == nil {
+		return res, err
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("unexpected steps %s", steps.Steps)
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		name, stepNode := node.Content[i].Value, node.Content[i+1]
+		if stepNode.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("unexpected step %s", name)
+		}
+
+		stepType := ""
+		if value := mappingValue(stepNode, "type"); value != nil && isStringNode(value) {
+			stepType = value.Value
+		}
+
+		step := map[string]interface{}{"name": name}
+		extraNode := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for j := 0; j < len(stepNode.Content); j += 2 {
+			key, value := stepNode.Content[j], stepNode.Content[j+1]
+			if attribute, ok := findPipelineStepAttribute(key.Value); ok && attribute.appliesTo(stepType) {
+				if flattened, ok := flattenPipelineStepAttribute(attribute, value); ok {
+					step[attribute.attribute] = flattened
+					continue
+				}
+			}
+			extraNode.Content = append(extraNode.Content, key, value)
+		}
+
+		if len(extraNode.Content) > 0 {
+			resetYamlStyle(extraNode)
+			extra, err := encodeYamlNode(extraNode)
+			if err != nil {
+				return nil, err
+			}
+			step["extra"] = extra
+		}
+
+		res = append(res, step)
+	}
+
+	return res, nil
+}
+
+// flattenPipelineStepAttribute returns the value of an attribute, and false when the YAML value does not fit it
+func flattenPipelineStepAttribute(attribute pipelineStepAttribute, value *yaml.Node) (interface{}, bool) {
+	if value.Kind != attribute.kind {
+		return nil, false
+	}
+
+	switch attribute.kind {
+	case yaml.ScalarNode:
+		if isStringNode(value) && value.Value != "" {
+			return value.Value, true
+		}
+	case yaml.SequenceNode:
+		return flattenStringList(value)
+	case yaml.MappingNode:
+		entries := make(map[string]string, len(value.Content)/2)
+		for i := 0; i < len(value.Content); i += 2 {
+			if _, ok := entries[value.Content[i].Value]; ok || !isStringNode(value.Content[i+1]) {
+				return nil, false
+			}
+			entries[value.Content[i].Value] = value.Content[i+1].Value
+		}
+		return entries, len(entries) > 0
+	}
+	return nil, false
+}
+
+// appliesTo returns whether the attribute is supported by steps of the given type
+func (attribute pipelineStepAttribute) appliesTo(stepType string) bool {
+	if stepType == "" {
+		stepType = "freestyle"
+	}
+	if attribute.typedOnly {
+		return !slices.Contains(pipelineStepBuiltinTypes, stepType)
+	}
+	return len(attribute.types) == 0 || slices.Contains(attribute.types, stepType)
+}
+
+func findPipelineStepAttribute(key string) (pipelineStepAttribute, bool) {
+	for _, attribute := range pipelineStepAttributes {
+		if attribute.key == key {
+			return attribute, true
+		}
+	}
+	return pipelineStepAttribute{}, false
+}
+
+// resourcePipelineCheckBlocks checks the step, stage and hooks blocks, so that invalid steps are reported at plan time
+func resourcePipelineCheckBlocks(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := checkPipelineHooks(d); err != nil {
+		return err
+	}
+
+	steps := d.Get("spec.0.step").([]interface{})
+	stages := d.Get("spec.0.stage").([]interface{})
+
+	declaredStages := make([]string, 0, len(stages))
+	for _, stage := range stages {
+		name := stage.(map[string]interface{})["name"].(string)
+		if slices.Contains(declaredStages, name) {
+			return fmt.Errorf("stage %s is declared more than once", name)
+		}
+		declaredStages = append(declaredStages, name)
+	}
+
+	names := make([]string, 0, len(steps))
+	for i, s := range steps {
+		step := s.(map[string]interface{})
+		prefix := fmt.Sprintf("spec.0.step.%d", i)
+		known := func(attribute string) bool {
+			return d.NewValueKnown(fmt.Sprintf("%s.%s", prefix, attribute))
+		}
+
+		name := step["name"].(string)
+		if known("name") {
+			if slices.Contains(names, name) {
+				return fmt.Errorf("step %s is declared more than once", name)
+			}
+			names = append(names, name)
+		}
+		if !known("type") || !known("extra") {
+			continue
+		}
+		stepType := step["type"].(string)
+		if stepType == "" {
+			stepType = "freestyle"
+		}
+
+		extraNode, err := parseYamlExtra(step["extra"].(string))
+		if err != nil {
+			return fmt.Errorf("invalid extra of step %s: %v", name, err)
+		}
+
+		for _, attribute := range pipelineStepAttributes {
+			if !known(attribute.attribute) || attribute.attribute == "type" || isEmptyValue(step[attribute.attribute]) {
+				continue
+			}
+			if !attribute.appliesTo(stepType) {
+				return fmt.Errorf("%s is not supported by step %s of type %s", attribute.attribute, name, stepType)
+			}
+			if mappingValue(extraNode, attribute.key) != nil {
+				return fmt.Errorf("%s of step %s is set both by its attribute and in extra", attribute.key, name)
+			}
+		}
+
+		if required, ok := pipelineStepRequiredAttributes[stepType]; ok && known(required) && isEmptyValue(step[required]) && mappingValue(extraNode, required) == nil {
+			return fmt.Errorf("%s is required by step %s of type %s", required, name, stepType)
+		}
+
+		if stage := step["stage"].(string); len(declaredStages) > 0 && known("stage") && stage != "" && !slices.Contains(declaredStages, stage) {
+			return fmt.Errorf("stage %s of step %s is not declared, expected one of %s", stage, name, strings.Join(declaredStages, ", "))
+		}
+	}
+
+	if d.NewValueKnown("original_yaml_string") {
+		root, err := parseYamlValue(d.Get("original_yaml_string").(string))
+		if err != nil {
+			return fmt.Errorf("error while parsing original YAML string: %v", err)
+		}
+		if err := checkPipelineYamlBlocks(root, pipelineYamlBlocksFrom(d)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseYamlExtra returns the YAML mapping of an extra attribute, empty when it is not set
+func parseYamlExtra(extra string) (*yaml.Node, error) {
+	node, err := parseYamlValue(extra)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a YAML mapping")
+	}
+	return node, nil
+}
+
+// parseYamlValue returns the root node of a YAML (or JSON) document, or nil when it is empty or null
+func parseYamlValue(value string) (*yaml.Node, error) {
+	var document yaml.Node
+	err := yaml.Unmarshal([]byte(value), &document)
+	if err != nil {
+		return nil, err
+	}
+	if document.Kind == 0 || len(document.Content) == 0 || document.Content[0].ShortTag() == "!!null" {
+		return nil, nil
+	}
+	return document.Content[0], nil
+}
+
+func encodeYamlNode(node *yaml.Node) (string, error) {
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// resetYamlStyle switches a node parsed from JSON to block style, strings are still quoted when needed
+func resetYamlStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYamlStyle(child)
+	}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func mappingNode(key string, value *yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{stringNode(key), value}}
+}
+
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func stringListNode(items []interface{}) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, item := range items {
+		s, _ := item.(string)
+		node.Content = append(node.Content, stringNode(s))
+	}
+	return node
+}
+
+// flattenStringList returns the strings of a non-empty YAML sequence, and false when it holds anything else
+func flattenStringList(node *yaml.Node) ([]string, bool) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, false
+	}
+	items := make([]string, 0, len(node.Content))
+	for _, item := range node.Content {
+		if !isStringNode(item) {
+			return nil, false
+		}
+		items = append(items, item.Value)
+	}
+	return items, len(items) > 0
+}
+
+func isStringNode(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.ShortTag() == "!!str"
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return value == nil
+}
@@ -0,0 +1,310 @@
+package codefresh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/iancoleman/orderedmap"
+)
+
+func TestAccCodefreshPipeline_StructuredSteps(t *testing.T) {
+	name := pipelineNamePrefix + acctest.RandString(10)
+	resourceName := "codefresh_pipeline.test"
+	var pipeline cfclient.Pipeline
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCodefreshPipelineDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCodefreshPipelineStructuredStepsConfig(name, "npm test"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCodefreshPipelineExists(resourceName, &pipeline),
+					resource.TestCheckResourceAttr(resourceName, "spec.0.step.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "spec.0.step.0.name", "main_clone"),
+					resource.TestCheckResourceAttr(resourceName, "spec.0.step.1.commands.0", "npm test"),
+					resource.TestCheckResourceAttr(resourceName, "original_yaml_string", "version: \"1.0\"\n"),
+				),
+			},
+			{
+				Config: testAccCodefreshPipelineStructuredStepsConfig(name, "npm run test:ci"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "spec.0.step.1.commands.0", "npm run test:ci"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCodefreshPipelineStructuredStepsConfig(rName, command string) string {
+	return fmt.Sprintf(`
+resource "codefresh_pipeline" "test" {
+  lifecycle {
+    ignore_changes = [
+      revision
+    ]
+  }
+
+  name                 = "%s"
+  original_yaml_string = "version: \"1.0\"\n"
+
+  spec {
+    stage {
+      name = "test"
+    }
+
+    step {
+      name     = "main_clone"
+      type     = "git-clone"
+      stage    = "test"
+      repo     = "codefresh-contrib/react-sample-app"
+      revision = "master"
+    }
+
+    step {
+      name     = "unit_tests"
+      stage    = "test"
+      image    = "node:20"
+      commands = [%q]
+      extra    = "fail_fast: false\n"
+    }
+  }
+}
+`, rName, command)
+}
+
+const structuredStepsYamlString = `version: "1.0"
+stages:
+  - clone
+  - build
+  - deploy
+steps:
+  main_clone:
+    type: git-clone
+    stage: clone
+    repo: codefresh-contrib/react-sample-app
+    revision: ${{CF_REVISION}}
+    git: github
+  build_image:
+    type: build
+    stage: build
+    image_name: org/app
+    tag: "1.0"
+    registry: dockerhub
+    buildkit: true
+  unit_tests:
+    stage: build
+    image: node:20
+    working_directory: ${{main_clone}}
+    environment:
+      - CI=true
+    commands:
+      - npm ci
+      - npm test
+    when:
+      branch:
+        only:
+          - main
+  approve:
+    type: pending-approval
+    stage: deploy
+    timeout:
+      duration: 2
+      finalState: denied
+  deploy:
+    type: helm:1.1.12
+    stage: deploy
+    arguments:
+      action: install
+      chart_name: app
+      custom_values:
+        - replicas=2
+  notify:
+    type: parallel
+    steps:
+      slack:
+        image: alpine
+        commands:
+          - echo done
+`
+
+func TestPipelineStepsRoundTripWithYaml(t *testing.T) {
+	original := &cfclient.Pipeline{}
+	if err := extractSpecAttributesFromOriginalYamlString(structuredStepsYamlString, original); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stages, err := flattenPipelineStages(original.Spec.Stages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	steps, err := flattenPipelineSteps(original.Spec.Steps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if steps[1]["tag"] != "1.0" || !strings.Contains(steps[1]["extra"].(string), "buildkit: true") {
+		t.Fatalf("unexpected build step %v", steps[1])
+	}
+	if !reflect.DeepEqual(steps[2]["commands"], []string{"npm ci", "npm test"}) || !strings.HasPrefix(steps[2]["extra"].(string), "when:") {
+		t.Fatalf("unexpected freestyle step %v", steps[2])
+	}
+	// arguments that are not all strings do not fit the attribute and are kept in extra
+	if _, ok := steps[4]["arguments"]; ok || !strings.Contains(steps[4]["extra"].(string), "custom_values") {
+		t.Fatalf("unexpected typed step %v", steps[4])
+	}
+
+	d := schema.TestResourceDataRaw(t, resourcePipeline().Schema, map[string]interface{}{"name": "project/app"})
+	if err := d.Set("spec", []map[string]interface{}{{"stage": stages, "step": steps}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pipeline, err := mapResourceToPipeline(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if pipeline.Spec.Stages.Stages != original.Spec.Stages.Stages {
+		t.Fatalf("expected stages %s, got %s", original.Spec.Stages.Stages, pipeline.Spec.Stages.Stages)
+	}
+	assertEquivalentSteps(t, original.Spec.Steps.Steps, pipeline.Spec.Steps.Steps)
+}
+
+func TestPipelineStepsAreReadBackFromTheSpec(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+	client := cfclient.NewClient(server.APIURL(), server.GraphQLURL(), fakeapi.APIKey, "")
+	ctx := context.Background()
+
+	originalYamlString := "version: \"1.0\"\nmode: parallel\n"
+	pipelineResource := resourcePipeline()
+	pipelineData := schema.TestResourceDataRaw(t, pipelineResource.Schema, map[string]interface{}{
+		"name":                 "project/app",
+		"original_yaml_string": originalYamlString,
+		"spec": []interface{}{map[string]interface{}{
+			"stage": []interface{}{map[string]interface{}{"name": "test"}},
+			"step": []interface{}{
+				map[string]interface{}{"name": "a_lint", "stage": "test", "image": "golangci/golangci-lint", "commands": []interface{}{"golangci-lint run"}},
+				map[string]interface{}{"name": "b_test", "stage": "test", "image": "golang:1.24", "commands": []interface{}{"go test ./..."}, "extra": "when:\n  steps:\n    - name: a_lint\n      on:\n        - success\n"},
+			},
+		}},
+	})
+	if diags := pipelineResource.CreateContext(ctx, pipelineData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	pipeline, err := client.GetPipeline(ctx, pipelineData.Id())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pipeline.Spec.Mode != "parallel" || !strings.Contains(pipeline.Metadata.OriginalYamlString, "a_lint:") {
+		t.Fatalf("expected the steps to be added to the pipeline YAML, got %s", pipeline.Metadata.OriginalYamlString)
+	}
+
+	if pipelineData.Get("original_yaml_string") != originalYamlString {
+		t.Fatalf("expected original_yaml_string to keep its configured value, got %q", pipelineData.Get("original_yaml_string"))
+	}
+	if pipelineData.Get("spec.0.step.1.name") != "b_test" || pipelineData.Get("spec.0.step.1.commands.0") != "go test ./..." || pipelineData.Get("spec.0.stage.0.name") != "test" {
+		t.Fatalf("unexpected steps %v", pipelineData.Get("spec.0.step"))
+	}
+	if extra := pipelineData.Get("spec.0.step.1.extra").(string); !strings.Contains(extra, "a_lint") {
+		t.Fatalf("unexpected extra %q", extra)
+	}
+}
+
+func TestPipelineStepsAreValidatedAtPlanTime(t *testing.T) {
+	testCases := map[string]struct {
+		originalYamlString string
+		stages             []interface{}
+		step               map[string]interface{}
+		expectedError      string
+	}{
+		"valid": {
+			step: map[string]interface{}{"name": "build", "type": "build", "image_name": "org/app", "tag": "latest"},
+		},
+		"missing required attribute": {
+			step:          map[string]interface{}{"name": "test", "commands": []interface{}{"make test"}},
+			expectedError: "image is required by step test of type freestyle",
+		},
+		"unsupported attribute": {
+			step:          map[string]interface{}{"name": "build", "type": "build", "image_name": "org/app", "commands": []interface{}{"make"}},
+			expectedError: "commands is not supported by step build of type build",
+		},
+		"arguments of a built-in step": {
+			step:          map[string]interface{}{"name": "push", "type": "push", "candidate": "app", "arguments": map[string]interface{}{"tag": "latest"}},
+			expectedError: "arguments is not supported by step push of type push",
+		},
+		"undeclared stage": {
+			stages:        []interface{}{map[string]interface{}{"name": "build"}},
+			step:          map[string]interface{}{"name": "test", "image": "alpine", "stage": "test"},
+			expectedError: "stage test of step test is not declared",
+		},
+		"field repeated in extra": {
+			step:          map[string]interface{}{"name": "test", "image": "alpine", "extra": "image: ubuntu\n"},
+			expectedError: "image of step test is set both by its attribute and in extra",
+		},
+		"steps also in original YAML": {
+			originalYamlString: "version: \"1.0\"\nsteps:\n  test:\n    image: alpine\n",
+			step:               map[string]interface{}{"name": "test", "image": "alpine"},
+			expectedError:      "steps cannot be declared both in original_yaml_string and with step blocks",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{
+				"name":                 "project/app",
+				"original_yaml_string": testCase.originalYamlString,
+				"spec": []interface{}{map[string]interface{}{
+					"stage": testCase.stages,
+					"step":  []interface{}{testCase.step},
+				}},
+			})
+			_, err := resourcePipeline().Diff(context.Background(), nil, config, nil)
+			if testCase.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), testCase.expectedError) {
+				t.Fatalf("expected error %q, got %v", testCase.expectedError, err)
+			}
+		})
+	}
+}
+
+// assertEquivalentSteps checks that two steps JSON have the same steps, in the same order, with the same fields
+func assertEquivalentSteps(t *testing.T, expected, actual string) {
+	t.Helper()
+
+	expectedSteps, actualSteps := orderedmap.New(), orderedmap.New()
+	if err := json.Unmarshal([]byte(expected), expectedSteps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal([]byte(actual), actualSteps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(expectedSteps.Keys(), actualSteps.Keys()) {
+		t.Fatalf("expected steps %v, got %v", expectedSteps.Keys(), actualSteps.Keys())
+	}
+
+	var expectedFields, actualFields map[string]interface{}
+	if err := json.Unmarshal([]byte(expected), &expectedFields); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal([]byte(actual), &actualFields); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(expectedFields, actualFields) {
+		t.Fatalf("expected steps %s, got %s", expected, actual)
+	}
+}
@@ -1,14 +1,20 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -163,7 +169,7 @@ func TestAccCodefreshPipeline_Variables(t *testing.T) {
 				ResourceName:            resourceName,
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"spec.0.encrypted_variables"},
+				ImportStateVerifyIgnore: []string{"spec.0.encrypted_variables", "encrypted_variables_fingerprints"},
 			},
 			{
 				Config: testAccCodefreshPipelineBasicConfigVariables(name, "codefresh-contrib/react-sample-app", "./codefresh.yml", "master", "git", "var1", "val1_updated", "var2", "val2_updated", "var1", "val1_updated", "var2", "val2_updated"),
@@ -408,7 +414,7 @@ func TestAccCodefreshPipeline_Triggers(t *testing.T) {
 				ResourceName:            resourceName,
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"spec.0.trigger.1.encrypted_variables"},
+				ImportStateVerifyIgnore: []string{"spec.0.trigger.1.encrypted_variables", "encrypted_variables_fingerprints"},
 			},
 			{
 				Config: testAccCodefreshPipelineBasicConfigTriggers(
@@ -853,7 +859,7 @@ func testAccCheckCodefreshPipelineExists(resource string, pipeline *cfclient.Pip
 		pipelineID := rs.Primary.ID
 
 		apiClient := testAccProvider.Meta().(*cfclient.Client)
-		retrievedPipeline, err := apiClient.GetPipeline(pipelineID)
+		retrievedPipeline, err := apiClient.GetPipeline(context.Background(), pipelineID)
 
 		if err != nil {
 			return fmt.Errorf("error fetching pipeline with resource %s. %s", resource, err)
@@ -874,7 +880,7 @@ func testAccCheckCodefreshPipelineDestroy(s *terraform.State) error {
 			continue
 		}
 
-		_, err := apiClient.GetPipeline(rs.Primary.ID)
+		_, err := apiClient.GetPipeline(context.Background(), rs.Primary.ID)
 
 		if err == nil {
 			return fmt.Errorf("Alert still exists")
@@ -905,7 +911,7 @@ func testAccCheckCodefreshPipelineOriginalYamlStringAttributePropagation(resourc
 		pipelineID := rs.Primary.ID
 
 		apiClient := testAccProvider.Meta().(*cfclient.Client)
-		pipeline, err := apiClient.GetPipeline(pipelineID)
+		pipeline, err := apiClient.GetPipeline(context.Background(), pipelineID)
 
 		if !reflect.DeepEqual(pipeline.Spec.Steps, spec.Steps) {
 			return fmt.Errorf("Expected Step %v. Got %v", spec.Steps, pipeline.Spec.Steps)
@@ -1555,3 +1561,247 @@ resource "codefresh_pipeline" "test" {
 		extResource1Context, extResource1Repo, extResource1Revision, extResourse1SourcePath, extResource1DestPath,
 		extResource2Context, extResource2Repo, extResource2Revision, extResourse2SourcePath, extResource2DestPath)
 }
+
+func TestFlattenSpecFromCassette(t *testing.T) {
+	client := testCassetteClient(t, "pipeline_triggers")
+	ctx := context.Background()
+
+	permitRestart := false
+	pipeline := &cfclient.Pipeline{
+		Metadata: cfclient.Metadata{Name: pipelineNamePrefix + "cassette"},
+		Spec: cfclient.Spec{
+			Concurrency:                  2,
+			PermitRestartFromFailedSteps: &permitRestart,
+			RuntimeEnvironment:           cfclient.RuntimeEnvironment{Name: "system/default", Memory: "2Gi", CPU: "1000m"},
+			Triggers: []cfclient.Trigger{{
+				Name:        "commits",
+				Type:        "git",
+				Repo:        "codefresh-contrib/react-sample-app",
+				Provider:    "github",
+				Context:     "github",
+				Events:      []string{"push.heads"},
+				BranchRegex: "/^main$/",
+				Options:     &cfclient.TriggerOptions{NoCache: true},
+				Variables:   []cfclient.Variable{{Key: "TRIGGER_VAR", Value: "trigger"}},
+			}},
+			CronTriggers: []cfclient.CronTrigger{{
+				Name:       "nightly",
+				Type:       "cron",
+				Expression: "0 0 * * *",
+				Message:    "nightly",
+			}},
+		},
+	}
+	pipeline.SetVariables(map[string]interface{}{"PLAIN": "visible"}, false)
+	pipeline.SetVariables(map[string]interface{}{"SECRET": "s3cr3t"}, true)
+
+	created, err := client.CreatePipeline(ctx, pipeline)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() {
+		if err := client.DeletePipeline(ctx, created.Metadata.ID); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}()
+
+	read, err := client.GetPipeline(ctx, created.Metadata.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	spec := flattenSpec(read.Spec)[0]
+
+	if spec["concurrency"] != 2 || spec["permit_restart_from_failed_steps"] != false || spec["permit_restart_from_failed_steps_use_account_settings"] != false {
+		t.Fatalf("unexpected spec %v", spec)
+	}
+	if variables := spec["variables"].(map[string]string); variables["PLAIN"] != "visible" {
+		t.Fatalf("unexpected variables %v", variables)
+	}
+	if encrypted := spec["encrypted_variables"].(map[string]string); encrypted["SECRET"] == "" || encrypted["SECRET"] == "s3cr3t" {
+		t.Fatalf("expected the encrypted variable to be returned masked, got %v", encrypted)
+	}
+	if runtime := spec["runtime_environment"].([]map[string]interface{})[0]; runtime["name"] != "system/default" || runtime["memory"] != "2Gi" {
+		t.Fatalf("unexpected runtime environment %v", runtime)
+	}
+
+	triggers := spec["trigger"].([]map[string]interface{})
+	if len(triggers) != 1 {
+		t.Fatalf("expected one trigger, got %v", triggers)
+	}
+	trigger := triggers[0]
+	if trigger["name"] != "commits" || trigger["branch_regex"] != "/^main$/" || !reflect.DeepEqual(trigger["events"], []string{"push.heads"}) {
+		t.Fatalf("unexpected trigger %v", trigger)
+	}
+	if options := trigger["options"].([]map[string]interface{})[0]; options["no_cache"] != true || options["reset_volume"] != false {
+		t.Fatalf("unexpected trigger options %v", options)
+	}
+	if variables := trigger["variables"].(map[string]string); variables["TRIGGER_VAR"] != "trigger" {
+		t.Fatalf("unexpected trigger variables %v", variables)
+	}
+
+	cronTriggers := spec["cron_trigger"].([]map[string]interface{})
+	if len(cronTriggers) != 1 || cronTriggers[0]["expression"] != "0 0 * * *" || cronTriggers[0]["message"] != "nightly" {
+		t.Fatalf("unexpected cron triggers %v", cronTriggers)
+	}
+}
+
+func TestPipelineUpdateConflictsWithChangesMadeOutsideTerraform(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+	client := cfclient.NewClient(server.APIURL(), server.GraphQLURL(), fakeapi.APIKey, "")
+	ctx := context.Background()
+
+	pipelineResource := resourcePipeline()
+	pipelineData := schema.TestResourceDataRaw(t, pipelineResource.Schema, map[string]interface{}{
+		"name": "project/app",
+		"spec": []interface{}{map[string]interface{}{"concurrency": 1}},
+	})
+	if diags := pipelineResource.CreateContext(ctx, pipelineData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	// the updates are planned and applied like Terraform does, so that force_overwrite only changes in the update setting it
+	state := pipelineData.State()
+	apply := func(concurrency int, forceOverwrite bool) diag.Diagnostics {
+		t.Helper()
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":            "project/app",
+			"force_overwrite": forceOverwrite,
+			"spec":            []interface{}{map[string]interface{}{"concurrency": concurrency}},
+		})
+		diff, err := pipelineResource.Diff(ctx, state, config, client)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		newState, diags := pipelineResource.Apply(ctx, state, diff, client)
+		if !diags.HasError() {
+			state = newState
+		}
+		return diags
+	}
+	modifyOutsideTerraform := func(priority int) *cfclient.Pipeline {
+		t.Helper()
+		modified, err := client.GetPipeline(ctx, pipelineData.Id())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		modified.Spec.Priority = priority
+		modified, err = client.UpdatePipeline(ctx, modified)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return modified
+	}
+
+	// the pipeline is modified, e.g. in the UI, after it was read into state
+	modified := modifyOutsideTerraform(5)
+	diags := apply(2, false)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "was modified outside of Terraform") {
+		t.Fatalf("expected a conflict error, got %v", diags)
+	}
+	current, err := client.GetPipeline(ctx, pipelineData.Id())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current.Spec.Priority != 5 || current.Spec.Concurrency != 1 || current.Metadata.Revision != modified.Metadata.Revision {
+		t.Fatalf("expected the pipeline not to be overwritten, got %+v", current.Spec)
+	}
+
+	if diags := apply(2, true); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if state.Attributes["spec.0.concurrency"] != "2" || state.Attributes["revision"] != strconv.Itoa(current.Metadata.Revision+1) {
+		t.Fatalf("expected the pipeline to be overwritten, got revision %v", state.Attributes["revision"])
+	}
+
+	// only the update setting force_overwrite overwrites the pipeline
+	modifyOutsideTerraform(6)
+	diags = apply(3, true)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "was modified outside of Terraform") {
+		t.Fatalf("expected a conflict error, got %v", diags)
+	}
+
+	// once refreshed, the state is at the current revision again
+	state, diags = pipelineResource.RefreshWithoutUpgrade(ctx, state, client)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if diags := apply(3, false); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+}
+
+func TestPipelineEncryptedVariablesDriftIsDetected(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+	client := cfclient.NewClient(server.APIURL(), server.GraphQLURL(), fakeapi.APIKey, "")
+	ctx := context.Background()
+
+	pipelineResource := resourcePipeline()
+	pipelineData := schema.TestResourceDataRaw(t, pipelineResource.Schema, map[string]interface{}{
+		"name": "project/app",
+		"spec": []interface{}{map[string]interface{}{
+			"encrypted_variables": map[string]interface{}{"SECRET": "s3cr3t", "KEPT": "kept"},
+			"trigger": []interface{}{map[string]interface{}{
+				"name":                "commits",
+				"repo":                "org/app",
+				"encrypted_variables": map[string]interface{}{"TOKEN": "t0k3n"},
+			}},
+		}},
+	})
+	if diags := pipelineResource.CreateContext(ctx, pipelineData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	fingerprints := pipelineData.Get("encrypted_variables_fingerprints").(map[string]interface{})
+	if len(fingerprints) != 3 || !matchesEncryptedVariableFingerprint(fingerprints["trigger/commits/TOKEN"].(string), "t0k3n") {
+		t.Fatalf("unexpected fingerprints %v", fingerprints)
+	}
+	if pipelineData.Get("spec.0.encrypted_variables.SECRET") != "s3cr3t" || pipelineData.Get("spec.0.trigger.0.encrypted_variables.TOKEN") != "t0k3n" {
+		t.Fatalf("expected the encrypted variables to keep their configured values, got %v", pipelineData.Get("spec"))
+	}
+
+	// the secrets are rotated outside of Terraform
+	rotated, err := client.GetPipelineDecrypted(ctx, pipelineData.Id())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range rotated.Spec.Variables {
+		if rotated.Spec.Variables[i].Key == "SECRET" {
+			rotated.Spec.Variables[i].Value = "rotated"
+		}
+	}
+	rotated.Spec.Triggers[0].Variables[0].Value = "rotated"
+	if _, err := client.UpdatePipeline(ctx, rotated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diags := pipelineResource.ReadContext(ctx, pipelineData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if pipelineData.Get("spec.0.encrypted_variables.SECRET") != encryptedVariableDriftMarker || pipelineData.Get("spec.0.trigger.0.encrypted_variables.TOKEN") != encryptedVariableDriftMarker {
+		t.Fatalf("expected the rotated variables to be reported as drift, got %v", pipelineData.Get("spec"))
+	}
+	if pipelineData.Get("spec.0.encrypted_variables.KEPT") != "kept" {
+		t.Fatalf("expected the unchanged variable to keep its value, got %v", pipelineData.Get("spec.0.encrypted_variables"))
+	}
+
+	// applying the configuration sets the variables back
+	pipelineData.Set("spec", []interface{}{map[string]interface{}{
+		"encrypted_variables": map[string]interface{}{"SECRET": "s3cr3t", "KEPT": "kept"},
+		"trigger": []interface{}{map[string]interface{}{
+			"name":                "commits",
+			"repo":                "org/app",
+			"encrypted_variables": map[string]interface{}{"TOKEN": "t0k3n"},
+		}},
+	}})
+	if diags := pipelineResource.UpdateContext(ctx, pipelineData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if pipelineData.Get("spec.0.encrypted_variables.SECRET") != "s3cr3t" || pipelineData.Get("spec.0.trigger.0.encrypted_variables.TOKEN") != "t0k3n" {
+		t.Fatalf("expected the encrypted variables to be set back, got %v", pipelineData.Get("spec"))
+	}
+	if fingerprint := pipelineData.Get("encrypted_variables_fingerprints.KEPT"); fingerprint != fingerprints["KEPT"] {
+		t.Fatalf("expected the fingerprint of the unchanged variable to be kept, got %v", fingerprint)
+	}
+}
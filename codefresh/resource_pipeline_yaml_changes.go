@@ -0,0 +1,100 @@
+package codefresh
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// noSemanticYamlChange is the summary of a change of the pipeline YAML limited to comments, formatting or the order of fields
+const noSemanticYamlChange = "no semantic change"
+
+// priorValues reads the values of a diff before the change
+type priorValues struct {
+	d *schema.ResourceDiff
+}
+
+func (p priorValues) Get(key string) interface{} {
+	old, _ := p.d.GetChange(key)
+	return old
+}
+
+// resourcePipelineSummarizeYamlChanges sets yaml_change_summary to the steps, stages and variables changed by the plan,
+// so that reviewers do not have to compare the whole pipeline YAML.
+// It is set on every update, empty when the YAML does not change, so that the summary of a previous update is never shown again.
+func resourcePipelineSummarizeYamlChanges(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return d.SetNew("yaml_change_summary", "")
+	}
+	if !hasPipelineChanges(d) {
+		// no update is planned
+		return nil
+	}
+	if !d.NewValueKnown("original_yaml_string") {
+		return d.SetNewComputed("yaml_change_summary")
+	}
+
+	changes, err := summarizePipelineYamlChanges(d)
+	if err != nil {
+		log.Printf("[WARN] Unable to summarize the changes of the pipeline YAML: %v", err)
+		return d.SetNewComputed("yaml_change_summary")
+	}
+	if len(changes) == 0 {
+		return d.SetNew("yaml_change_summary", "")
+	}
+
+	// the revision the changes apply to makes the summaries of two updates with the same changes differ, so that the plan shows them
+	if revision := d.Get("revision").(int); revision > 0 {
+		changes = append([]string{fmt.Sprintf("changes to revision %d:", revision)}, changes...)
+	}
+	return d.SetNew("yaml_change_summary", strings.Join(changes, "\n"))
+}
+
+// pipelineComputedAttributes are the attributes of the pipeline only set by the provider
+var pipelineComputedAttributes = []string{"yaml_change_summary", "revision", "encrypted_variables_fingerprints"}
+
+// hasPipelineChanges returns whether the plan changes any attribute of the pipeline other than those computed by the provider
+func hasPipelineChanges(d *schema.ResourceDiff) bool {
+	for _, key := range d.GetChangedKeysPrefix("") {
+		if !slices.Contains(pipelineComputedAttributes, strings.SplitN(key, ".", 2)[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// summarizePipelineYamlChanges returns the changes of the pipeline YAML and variables made by the plan, one per line
+func summarizePipelineYamlChanges(d *schema.ResourceDiff) ([]string, error) {
+	if !d.HasChanges("original_yaml_string", "spec") {
+		return nil, nil
+	}
+
+	oldYamlString, newYamlString := d.GetChange("original_yaml_string")
+	oldGeneratedYaml, err := buildPipelineYaml(oldYamlString.(string), pipelineYamlBlocksFrom(priorValues{d}))
+	if err != nil {
+		return nil, err
+	}
+	newGeneratedYaml, err := buildPipelineYaml(newYamlString.(string), pipelineYamlBlocksFrom(d))
+	if err != nil {
+		// invalid blocks are reported by resourcePipelineCheckBlocks
+		return nil, nil
+	}
+
+	changes, err := schemautil.SummarizePipelineYamlChanges(oldGeneratedYaml, newGeneratedYaml)
+	if err != nil {
+		return nil, err
+	}
+
+	oldVariables, newVariables := d.GetChange("spec.0.variables")
+	changes = append(changes, schemautil.SummarizeMapChanges("variables", oldVariables.(map[string]interface{}), newVariables.(map[string]interface{}))...)
+
+	if len(changes) == 0 && oldGeneratedYaml != newGeneratedYaml {
+		changes = append(changes, noSemanticYamlChange)
+	}
+	return changes, nil
+}
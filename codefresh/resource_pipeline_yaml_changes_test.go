@@ -0,0 +1,101 @@
+package codefresh
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestPipelineYamlChangesAreSummarizedInThePlan(t *testing.T) {
+	pipelineResource := resourcePipeline()
+	oldConfig := map[string]interface{}{
+		"name":                 "project/app",
+		"original_yaml_string": "version: \"1.0\"\nmode: sequential\n",
+		"spec": []interface{}{map[string]interface{}{
+			"variables": map[string]interface{}{"KEPT": "1", "CHANGED": "1"},
+			"step": []interface{}{
+				map[string]interface{}{"name": "lint", "image": "alpine", "commands": []interface{}{"echo lint"}},
+				map[string]interface{}{"name": "test", "image": "alpine", "commands": []interface{}{"echo test"}},
+			},
+		}},
+	}
+	state := schema.TestResourceDataRaw(t, pipelineResource.Schema, oldConfig)
+	state.SetId("pipeline-id")
+
+	plan := func(t *testing.T, config map[string]interface{}) *terraform.InstanceDiff {
+		t.Helper()
+		diff, err := pipelineResource.Diff(context.Background(), state.State(), terraform.NewResourceConfigRaw(config), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return diff
+	}
+
+	diff := plan(t, map[string]interface{}{
+		"name":                 "project/app",
+		"original_yaml_string": "version: \"1.0\"\nmode: parallel\n",
+		"spec": []interface{}{map[string]interface{}{
+			"variables": map[string]interface{}{"KEPT": "1", "CHANGED": "2", "ADDED": "1"},
+			"step": []interface{}{
+				map[string]interface{}{"name": "build", "type": "build", "image_name": "org/app"},
+				map[string]interface{}{"name": "test", "image": "alpine:3.20", "commands": []interface{}{"echo test"}},
+			},
+		}},
+	})
+	expected := "steps added: build\nsteps removed: lint\nsteps modified: test\nfields modified: mode\nvariables added: ADDED\nvariables modified: CHANGED"
+	if summary := diff.Attributes["yaml_change_summary"]; summary == nil || summary.New != expected {
+		t.Fatalf("expected the summary %q, got %v", expected, summary)
+	}
+
+	diff = plan(t, map[string]interface{}{
+		"name":                 "project/app",
+		"original_yaml_string": "# reformatted\nmode: sequential\nversion: '1.0'\n",
+		"spec":                 oldConfig["spec"],
+	})
+	if summary := diff.Attributes["yaml_change_summary"]; summary == nil || summary.New != noSemanticYamlChange {
+		t.Fatalf("expected the summary %q, got %v", noSemanticYamlChange, summary)
+	}
+
+	diff = plan(t, map[string]interface{}{
+		"name":                 "project/renamed",
+		"original_yaml_string": oldConfig["original_yaml_string"],
+		"spec":                 oldConfig["spec"],
+	})
+	if summary, ok := diff.Attributes["yaml_change_summary"]; ok {
+		t.Fatalf("expected no summary when the YAML does not change, got %v", summary)
+	}
+
+	// the summary of the previous update is cleared, and the revision tells apart updates with the same changes
+	if err := state.Set("yaml_change_summary", "fields modified: mode"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := state.Set("revision", 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	diff = plan(t, map[string]interface{}{
+		"name":                 "project/renamed",
+		"original_yaml_string": oldConfig["original_yaml_string"],
+		"spec":                 oldConfig["spec"],
+	})
+	if summary := diff.Attributes["yaml_change_summary"]; summary == nil || summary.Old != "fields modified: mode" || summary.New != "" {
+		t.Fatalf("expected the previous summary to be cleared, got %v", summary)
+	}
+
+	diff = plan(t, map[string]interface{}{
+		"name":                 "project/app",
+		"original_yaml_string": "version: \"1.0\"\nmode: parallel\n",
+		"spec":                 oldConfig["spec"],
+	})
+	expected = "changes to revision 3:\nfields modified: mode"
+	if summary := diff.Attributes["yaml_change_summary"]; summary == nil || summary.New != expected {
+		t.Fatalf("expected the summary %q, got %v", expected, summary)
+	}
+
+	if diff := plan(t, oldConfig); diff != nil {
+		if summary, ok := diff.Attributes["yaml_change_summary"]; ok {
+			t.Fatalf("expected the summary to be kept when no update is planned, got %v", summary)
+		}
+	}
+}
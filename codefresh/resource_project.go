@@ -1,27 +1,30 @@
 package codefresh
 
 import (
-	"fmt"
+	"context"
 	"log"
 	"time"
 
 	"github.com/cenkalti/backoff"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceProject() *schema.Resource {
-	return &schema.Resource{
+	projectResource := &schema.Resource{
 		Description: `
 The top-level concept in Codefresh. You can create projects to group pipelines that are related.
 In most cases a single project will be a single application (that itself contains many micro-services).
 You are free to use projects as you see fit. For example, you could create a project for a specific Kubernetes cluster or a specific team/department.
 		`,
-		Create: resourceProjectCreate,
-		Read:   resourceProjectRead,
-		Update: resourceProjectUpdate,
-		Delete: resourceProjectDelete,
+		CreateContext: resourceProjectCreate,
+		ReadContext:   resourceProjectRead,
+		UpdateContext: resourceProjectUpdate,
+		DeleteContext: resourceProjectDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -48,7 +51,7 @@ You are free to use projects as you see fit. For example, you could create a pro
 				},
 			},
 			"encrypted_variables": {
-				Description: "Project level encrypted variables. Please note that drift will not be detected for encrypted variables",
+				Description: "Project level encrypted variables. Their drift is detected with `encrypted_variables_fingerprints`.",
 				Type:        schema.TypeMap,
 				Optional:    true,
 				Elem: &schema.Schema{
@@ -56,26 +59,35 @@ You are free to use projects as you see fit. For example, you could create a pro
 					Sensitive: true,
 				},
 			},
+			"encrypted_variables_fingerprints": encryptedVariablesFingerprintsSchema(),
 		},
 	}
+	// encrypted variables can be set without being stored in state
+	schemautil.AddWriteOnlyAttribute(projectResource.Schema, "", "encrypted_variables")
+	return projectResource
 }
 
-func resourceProjectCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
 	project := *mapResourceToProject(d)
 
-	resp, err := client.CreateProject(&project)
+	resp, err := client.CreateProject(ctx, &project)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(resp.ID)
 
+	err = setProjectEncryptedVariablesFingerprints(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
-func resourceProjectRead(d *schema.ResourceData, meta interface{}) error {
+func resourceProjectRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
@@ -85,47 +97,57 @@ func resourceProjectRead(d *schema.ResourceData, meta interface{}) error {
 		return nil
 	}
 
-	project, err := client.GetProjectByID(projectID)
+	project, err := client.GetProjectByIDDecrypted(ctx, projectID)
 	if err != nil {
-		return err
+		if cfclient.IsNotFound(err) {
+			log.Printf("[WARN] Project %s not found, removing it from state", projectID)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 
 	err = mapProjectToResource(project, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceProjectUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceProjectUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
 	project := *mapResourceToProject(d)
 
-	err := client.UpdateProject(&project)
+	err := client.UpdateProject(ctx, &project)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+
+	err = setProjectEncryptedVariablesFingerprints(d)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceProjectDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceProjectDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 	// Adding a Retry backoff to address eventual consistency for the API
 	expBackoff := backoff.NewExponentialBackOff()
 	expBackoff.MaxElapsedTime = 2 * time.Second
 	err := backoff.Retry(
 		func() error {
-			err := client.DeleteProject(d.Id())
+			err := client.DeleteProject(ctx, d.Id())
 			if err != nil {
 				log.Printf("Unable to destroy Project due to error %v", err)
 			}
 			return err
-		}, expBackoff)
+		}, backoff.WithContext(expBackoff, ctx))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -150,12 +172,16 @@ func mapProjectToResource(project *cfclient.Project, d *schema.ResourceData) err
 		return err
 	}
 
-	// Set encrypted vars from resource data to avoid constant diff
-	if len(encryptedVars) > 0 {
-		// Iterate over variables and set the value from resource data
-		for k := range encryptedVars {
-			encryptedVars[k] = d.Get(fmt.Sprintf("encrypted_variables.%s", k)).(string)
-		}
+	// Set encrypted vars from resource data to avoid constant diff, unless their decrypted value shows that they were changed outside of Terraform
+	fingerprints := newEncryptedVariablesFingerprints(d)
+	if schemautil.UsesWriteOnly(d, "encrypted_variables") {
+		encryptedVars = map[string]string{}
+	} else {
+		fingerprints.read(d, "", "encrypted_variables", encryptedVars)
+	}
+	err = fingerprints.set(d)
+	if err != nil {
+		return err
 	}
 
 	err = d.Set("encrypted_variables", encryptedVars)
@@ -176,7 +202,14 @@ func mapResourceToProject(d *schema.ResourceData) *cfclient.Project {
 	}
 	variables := d.Get("variables").(map[string]interface{})
 	project.SetVariables(variables, false)
-	encryptedVariables := d.Get("encrypted_variables").(map[string]interface{})
+	encryptedVariables := schemautil.GetMapOrWriteOnly(d, "encrypted_variables")
 	project.SetVariables(encryptedVariables, true)
 	return project
 }
+
+// setProjectEncryptedVariablesFingerprints records the fingerprints of the encrypted variables of the project that were applied
+func setProjectEncryptedVariablesFingerprints(d *schema.ResourceData) error {
+	fingerprints := newEncryptedVariablesFingerprints(d)
+	fingerprints.apply("", d.Get("encrypted_variables").(map[string]interface{}))
+	return fingerprints.set(d)
+}
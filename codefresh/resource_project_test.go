@@ -1,13 +1,16 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"testing"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/fakeapi"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -86,7 +89,7 @@ func TestAccCodefreshProject_Variables(t *testing.T) {
 				ResourceName:            resourceName,
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"encrypted_variables"},
+				ImportStateVerifyIgnore: []string{"encrypted_variables", "encrypted_variables_fingerprints"},
 			},
 			{
 				Config: testAccCodefreshProjectBasicConfigVariables(name, "var1", "val1_updated", "var2", "val2_updated", "encvar1", "encvar1_updated"),
@@ -115,7 +118,7 @@ func testAccCheckCodefreshProjectExists(resource string) resource.TestCheckFunc
 		projectID := rs.Primary.ID
 
 		apiClient := testAccProvider.Meta().(*cfclient.Client)
-		_, err := apiClient.GetProjectByID(projectID)
+		_, err := apiClient.GetProjectByID(context.Background(), projectID)
 
 		if err != nil {
 			return fmt.Errorf("error fetching project with resource %s. %s", resource, err)
@@ -133,7 +136,7 @@ func testAccCheckCodefreshProjectDestroy(s *terraform.State) error {
 			continue
 		}
 
-		_, err := apiClient.GetProjectByID(rs.Primary.ID)
+		_, err := apiClient.GetProjectByID(context.Background(), rs.Primary.ID)
 
 		if err == nil {
 			return fmt.Errorf("Alert still exists")
@@ -185,3 +188,77 @@ resource "codefresh_project" "test" {
 }
 `, rName, var1Name, var1Value, var2Name, var2Value, encrytedVar1Name, encrytedVar1Value)
 }
+
+func TestProjectEncryptedVariablesDriftIsDetected(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+	client := cfclient.NewClient(server.APIURL(), server.GraphQLURL(), fakeapi.APIKey, "")
+	ctx := context.Background()
+
+	projectResource := resourceProject()
+	projectData := schema.TestResourceDataRaw(t, projectResource.Schema, map[string]interface{}{
+		"name":                "project",
+		"encrypted_variables": map[string]interface{}{"SECRET": "s3cr3t"},
+	})
+	if diags := projectResource.CreateContext(ctx, projectData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if diags := projectResource.ReadContext(ctx, projectData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if projectData.Get("encrypted_variables.SECRET") != "s3cr3t" {
+		t.Fatalf("expected the encrypted variable to keep its configured value, got %v", projectData.Get("encrypted_variables"))
+	}
+
+	// the secret is rotated outside of Terraform
+	rotated, err := client.GetProjectByIDDecrypted(ctx, projectData.Id())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rotated.Variables[0].Value = "rotated"
+	if err := client.UpdateProject(ctx, rotated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diags := projectResource.ReadContext(ctx, projectData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if projectData.Get("encrypted_variables.SECRET") != encryptedVariableDriftMarker {
+		t.Fatalf("expected the rotated variable to be reported as drift, got %v", projectData.Get("encrypted_variables"))
+	}
+}
+
+func TestProjectEncryptedVariablesCanBeWriteOnly(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+	client := cfclient.NewClient(server.APIURL(), server.GraphQLURL(), fakeapi.APIKey, "")
+	ctx := context.Background()
+
+	projectResource := resourceProject()
+	projectData := testApplyWithWriteOnly(t, projectResource, map[string]interface{}{
+		"name":                           "project",
+		"encrypted_variables_wo_version": 1,
+	}, map[string]interface{}{
+		"name":                           "project",
+		"encrypted_variables_wo":         `{"SECRET":"s3cr3t"}`,
+		"encrypted_variables_wo_version": 1,
+	}, client)
+
+	project, err := client.GetProjectByIDDecrypted(ctx, projectData.Id())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(project.Variables) != 1 || project.Variables[0].Key != "SECRET" || project.Variables[0].Value != "s3cr3t" {
+		t.Fatalf("expected the write-only encrypted variable to be sent to Codefresh, got %v", project.Variables)
+	}
+
+	if diags := projectResource.ReadContext(ctx, projectData, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if len(projectData.Get("encrypted_variables").(map[string]interface{})) != 0 || len(projectData.Get("encrypted_variables_fingerprints").(map[string]interface{})) != 0 {
+		t.Fatalf("expected the write-only encrypted variable not to be stored in state, got %v", projectData.State().Attributes)
+	}
+	if projectData.Get("encrypted_variables_wo_version") != 1 {
+		t.Fatalf("expected encrypted_variables_wo_version to be kept, got %v", projectData.Get("encrypted_variables_wo_version"))
+	}
+}
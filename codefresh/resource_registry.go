@@ -1,10 +1,13 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -28,13 +31,25 @@ var providers = []string{
 	providerBintray,
 }
 
+// registrySecrets are the sensitive attributes of each provider block of a registry
+var registrySecrets = map[string]string{
+	providerAcr:       "client_secret",
+	providerGcr:       "keyfile",
+	providerGar:       "keyfile",
+	providerEcr:       "secret_access_key",
+	providerBintray:   "token",
+	providerDockerhub: "password",
+	providerOther:     "password",
+}
+
 func resourceRegistry() *schema.Resource {
-	return &schema.Resource{
-		Description: "Registry is the configuration that Codefresh uses to push/pull container images.",
-		Create:      resourceRegistryCreate,
-		Read:        resourceRegistryRead,
-		Update:      resourceRegistryUpdate,
-		Delete:      resourceRegistryDelete,
+	registryResource := &schema.Resource{
+		Description:   "Registry is the configuration that Codefresh uses to push/pull container images.",
+		CreateContext: resourceRegistryCreate,
+		ReadContext:   resourceRegistryRead,
+		UpdateContext: resourceRegistryUpdate,
+		DeleteContext: resourceRegistryDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -303,22 +318,28 @@ func resourceRegistry() *schema.Resource {
 			},
 		},
 	}
+	// secrets can be set without being stored in state
+	spec := registryResource.Schema["spec"].Elem.(*schema.Resource).Schema
+	for provider, secret := range registrySecrets {
+		schemautil.AddWriteOnlyAttribute(spec[provider].Elem.(*schema.Resource).Schema, fmt.Sprintf("spec.0.%s.0.", provider), secret)
+	}
+	return registryResource
 }
 
-func resourceRegistryCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceRegistryCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
-	resp, err := client.CreateRegistry(mapResourceToRegistry(d))
+	resp, err := client.CreateRegistry(ctx, mapResourceToRegistry(d))
 	if err != nil {
 		log.Printf("[DEBUG] Error while creating registry. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(resp.Id)
-	return resourceRegistryRead(d, meta)
+	return resourceRegistryRead(ctx, d, meta)
 }
 
-func resourceRegistryRead(d *schema.ResourceData, meta interface{}) error {
+func resourceRegistryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
 	registryId := d.Id()
@@ -328,44 +349,49 @@ func resourceRegistryRead(d *schema.ResourceData, meta interface{}) error {
 		return nil
 	}
 
-	registry, err := client.GetRegistry(registryId)
+	registry, err := client.GetRegistry(ctx, registryId)
 	if err != nil {
+		if cfclient.IsNotFound(err) {
+			log.Printf("[WARN] Registry %s not found, removing it from state", registryId)
+			d.SetId("")
+			return nil
+		}
 		log.Printf("[DEBUG] Error while getting registry. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 	}
 
 	err = mapRegistryToResource(*registry, d)
 	if err != nil {
 		log.Printf("[DEBUG] Error while mapping registry to resource. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceRegistryUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceRegistryUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
 	registry := *mapResourceToRegistry(d)
 	registry.Id = d.Id()
 
-	_, err := client.UpdateRegistry(&registry)
+	_, err := client.UpdateRegistry(ctx, &registry)
 	if err != nil {
 		log.Printf("[DEBUG] Error while updating registry. Error = %v", err)
-		return err
+		return diag.FromErr(err)
 	}
 
-	return resourceRegistryRead(d, meta)
+	return resourceRegistryRead(ctx, d, meta)
 }
 
-func resourceRegistryDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceRegistryDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
-	err := client.DeleteRegistry(d.Id())
+	err := client.DeleteRegistry(ctx, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -387,7 +413,7 @@ func mapResourceToRegistry(d *schema.ResourceData) *cfclient.Registry {
 		registry.Provider = providerAcr
 		registry.Domain = d.Get(providerKey + ".0.domain").(string)
 		registry.ClientId = d.Get(providerKey + ".0.client_id").(string)
-		registry.ClientSecret = d.Get(providerKey + ".0.client_secret").(string)
+		registry.ClientSecret = schemautil.GetStringOrWriteOnly(d, providerKey+".0.client_secret")
 		registry.RepositoryPrefix = d.Get(providerKey + ".0.repository_prefix").(string)
 		return registry
 	}
@@ -397,7 +423,7 @@ func mapResourceToRegistry(d *schema.ResourceData) *cfclient.Registry {
 		registry.Provider = providerEcr
 		registry.Region = d.Get(providerKey + ".0.region").(string)
 		registry.AccessKeyId = d.Get(providerKey + ".0.access_key_id").(string)
-		registry.SecretAccessKey = d.Get(providerKey + ".0.secret_access_key").(string)
+		registry.SecretAccessKey = schemautil.GetStringOrWriteOnly(d, providerKey+".0.secret_access_key")
 		registry.RepositoryPrefix = d.Get(providerKey + ".0.repository_prefix").(string)
 		return registry
 	}
@@ -406,7 +432,7 @@ func mapResourceToRegistry(d *schema.ResourceData) *cfclient.Registry {
 	if _, ok := d.GetOk(providerKey); ok {
 		registry.Provider = providerGcr
 		registry.Domain = d.Get(providerKey + ".0.domain").(string)
-		registry.Keyfile = d.Get(providerKey + ".0.keyfile").(string)
+		registry.Keyfile = schemautil.GetStringOrWriteOnly(d, providerKey+".0.keyfile")
 		registry.RepositoryPrefix = d.Get(providerKey + ".0.repository_prefix").(string)
 		return registry
 	}
@@ -415,7 +441,7 @@ func mapResourceToRegistry(d *schema.ResourceData) *cfclient.Registry {
 	if _, ok := d.GetOk(providerKey); ok {
 		registry.Provider = providerGar
 		registry.Domain = d.Get(providerKey + ".0.location").(string)
-		registry.Keyfile = d.Get(providerKey + ".0.keyfile").(string)
+		registry.Keyfile = schemautil.GetStringOrWriteOnly(d, providerKey+".0.keyfile")
 		registry.RepositoryPrefix = d.Get(providerKey + ".0.repository_prefix").(string)
 		return registry
 	}
@@ -424,7 +450,7 @@ func mapResourceToRegistry(d *schema.ResourceData) *cfclient.Registry {
 	if _, ok := d.GetOk(providerKey); ok {
 		registry.Provider = providerDockerhub
 		registry.Username = d.Get(providerKey + ".0.username").(string)
-		registry.Password = d.Get(providerKey + ".0.password").(string)
+		registry.Password = schemautil.GetStringOrWriteOnly(d, providerKey+".0.password")
 		return registry
 	}
 
@@ -433,7 +459,7 @@ func mapResourceToRegistry(d *schema.ResourceData) *cfclient.Registry {
 		registry.Provider = providerBintray
 		registry.Domain = d.Get(providerKey + ".0.domain").(string)
 		registry.Username = d.Get(providerKey + ".0.username").(string)
-		registry.Token = d.Get(providerKey + ".0.token").(string)
+		registry.Token = schemautil.GetStringOrWriteOnly(d, providerKey+".0.token")
 		registry.RepositoryPrefix = d.Get(providerKey + ".0.repository_prefix").(string)
 		return registry
 	}
@@ -443,7 +469,7 @@ func mapResourceToRegistry(d *schema.ResourceData) *cfclient.Registry {
 		registry.Provider = providerOther
 		registry.Domain = d.Get(providerKey + ".0.domain").(string)
 		registry.Username = d.Get(providerKey + ".0.username").(string)
-		registry.Password = d.Get(providerKey + ".0.password").(string)
+		registry.Password = schemautil.GetStringOrWriteOnly(d, providerKey+".0.password")
 		registry.BehindFirewall = d.Get(providerKey + ".0.behind_firewall").(bool)
 		registry.RepositoryPrefix = d.Get(providerKey + ".0.repository_prefix").(string)
 		return registry
@@ -484,95 +510,64 @@ func mapRegistryToResource(registry cfclient.Registry, d *schema.ResourceData) e
 		return err
 	}
 
-	if registry.Provider == providerAcr {
-		err = d.Set(fmt.Sprintf("spec.0.%v.0", providerAcr), map[string]interface{}{
+	var block map[string]interface{}
+	switch registry.Provider {
+	case providerAcr:
+		block = map[string]interface{}{
 			"domain":            registry.Domain,
 			"client_id":         registry.ClientId,
 			"client_secret":     registry.ClientSecret,
 			"repository_prefix": registry.RepositoryPrefix,
-		})
-
-		if err != nil {
-			return err
 		}
-	}
-
-	if registry.Provider == providerEcr {
-		err = d.Set(fmt.Sprintf("spec.0.%v.0", providerEcr), map[string]interface{}{
+	case providerEcr:
+		block = map[string]interface{}{
 			"region":            registry.Domain,
 			"access_key_id":     registry.AccessKeyId,
 			"secret_access_key": registry.SecretAccessKey,
 			"repository_prefix": registry.RepositoryPrefix,
-		})
-
-		if err != nil {
-			return err
 		}
-	}
-
-	if registry.Provider == providerGcr {
-		err = d.Set(fmt.Sprintf("spec.0.%v.0", providerGcr), map[string]interface{}{
+	case providerGcr:
+		block = map[string]interface{}{
 			"domain":            registry.Domain,
 			"keyfile":           registry.Keyfile,
 			"repository_prefix": registry.RepositoryPrefix,
-		})
-
-		if err != nil {
-			return err
 		}
-	}
-
-	if registry.Provider == providerGar {
-		err = d.Set(fmt.Sprintf("spec.0.%v.0", providerGcr), map[string]interface{}{
+	case providerGar:
+		block = map[string]interface{}{
 			"location":          registry.Domain,
 			"keyfile":           registry.Keyfile,
 			"repository_prefix": registry.RepositoryPrefix,
-		})
-
-		if err != nil {
-			return err
 		}
-	}
-
-	if registry.Provider == providerBintray {
-		err = d.Set(fmt.Sprintf("spec.0.%v.0", providerBintray), map[string]interface{}{
+	case providerBintray:
+		block = map[string]interface{}{
 			"domain":            registry.Domain,
 			"username":          registry.Username,
 			"token":             registry.Token,
 			"repository_prefix": registry.RepositoryPrefix,
-		})
-
-		if err != nil {
-			return err
 		}
-	}
-
-	if registry.Provider == providerDockerhub {
-		err = d.Set(fmt.Sprintf("spec.0.%v.0", providerDockerhub), map[string]interface{}{
+	case providerDockerhub:
+		block = map[string]interface{}{
 			"username": registry.Username,
 			"password": registry.Password,
-		})
-
-		if err != nil {
-			return err
 		}
-	}
-
-	if registry.Provider == providerOther {
-		err = d.Set(fmt.Sprintf("spec.0.%v.0", providerOther), map[string]interface{}{
+	case providerOther:
+		block = map[string]interface{}{
 			"domain":            registry.Domain,
 			"username":          registry.Username,
 			"password":          registry.Password,
 			"behind_firewall":   registry.BehindFirewall,
 			"repository_prefix": registry.RepositoryPrefix,
-		})
-
-		if err != nil {
-			return err
 		}
+	default:
+		return nil
 	}
 
-	return nil
+	// the secret is kept out of state when it is set with its write-only alternative, along with the version of the latter
+	secretPath := fmt.Sprintf("spec.0.%v.0.%v", registry.Provider, registrySecrets[registry.Provider])
+	block[registrySecrets[registry.Provider]] = schemautil.SecretToKeep(d, secretPath, block[registrySecrets[registry.Provider]])
+	block[registrySecrets[registry.Provider]+"_wo_version"] = d.Get(secretPath + "_wo_version")
+
+	return d.Set(fmt.Sprintf("spec.0.%v.0", registry.Provider), block)
 }
 
 func getConflictingProviders(arr []string, exclude string) []string {
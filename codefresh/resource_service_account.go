@@ -1,20 +1,25 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
+	"log"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceServiceAccount() *schema.Resource {
 	return &schema.Resource{
-		Description: "A service account is an identity that provides automated processes, applications, and services with the necessary permissions to interact securely with the Codefresh platform",
-		Create:      resourceServiceAccountCreate,
-		Read:        resourceServiceAccountRead,
-		Update:      resourceServiceAccountUpdate,
-		Delete:      resourceServiceAccountDelete,
+		Description:   "A service account is an identity that provides automated processes, applications, and services with the necessary permissions to interact securely with the Codefresh platform",
+		CreateContext: resourceServiceAccountCreate,
+		ReadContext:   resourceServiceAccountRead,
+		UpdateContext: resourceServiceAccountUpdate,
+		DeleteContext: resourceServiceAccountDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -42,14 +47,14 @@ func resourceServiceAccount() *schema.Resource {
 	}
 }
 
-func resourceServiceAccountCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceServiceAccountCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
 	newSerivceAccount := *mapResourceToServiceAccount(d)
 
-	resp, err := client.CreateServiceUser(&newSerivceAccount)
+	resp, err := client.CreateServiceUser(ctx, &newSerivceAccount)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(resp.ID)
@@ -57,7 +62,7 @@ func resourceServiceAccountCreate(d *schema.ResourceData, meta interface{}) erro
 	return nil
 }
 
-func resourceServiceAccountRead(d *schema.ResourceData, meta interface{}) error {
+func resourceServiceAccountRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
@@ -68,41 +73,46 @@ func resourceServiceAccountRead(d *schema.ResourceData, meta interface{}) error
 		return nil
 	}
 
-	serviceAccount, err := client.GetServiceUserByID(serviceAccountID)
+	serviceAccount, err := client.GetServiceUserByID(ctx, serviceAccountID)
 
 	if err != nil {
-		return err
+		if cfclient.IsNotFound(err) {
+			log.Printf("[WARN] Service account %s not found, removing it from state", serviceAccountID)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 
 	err = mapServiceAccountToResource(serviceAccount, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceServiceAccountUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceServiceAccountUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
 	updateServiceAccount := *mapResourceToServiceAccount(d)
 
-	_, err := client.UpdateServiceUser(&updateServiceAccount)
+	_, err := client.UpdateServiceUser(ctx, &updateServiceAccount)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceServiceAccountDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceServiceAccountDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
-	err := client.DeleteServiceUser(d.Id())
+	err := client.DeleteServiceUser(ctx, d.Id())
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
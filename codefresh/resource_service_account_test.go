@@ -1,6 +1,7 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"testing"
@@ -55,7 +56,7 @@ func testAccCheckCodefreshServiceUserExists(resource string) resource.TestCheckF
 		serviceUserID := rs.Primary.ID
 
 		apiClient := testAccProvider.Meta().(*cfclient.Client)
-		_, err := apiClient.GetServiceUserByID(serviceUserID)
+		_, err := apiClient.GetServiceUserByID(context.Background(), serviceUserID)
 
 		if err != nil {
 			return fmt.Errorf("error fetching serviceUser with resource %s. %s", resource, err)
@@ -90,7 +91,7 @@ func testAccCheckCodefreshServiceUserAssignedToTeam(serviceUserResource string,
 		teamID := teamState.Primary.ID
 
 		apiClient := testAccProvider.Meta().(*cfclient.Client)
-		serviceUser, err := apiClient.GetServiceUserByID(serviceUserID)
+		serviceUser, err := apiClient.GetServiceUserByID(context.Background(), serviceUserID)
 
 		if err != nil {
 			return fmt.Errorf("error fetching serviceUser with resource %s. %s", serviceUserID, err)
@@ -122,7 +123,7 @@ func testAccCheckCodefreshServiceUserDestroy(s *terraform.State) error {
 			continue
 		}
 
-		_, err := apiClient.GetServiceUserByID(rs.Primary.ID)
+		_, err := apiClient.GetServiceUserByID(context.Background(), rs.Primary.ID)
 
 		if err == nil {
 			return fmt.Errorf("Alert still exists")
@@ -29,6 +29,7 @@ The resource allows to handle the life-cycle of the version by allowing specifyi
 		ReadContext:   resourceStepTypesRead,
 		UpdateContext: resourceStepTypesUpdate,
 		DeleteContext: resourceStepTypesDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -119,7 +120,7 @@ func resourceStepTypesCreate(ctx context.Context, d *schema.ResourceData, meta i
 	for _, version := range orderedVersions {
 		step := mapVersion[version.String()]
 		log.Printf("[DEBUG] Version for create: %q. StepSpec: %v", version, step.Spec.Steps)
-		_, err := client.CreateStepTypes(&step)
+		_, err := client.CreateStepTypes(ctx, &step)
 		if err != nil {
 			return diag.Errorf("[DEBUG] Error while creating step types OnCreate. Error = %v", err)
 		}
@@ -139,7 +140,7 @@ func resourceStepTypesRead(ctx context.Context, d *schema.ResourceData, meta int
 	}
 
 	//Extracting the step just based on the name to validate it exists
-	stepTypes, err := client.GetStepTypes(stepTypesIdentifier)
+	stepTypes, err := client.GetStepTypes(ctx, stepTypesIdentifier)
 	if err != nil {
 		log.Printf("[DEBUG] Step Not found %v. Error = %v", stepTypesIdentifier, err)
 		d.SetId("")
@@ -155,7 +156,7 @@ func resourceStepTypesRead(ctx context.Context, d *schema.ResourceData, meta int
 		version := step.(map[string]interface{})["version_number"].(string)
 		log.Printf("[DEBUG] Get step version FromList %v", version)
 		if version != "" {
-			stepTypes, err := client.GetStepTypes(stepTypesIdentifier + ":" + version)
+			stepTypes, err := client.GetStepTypes(ctx, stepTypesIdentifier+":"+version)
 			log.Printf("[DEBUG] Get step version %v", version)
 			if err != nil {
 				log.Printf("[DEBUG] StepVersion not found %v. Error = %v", stepTypesIdentifier+":"+version, err)
@@ -205,7 +206,7 @@ func resourceStepTypesUpdate(ctx context.Context, d *schema.ResourceData, meta i
 		versionNumber := version.VersionNumber
 		versionsDefined[versionNumber] = versionNumber
 
-		_, err := client.GetStepTypes(name + ":" + versionNumber)
+		_, err := client.GetStepTypes(ctx, name+":"+versionNumber)
 		cleanUpStepFromTransientValues(&version.StepTypes, name, versionNumber)
 		if err != nil {
 			// If an error occured during Get, we assume step doesn't exist
@@ -213,7 +214,7 @@ func resourceStepTypesUpdate(ctx context.Context, d *schema.ResourceData, meta i
 			mapVersionToCreate[versionNumber] = version.StepTypes
 		} else {
 			log.Printf("[DEBUG] Update Version step: %q", versionNumber)
-			_, err := client.UpdateStepTypes(&version.StepTypes)
+			_, err := client.UpdateStepTypes(ctx, &version.StepTypes)
 			if err != nil {
 				return diag.Errorf("[DEBUG] Error while updating stepTypes. Error = %v", err)
 			}
@@ -231,7 +232,7 @@ func resourceStepTypesUpdate(ctx context.Context, d *schema.ResourceData, meta i
 	for _, version := range orderedVersions {
 		step := mapVersionToCreate[version.String()]
 		log.Printf("[DEBUG] Creating version %s for step types: %s", step.Metadata["version"], step.Metadata["name"])
-		_, err := client.CreateStepTypes(&step)
+		_, err := client.CreateStepTypes(ctx, &step)
 		if err != nil {
 			return diag.Errorf("[DEBUG] Error while creating step types OnUpdate function. Error = %v", err)
 		}
@@ -242,7 +243,7 @@ func resourceStepTypesUpdate(ctx context.Context, d *schema.ResourceData, meta i
 		if _, ok := versionsDefined[version]; !ok {
 			log.Printf("[DEBUG] Deleting version: %s", version)
 			// If not defined we remove from the system
-			err := client.DeleteStepTypes(d.Id() + ":" + version)
+			err := client.DeleteStepTypes(ctx, d.Id()+":"+version)
 			if err != nil {
 				return diag.Errorf("[DEBUG] Error while deleting step_types_versions. Error = %v", err)
 			}
@@ -256,7 +257,7 @@ func resourceStepTypesDelete(ctx context.Context, d *schema.ResourceData, meta i
 
 	client := meta.(*cfclient.Client)
 	log.Printf("[DEBUG] Deleting step type: %s", d.Id())
-	err := client.DeleteStepTypes(d.Id())
+	err := client.DeleteStepTypes(ctx, d.Id())
 	if err != nil {
 		return diag.Errorf("[DEBUG] Error while deleting step_types %s. Error = %v", d.Id(), err)
 	}
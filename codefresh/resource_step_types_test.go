@@ -1,6 +1,7 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -104,7 +105,7 @@ func TestAccCodefreshStepTypes(t *testing.T) {
 	if os.Getenv("TF_ACC") == "1" {
 		apiClient := testAccProvider.Meta().(*cfclient.Client)
 		var accountName string
-		if account, err := apiClient.GetCurrentAccount(); err == nil {
+		if account, err := apiClient.GetCurrentAccount(context.Background()); err == nil {
 			accountName = account.Name
 		} else {
 			log.Fatalf("Error, unable to retrieve current account name: %s", err)
@@ -167,7 +168,7 @@ func testAccCheckCodefreshStepTypesExists(resource string) resource.TestCheckFun
 		stepTypeID := rs.Primary.ID
 
 		apiClient := testAccProvider.Meta().(*cfclient.Client)
-		_, err := apiClient.GetStepTypes(stepTypeID)
+		_, err := apiClient.GetStepTypes(context.Background(), stepTypeID)
 
 		if err != nil {
 			return fmt.Errorf("error fetching step types with resource %s. %s", resource, err)
@@ -185,7 +186,7 @@ func testAccCheckCodefreshStepTypesDestroy(s *terraform.State) error {
 			continue
 		}
 
-		_, err := apiClient.GetStepTypes(rs.Primary.ID)
+		_, err := apiClient.GetStepTypes(context.Background(), rs.Primary.ID)
 
 		if err == nil {
 			return fmt.Errorf("Alert still exists")
@@ -1,20 +1,25 @@
 package codefresh
 
 import (
+	"context"
 	"fmt"
+	"log"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceTeam() *schema.Resource {
 	return &schema.Resource{
-		Description: "Teams are groups of users that are used to enforce access control.",
-		Create:      resourceTeamCreate,
-		Read:        resourceTeamRead,
-		Update:      resourceTeamUpdate,
-		Delete:      resourceTeamDelete,
+		Description:   "Teams are groups of users that are used to enforce access control.",
+		CreateContext: resourceTeamCreate,
+		ReadContext:   resourceTeamRead,
+		UpdateContext: resourceTeamUpdate,
+		DeleteContext: resourceTeamDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -59,14 +64,14 @@ The type of the team. Possible values:
 	}
 }
 
-func resourceTeamCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceTeamCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
 	team := *mapResourceToTeam(d)
 
-	resp, err := client.CreateTeam(&team)
+	resp, err := client.CreateTeam(ctx, &team)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(resp.ID)
@@ -74,13 +79,13 @@ func resourceTeamCreate(d *schema.ResourceData, meta interface{}) error {
 	err = d.Set("account_id", resp.Account)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceTeamRead(d *schema.ResourceData, meta interface{}) error {
+func resourceTeamRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
@@ -90,32 +95,38 @@ func resourceTeamRead(d *schema.ResourceData, meta interface{}) error {
 		return nil
 	}
 
-	team, err := client.GetTeamByID(teamID)
+	team, err := client.GetTeamByID(ctx, teamID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+
+	if team == nil {
+		log.Printf("[WARN] Team %s not found, removing it from state", teamID)
+		d.SetId("")
+		return nil
 	}
 
 	err = mapTeamToResource(team, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceTeamUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceTeamUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
 	team := *mapResourceToTeam(d)
 
 	// Rename
-	err := client.RenameTeam(team.ID, team.Name)
+	err := client.RenameTeam(ctx, team.ID, team.Name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Update users
-	existingTeam, err := client.GetTeamByID(team.ID)
+	existingTeam, err := client.GetTeamByID(ctx, team.ID)
 	if err != nil {
 		return nil
 	}
@@ -125,28 +136,28 @@ func resourceTeamUpdate(d *schema.ResourceData, meta interface{}) error {
 	usersToAdd, usersToDelete := cfclient.GetUsersDiff(datautil.ConvertStringArr(desiredUsers), existingTeam.Users)
 
 	for _, userId := range usersToDelete {
-		err := client.DeleteUserFromTeam(team.ID, userId)
+		err := client.DeleteUserFromTeam(ctx, team.ID, userId)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	for _, userId := range usersToAdd {
-		err := client.AddUserToTeam(team.ID, userId)
+		err := client.AddUserToTeam(ctx, team.ID, userId)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	return nil
 }
 
-func resourceTeamDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceTeamDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*cfclient.Client)
 
-	err := client.DeleteTeam(d.Id())
+	err := client.DeleteTeam(ctx, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
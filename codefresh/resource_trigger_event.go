@@ -0,0 +1,209 @@
+package codefresh
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/triggertypes"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceTriggerEvent() *schema.Resource {
+	return &schema.Resource{
+		Description:   "This resource manages a Hermes trigger event, such as a registry push, a Helm chart push or a custom event, that pipelines can be triggered by with the `codefresh_pipeline_event_trigger` resource. For cron events, use the `codefresh_pipeline_cron_trigger` resource or the `cron_trigger` attribute of the `codefresh_pipeline` resource, which create the event along with the trigger of the pipeline.",
+		CreateContext: resourceTriggerEventCreate,
+		ReadContext:   resourceTriggerEventRead,
+		DeleteContext: resourceTriggerEventDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourceTriggerEventCustomizeDiff,
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{
+			validateTriggerEventType,
+		},
+		// The Codefresh API does not support updating trigger events
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Description: "The type of the event, for example `registry`, `helm` or `custom`. See the `codefresh_trigger_types` data source for the supported types and kinds.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"kind": {
+				Description: "The kind of the event within its type, for example `dockerhub`, `quay`, `azure`, `jfrog` or `ecr` for registry events.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"filter": {
+				Description: "An optional filter of the event.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"values": {
+				Description: "The values identifying the event, which depend on its type and kind. For example `namespace`, `name` and `action` for Docker Hub push events.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"secret": {
+				Description: "The secret the external system must send along with the event. Generated by Codefresh if not set.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Sensitive:   true,
+			},
+			"uri": {
+				Description: "The URI of the event, used as `event` by the `codefresh_pipeline_event_trigger` resource.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"endpoint": {
+				Description: "The webhook URL the external system must call to send the event.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"account": {
+				Description: "The ID of the account the event belongs to.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// validateTriggerEventType warns about a type and kind missing from the catalog of trigger types
+func validateTriggerEventType(ctx context.Context, req schema.ValidateResourceConfigFuncRequest, resp *schema.ValidateResourceConfigFuncResponse) {
+	if req.RawConfig.IsNull() || !req.RawConfig.IsKnown() {
+		return
+	}
+	eventType := req.RawConfig.GetAttr("type")
+	kind := req.RawConfig.GetAttr("kind")
+	if !eventType.IsKnown() || eventType.IsNull() || !kind.IsKnown() || kind.IsNull() {
+		return
+	}
+	resp.Diagnostics = append(resp.Diagnostics, triggertypes.UnknownTypeWarning(eventType.AsString(), kind.AsString(), cty.GetAttrPath("kind"))...)
+}
+
+// resourceTriggerEventCustomizeDiff checks the values of a known type and kind against the catalog of trigger types
+func resourceTriggerEventCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("type") || !d.NewValueKnown("kind") || !d.NewValueKnown("values") {
+		return nil
+	}
+
+	eventType := d.Get("type").(string)
+	kind := d.Get("kind").(string)
+	triggerType, ok := triggertypes.Find(eventType, kind)
+	if !ok {
+		return nil
+	}
+
+	values := d.Get("values").(map[string]interface{})
+	for _, value := range triggerType.Values {
+		if _, ok := values[value]; !ok {
+			return fmt.Errorf("trigger events of type %q and kind %q require the values %v", eventType, kind, triggerType.Values)
+		}
+	}
+
+	return nil
+}
+
+func resourceTriggerEventCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*cfclient.Client)
+
+	uri, err := client.CreateHermesTriggerEvent(ctx, mapResourceToTriggerEvent(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(uri)
+
+	return resourceTriggerEventRead(ctx, d, meta)
+}
+
+func resourceTriggerEventRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*cfclient.Client)
+
+	event, err := client.GetHermesTriggerEvent(ctx, d.Id())
+	if err != nil {
+		if cfclient.IsNotFound(err) {
+			log.Printf("[WARN] Trigger event %s not found, removing it from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	err = mapTriggerEventToResource(event, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceTriggerEventDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*cfclient.Client)
+
+	err := client.DeleteHermesTriggerEvent(ctx, d.Id())
+	if err != nil && !cfclient.IsNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func mapTriggerEventToResource(event *cfclient.HermesTriggerEvent, d *schema.ResourceData) error {
+	attributes := map[string]interface{}{
+		"type":     event.Type,
+		"kind":     event.Kind,
+		"filter":   event.Filter,
+		"uri":      d.Id(),
+		"endpoint": event.Endpoint,
+		"account":  event.Account,
+	}
+	// Values are only returned by some kinds of events, keep the configured ones otherwise
+	if len(event.Values) > 0 {
+		attributes["values"] = event.Values
+	}
+	// The secret is not returned when it is masked
+	if event.Secret != "" {
+		attributes["secret"] = event.Secret
+	}
+
+	for key, value := range attributes {
+		err := d.Set(key, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func mapResourceToTriggerEvent(d *schema.ResourceData) *cfclient.HermesTriggerEvent {
+	event := &cfclient.HermesTriggerEvent{
+		Type:   d.Get("type").(string),
+		Kind:   d.Get("kind").(string),
+		Filter: d.Get("filter").(string),
+		Secret: d.Get("secret").(string),
+		Values: datautil.ConvertStringMap(d.Get("values").(map[string]interface{})),
+	}
+	if event.Secret == "" {
+		event.Secret = "!generate"
+	}
+
+	return event
+}
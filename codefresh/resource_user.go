@@ -1,21 +1,25 @@
 package codefresh
 
 import (
+	"context"
 	"errors"
 	"log"
 
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
 	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/datautil"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/schemautil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceUser() *schema.Resource {
 	return &schema.Resource{
-		Description: "This resource is used to manage a Codefresh user. Requires a Codefresh admin token and applies only to Codefresh on-premises installations.",
-		Create:      resourceUsersCreate,
-		Read:        resourceUsersRead,
-		Update:      resourceUsersUpdate,
-		Delete:      resourceUsersDelete,
+		Description:   "This resource is used to manage a Codefresh user. Requires a Codefresh admin token and applies only to Codefresh on-premises installations.",
+		CreateContext: resourceUsersCreate,
+		ReadContext:   resourceUsersRead,
+		UpdateContext: resourceUsersUpdate,
+		DeleteContext: resourceUsersDelete,
+		Timeouts:      schemautil.DefaultResourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -138,44 +142,44 @@ func resourceUser() *schema.Resource {
 	}
 }
 
-func resourceUsersCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceUsersCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
 	user := mapResourceToNewUser(d)
 
-	resp, err := client.AddPendingUser(user)
+	resp, err := client.AddPendingUser(ctx, user)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(resp.ID)
 
 	// Adding user to users teams
 	for _, accountID := range user.Account {
-		_ = client.AddUserToTeamByAdmin(resp.ID, accountID, "users")
+		_ = client.AddUserToTeamByAdmin(ctx, resp.ID, accountID, "users")
 	}
 
 	if d.Get("activate").(bool) {
-		err := client.ActivateUser(d.Id())
+		err := client.ActivateUser(ctx, d.Id())
 
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	if d.Get("password") != "" {
-		err := client.UpdateLocalUserPassword(d.Get("user_name").(string), d.Get("password").(string))
+		err := client.UpdateLocalUserPassword(ctx, d.Get("user_name").(string), d.Get("password").(string))
 
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
-	return resourceUsersRead(d, meta)
+	return resourceUsersRead(ctx, d, meta)
 }
 
-func resourceUsersRead(d *schema.ResourceData, meta interface{}) error {
+func resourceUsersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*cfclient.Client)
 
@@ -186,20 +190,25 @@ func resourceUsersRead(d *schema.ResourceData, meta interface{}) error {
 		return nil
 	}
 
-	user, err := client.GetUserByID(userId)
+	user, err := client.GetUserByID(ctx, userId)
 	if err != nil {
-		return err
+		if cfclient.IsNotFound(err) {
+			log.Printf("[WARN] User %s not found, removing it from state", userId)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 
 	err = mapUserToResource(*user, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceUsersUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceUsersUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	// only accounts list
 
 	client := meta.(*cfclient.Client)
@@ -208,32 +217,32 @@ func resourceUsersUpdate(d *schema.ResourceData, meta interface{}) error {
 
 	userId := d.Id()
 
-	accounts, err := client.GetAccountsList(datautil.ConvertStringArr(accountList))
+	accounts, err := client.GetAccountsList(ctx, datautil.ConvertStringArr(accountList))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	err = client.UpdateUserAccounts(userId, *accounts)
+	err = client.UpdateUserAccounts(ctx, userId, *accounts)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Adding user to users teams
 	for _, account := range *accounts {
-		_ = client.AddUserToTeamByAdmin(userId, account.ID, "users")
+		_ = client.AddUserToTeamByAdmin(ctx, userId, account.ID, "users")
 	}
 
 	// Update local password
-	err = updateUserLocalPassword(d, client)
+	err = updateUserLocalPassword(ctx, d, client)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return resourceUsersRead(d, meta)
+	return resourceUsersRead(ctx, d, meta)
 }
 
-func resourceUsersDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceUsersDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	// To research
 	// it's impossible sometimes to delete user - limit of runtimes or collaborators should be increased.
@@ -242,9 +251,9 @@ func resourceUsersDelete(d *schema.ResourceData, meta interface{}) error {
 
 	userName := d.Get("user_name").(string)
 
-	err := client.DeleteUser(userName)
+	err := client.DeleteUser(ctx, userName)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -402,7 +411,7 @@ func mapResourceToNewUser(d *schema.ResourceData) *cfclient.NewUser {
 	return user
 }
 
-func updateUserLocalPassword(d *schema.ResourceData, client *cfclient.Client) error {
+func updateUserLocalPassword(ctx context.Context, d *schema.ResourceData, client *cfclient.Client) error {
 
 	if d.HasChange("password") {
 		hasPassword := d.Get("has_password").(bool)
@@ -414,14 +423,14 @@ func updateUserLocalPassword(d *schema.ResourceData, client *cfclient.Client) er
 		userName := d.Get("user_name").(string)
 
 		if password := d.Get("password"); password != "" {
-			err := client.UpdateLocalUserPassword(userName, password.(string))
+			err := client.UpdateLocalUserPassword(ctx, userName, password.(string))
 
 			if err != nil {
 				return err
 			}
 			// If password is not set but has_password returns true, it means that it was removed
 		} else if hasPassword {
-			err := client.DeleteLocalUserPassword(userName)
+			err := client.DeleteLocalUserPassword(ctx, userName)
 
 			if err != nil {
 				return err
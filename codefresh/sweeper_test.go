@@ -0,0 +1,289 @@
+package codefresh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/cfclient"
+	"github.com/codefresh-io/terraform-provider-codefresh/codefresh/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// sweepNamePrefixes are the prefixes of the names given to the objects created by the acceptance tests.
+// Sweepers only delete objects whose name, without the project or account part, starts with one of them.
+var sweepNamePrefixes = []string{"TerraformAccTest_", "tf-test-"}
+
+// The sweepers are run with `make sweep` or `go test ./codefresh -v -sweep=all`, the region is not used by Codefresh
+func init() {
+	resource.AddTestSweepers("codefresh_pipeline", &resource.Sweeper{
+		Name: "codefresh_pipeline",
+		F:    sweepPipelines,
+	})
+	resource.AddTestSweepers("codefresh_project", &resource.Sweeper{
+		Name:         "codefresh_project",
+		Dependencies: []string{"codefresh_pipeline"},
+		F:            sweepProjects,
+	})
+	resource.AddTestSweepers("codefresh_context", &resource.Sweeper{
+		Name:         "codefresh_context",
+		Dependencies: []string{"codefresh_pipeline"},
+		F:            sweepContexts,
+	})
+	resource.AddTestSweepers("codefresh_registry", &resource.Sweeper{
+		Name:         "codefresh_registry",
+		Dependencies: []string{"codefresh_pipeline"},
+		F:            sweepRegistries,
+	})
+	resource.AddTestSweepers("codefresh_step_types", &resource.Sweeper{
+		Name:         "codefresh_step_types",
+		Dependencies: []string{"codefresh_pipeline"},
+		F:            sweepStepTypes,
+	})
+	resource.AddTestSweepers("codefresh_service_account", &resource.Sweeper{
+		Name: "codefresh_service_account",
+		F:    sweepServiceAccounts,
+	})
+	resource.AddTestSweepers("codefresh_team", &resource.Sweeper{
+		Name:         "codefresh_team",
+		Dependencies: []string{"codefresh_service_account"},
+		F:            sweepTeams,
+	})
+	resource.AddTestSweepers("codefresh_account_idp", &resource.Sweeper{
+		Name: "codefresh_account_idp",
+		F:    sweepAccountIDPs,
+	})
+	resource.AddTestSweepers("codefresh_idp", &resource.Sweeper{
+		Name: "codefresh_idp",
+		F:    sweepIDPs,
+	})
+}
+
+// sweeperClient returns a client configured like the provider, from the environment and the Codefresh CLI config
+func sweeperClient() (*cfclient.Client, error) {
+	provider := Provider()
+	if diags := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{})); diags.HasError() {
+		return nil, fmt.Errorf("cannot configure the provider: %v", diags)
+	}
+	return provider.Meta().(*cfclient.Client), nil
+}
+
+// isSweepable returns whether name, or its part after the last slash, was given by an acceptance test
+func isSweepable(name string) bool {
+	baseName := name[strings.LastIndex(name, "/")+1:]
+	for _, prefix := range sweepNamePrefixes {
+		if strings.HasPrefix(name, prefix) || strings.HasPrefix(baseName, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sweep deletes the objects of list whose name is sweepable, it keeps going on errors and returns them all
+func sweep[T any](kind string, list func(ctx context.Context, client *cfclient.Client) ([]T, error), name func(T) string, remove func(ctx context.Context, client *cfclient.Client, object T) error) error {
+	ctx := context.Background()
+	client, err := sweeperClient()
+	if err != nil {
+		return err
+	}
+	objects, err := list(ctx, client)
+	if err != nil {
+		return fmt.Errorf("cannot list %s: %w", kind, err)
+	}
+
+	var errs []error
+	for _, object := range objects {
+		if !isSweepable(name(object)) {
+			continue
+		}
+		log.Printf("[INFO] Sweeping %s %s", kind, name(object))
+		if err := remove(ctx, client, object); err != nil && !cfclient.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("cannot delete %s %s: %w", kind, name(object), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sweepPipelines(region string) error {
+	return sweep("pipeline",
+		func(ctx context.Context, client *cfclient.Client) ([]cfclient.Pipeline, error) {
+			pipelines, err := client.GetPipelines(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return *pipelines, nil
+		},
+		func(pipeline cfclient.Pipeline) string { return pipeline.Metadata.Name },
+		func(ctx context.Context, client *cfclient.Client, pipeline cfclient.Pipeline) error {
+			return client.DeletePipeline(ctx, pipeline.GetID())
+		})
+}
+
+func sweepProjects(region string) error {
+	return sweep("project",
+		func(ctx context.Context, client *cfclient.Client) ([]cfclient.Project, error) {
+			return client.GetProjects(ctx)
+		},
+		func(project cfclient.Project) string { return project.ProjectName },
+		func(ctx context.Context, client *cfclient.Client, project cfclient.Project) error {
+			return client.DeleteProject(ctx, project.ID)
+		})
+}
+
+func sweepContexts(region string) error {
+	return sweep("context",
+		func(ctx context.Context, client *cfclient.Client) ([]cfclient.Context, error) {
+			return client.GetContexts(ctx)
+		},
+		func(context cfclient.Context) string { return context.Metadata.Name },
+		func(ctx context.Context, client *cfclient.Client, context cfclient.Context) error {
+			return client.DeleteContext(ctx, context.Metadata.Name)
+		})
+}
+
+func sweepRegistries(region string) error {
+	return sweep("registry",
+		func(ctx context.Context, client *cfclient.Client) ([]cfclient.Registry, error) {
+			return client.GetRegistries(ctx)
+		},
+		func(registry cfclient.Registry) string { return registry.Name },
+		func(ctx context.Context, client *cfclient.Client, registry cfclient.Registry) error {
+			return client.DeleteRegistry(ctx, registry.Id)
+		})
+}
+
+func sweepStepTypes(region string) error {
+	return sweep("step types",
+		func(ctx context.Context, client *cfclient.Client) ([]cfclient.StepTypes, error) {
+			return client.GetStepTypesList(ctx)
+		},
+		func(stepTypes cfclient.StepTypes) string {
+			name, _ := stepTypes.Metadata["name"].(string)
+			return name
+		},
+		func(ctx context.Context, client *cfclient.Client, stepTypes cfclient.StepTypes) error {
+			// deleting by name removes every version
+			return client.DeleteStepTypes(ctx, stepTypes.GetID())
+		})
+}
+
+func sweepServiceAccounts(region string) error {
+	return sweep("service account",
+		func(ctx context.Context, client *cfclient.Client) ([]cfclient.ServiceUser, error) {
+			return client.GetServiceUserList(ctx)
+		},
+		func(serviceUser cfclient.ServiceUser) string { return serviceUser.Name },
+		func(ctx context.Context, client *cfclient.Client, serviceUser cfclient.ServiceUser) error {
+			return client.DeleteServiceUser(ctx, serviceUser.ID)
+		})
+}
+
+func sweepTeams(region string) error {
+	return sweep("team",
+		func(ctx context.Context, client *cfclient.Client) ([]cfclient.Team, error) {
+			return client.GetTeamList(ctx)
+		},
+		func(team cfclient.Team) string { return team.Name },
+		func(ctx context.Context, client *cfclient.Client, team cfclient.Team) error {
+			return client.DeleteTeam(ctx, team.ID)
+		})
+}
+
+func sweepAccountIDPs(region string) error {
+	return sweep("account IDP",
+		func(ctx context.Context, client *cfclient.Client) ([]cfclient.IDP, error) {
+			idps, err := client.GetAccountIDPs(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return *idps, nil
+		},
+		func(idp cfclient.IDP) string { return idp.DisplayName },
+		func(ctx context.Context, client *cfclient.Client, idp cfclient.IDP) error {
+			return client.DeleteIDPAccount(ctx, idp.ID)
+		})
+}
+
+func sweepIDPs(region string) error {
+	return sweep("IDP",
+		func(ctx context.Context, client *cfclient.Client) ([]cfclient.IDP, error) {
+			idps, err := client.GetIDPs(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return *idps, nil
+		},
+		func(idp cfclient.IDP) string { return idp.DisplayName },
+		func(ctx context.Context, client *cfclient.Client, idp cfclient.IDP) error {
+			// global IDPs, without accounts, are never created by the acceptance tests
+			if len(idp.Accounts) == 0 {
+				return nil
+			}
+			return client.DeleteIDP(ctx, idp.ID)
+		})
+}
+
+func TestSweepersDeleteAcceptanceTestObjects(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(ENV_CODEFRESH_API_URL, server.APIURL())
+	t.Setenv(ENV_CODEFRESH_API2_URL, server.GraphQLURL())
+	t.Setenv(ENV_CODEFRESH_API_KEY, fakeapi.APIKey)
+
+	ctx := context.Background()
+	client := cfclient.NewClient(server.APIURL(), server.GraphQLURL(), fakeapi.APIKey, "")
+	for _, name := range []string{"TerraformAccTest_project/TerraformAccTest_pipeline", "production/deploy"} {
+		if _, err := client.CreatePipeline(ctx, &cfclient.Pipeline{Metadata: cfclient.Metadata{Name: name}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for _, name := range []string{"TerraformAccTest_project", "production"} {
+		if _, err := client.CreateProject(ctx, &cfclient.Project{ProjectName: name}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for _, name := range []string{"TerraformAccTest_cf ctx/test", "production"} {
+		if _, err := client.CreateContext(ctx, &cfclient.Context{Metadata: cfclient.ContextMetadata{Name: name}, Spec: cfclient.ContextSpec{Type: "config"}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for _, name := range []string{"TerraformAccTest_team", "developers"} {
+		if _, err := client.CreateTeam(ctx, &cfclient.Team{Name: name}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for _, sweeper := range []func(string) error{sweepPipelines, sweepProjects, sweepContexts, sweepTeams} {
+		if err := sweeper(""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	pipelines, _ := client.GetPipelines(ctx)
+	projects, _ := client.GetProjects(ctx)
+	contexts, _ := client.GetContexts(ctx)
+	teams, _ := client.GetTeamList(ctx)
+	remaining := []string{}
+	for _, pipeline := range *pipelines {
+		remaining = append(remaining, pipeline.Metadata.Name)
+	}
+	for _, project := range projects {
+		remaining = append(remaining, project.ProjectName)
+	}
+	for _, context := range contexts {
+		remaining = append(remaining, context.Metadata.Name)
+	}
+	for _, team := range teams {
+		remaining = append(remaining, team.Name)
+	}
+	expected := []string{"production/deploy", "production", "production", "developers"}
+	if !slices.Equal(remaining, expected) {
+		t.Fatalf("expected only %v to be kept, got %v", expected, remaining)
+	}
+}